@@ -0,0 +1,36 @@
+// combo-lint 检查内置溢价组合的一致性，并可将组合的包含关系导出为 Graphviz DOT 文件
+//
+// 用法示例:
+//
+//	combo-lint
+//	combo-lint -dot docs/combos.dot
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval/newrule"
+)
+
+func main() {
+	dot := flag.String("dot", "", "DOT 输出文件，为空时只输出检查结果")
+	flag.Parse()
+
+	combos := newrule.New().Rules().Combos
+	issues := newrule.LintCombos(combos)
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	fmt.Printf("共 %d 个组合，%d 个问题\n", len(combos), len(issues))
+
+	if *dot == "" {
+		return
+	}
+	if err := os.WriteFile(*dot, []byte(newrule.BuildComboLattice(combos).DOT()), 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Println(*dot)
+}
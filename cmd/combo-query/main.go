@@ -0,0 +1,68 @@
+// combo-query 查询已加载的溢价组合目录，并可说明各组合在指定账号上的命中情况
+//
+// 用法示例:
+//
+//	combo-query -char 哥伦比娅
+//	combo-query -char 丝柯克,玛薇卡 -min-const 6 -min-value 1000
+//	combo-query -group 月国满命溢价组合 -account account.json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+	"github.com/sdojjy/genshin-value-rule/pkg/eval/newrule"
+)
+
+func main() {
+	var (
+		chars       = flag.String("char", "", "组合需包含的角色，多个角色以逗号分隔")
+		minConst    = flag.Int("min-const", 0, "角色在组合中要求的命座下限")
+		minValue    = flag.Float64("min-value", 0, "组合价值下限")
+		maxValue    = flag.Float64("max-value", 0, "组合价值上限，0 表示不限")
+		group       = flag.String("group", "", "组合分组")
+		ascending   = flag.Bool("asc", false, "按价值升序排列")
+		accountPath = flag.String("account", "", "账号 JSON 文件，指定时说明各组合在该账号上的状态")
+	)
+	flag.Parse()
+
+	q := newrule.ComboQuery{
+		MinConst:  *minConst,
+		MinValue:  *minValue,
+		MaxValue:  *maxValue,
+		Group:     *group,
+		Ascending: *ascending,
+	}
+	if *chars != "" {
+		q.Characters = strings.Split(*chars, ",")
+	}
+
+	rule := newrule.New()
+	combos := rule.QueryCombos(q)
+	if *accountPath == "" {
+		for _, combo := range combos {
+			fmt.Printf("%8.0f  [%s] %s\n", combo.Value, combo.Group, combo.Name)
+		}
+		fmt.Printf("共 %d 个组合\n", len(combos))
+		return
+	}
+
+	data, err := os.ReadFile(*accountPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	var account eval.Assets
+	if err := json.Unmarshal(data, &account); err != nil {
+		fmt.Fprintf(os.Stderr, "解析账号文件失败: %v\n", err)
+		os.Exit(1)
+	}
+	for _, status := range rule.ExplainCombos(account, combos) {
+		fmt.Println(status)
+	}
+	fmt.Printf("共 %d 个组合\n", len(combos))
+}
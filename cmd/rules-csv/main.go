@@ -0,0 +1,65 @@
+// rules-csv 将估值规则中的价格表、组合与分档表导出为 CSV，或将编辑后的 CSV 导入为规则集文档
+//
+// 用法示例:
+//
+//	rules-csv export -dir rules/
+//	rules-csv import -dir rules/ -o ruleset.json
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval/newrule"
+	"github.com/sdojjy/genshin-value-rule/pkg/rulecsv"
+	"github.com/sdojjy/genshin-value-rule/pkg/schema"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "用法: rules-csv export|import -dir 目录 [-o 规则集文件]")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	fs := flag.NewFlagSet(os.Args[1], flag.ExitOnError)
+	dir := fs.String("dir", "rules", "CSV 所在目录")
+	out := fs.String("o", "", "import: 规则集文档输出文件，为空时只校验")
+	fs.Parse(os.Args[2:])
+
+	rules := newrule.New().Rules()
+	switch os.Args[1] {
+	case "export":
+		if err := rulecsv.ExportDir(*dir, rules); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Printf("已导出到 %s\n", *dir)
+	case "import":
+		imported, err := rulecsv.ImportDir(*dir, rules)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Printf("角色 %d，武器 %d，组合 %d，角色数量档位 %d，资源档位 %d\n",
+			len(imported.Characters), len(imported.Weapons), len(imported.Combos),
+			len(imported.CharCountMultiplierTiers), len(imported.ResourceValueTiers))
+		if *out == "" {
+			return
+		}
+		data, err := schema.EncodeRuleSet(imported)
+		if err == nil {
+			err = os.WriteFile(*out, data, 0o644)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Printf("已写入 %s\n", *out)
+	default:
+		usage()
+	}
+}
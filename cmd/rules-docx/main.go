@@ -0,0 +1,62 @@
+// rules-docx 从 Word 价格文档中提取价格表与组合，显示与当前规则的差异，并可输出为规则集文档
+//
+// 用法示例:
+//
+//	rules-docx 估值规则.docx
+//	rules-docx -o ruleset.json 估值规则.docx
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval/newrule"
+	"github.com/sdojjy/genshin-value-rule/pkg/ruledoc"
+	"github.com/sdojjy/genshin-value-rule/pkg/schema"
+)
+
+func main() {
+	out := flag.String("o", "", "规则集文档输出文件，为空时只显示差异")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "用法: rules-docx [-o 规则集文件] 价格文档.docx")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	doc, err := ruledoc.OpenDocx(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	current := newrule.New().Rules()
+	imported, report := ruledoc.Extract(doc, current)
+	fmt.Print(report)
+
+	changes := ruledoc.Diff(current, imported)
+	if len(changes) == 0 {
+		fmt.Println("与当前规则一致")
+	} else {
+		fmt.Printf("与当前规则相比有 %d 处差异:\n", len(changes))
+		for _, c := range changes {
+			fmt.Println(" ", c)
+		}
+	}
+
+	if *out == "" {
+		return
+	}
+	data, err := schema.EncodeRuleSet(imported)
+	if err == nil {
+		err = os.WriteFile(*out, data, 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	fmt.Printf("已写入 %s\n", *out)
+}
@@ -0,0 +1,38 @@
+// schema-gen 由 Go 类型生成账号、规则集与估值结果文档的 JSON Schema
+//
+// 用法示例:
+//
+//	schema-gen -out docs/schema
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/schema"
+)
+
+func main() {
+	out := flag.String("out", "docs/schema", "输出目录")
+	flag.Parse()
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for _, d := range schema.Documents {
+		data, err := schema.Generate(d)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "生成 %s 失败: %v\n", d.File, err)
+			os.Exit(1)
+		}
+		path := filepath.Join(*out, d.File)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println(path)
+	}
+}
@@ -5,14 +5,22 @@ type AccountEvaluator interface {
 }
 
 type Assets struct {
-	Characters     map[string]int
-	Weapons        map[string]int
-	YuanShi        int
-	JiuChanZhiYuan int
-	YellowCount    int
+	Characters     map[string]int `json:"characters"`
+	Weapons        map[string]int `json:"weapons"`
+	YuanShi        int            `json:"yuanShi,omitempty"`
+	JiuChanZhiYuan int            `json:"jiuChanZhiYuan,omitempty"`
+	YellowCount    int            `json:"yellowCount,omitempty"`
+
+	// 卡池状态
+	CharacterPity       int  `json:"characterPity,omitempty"`       // 角色池当前已垫抽数
+	CharacterGuaranteed bool `json:"characterGuaranteed,omitempty"` // 角色池是否大保底
+	WeaponPity          int  `json:"weaponPity,omitempty"`          // 武器池当前已垫抽数
+	WeaponGuaranteed    bool `json:"weaponGuaranteed,omitempty"`    // 武器池是否大保底
+	FatePoints          int  `json:"fatePoints,omitempty"`          // 武器池命定值
 }
 
 type ValuationResult struct {
-	FinalTotal float64 `json:"finalTotal"`
-	Breakdown  string  `json:"breakdown"`
+	FinalTotal  float64 `json:"finalTotal"`
+	Breakdown   string  `json:"breakdown"`
+	RuleVersion string  `json:"ruleVersion,omitempty"` // 所用规则的版本号
 }
@@ -0,0 +1,220 @@
+package newrule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+// ComboQuery 是组合目录的查询条件，零值表示不限
+type ComboQuery struct {
+	Characters []string // 组合需包含全部这些角色 (固定要求或替代要求组的选项)
+	MinConst   int      // 上述角色在组合中要求的命座下限不低于此值；未指定角色时要求组合中任一角色满足
+	MinValue   float64
+	MaxValue   float64
+	Group      string // 组合分组，如 "月国满命溢价组合"
+	Ascending  bool   // 按价值升序排列，默认降序
+}
+
+// comboRequirementFor 返回组合对角色的要求，替代要求组中的选项同样计入
+func comboRequirementFor(combo ComboRule, name string) (RequiredChar, bool) {
+	for _, req := range combo.RequiredChars {
+		if req.Name == name {
+			return req, true
+		}
+	}
+	for _, group := range combo.RequiredGroups {
+		for _, opt := range group.Options {
+			if opt.Name == name {
+				return opt, true
+			}
+		}
+	}
+	return RequiredChar{}, false
+}
+
+// Match 判断组合是否满足查询条件
+func (q ComboQuery) Match(combo ComboRule) bool {
+	if q.Group != "" && combo.Group != q.Group {
+		return false
+	}
+	if q.MinValue > 0 && combo.Value < q.MinValue {
+		return false
+	}
+	if q.MaxValue > 0 && combo.Value > q.MaxValue {
+		return false
+	}
+	for _, name := range q.Characters {
+		req, ok := comboRequirementFor(combo, name)
+		if !ok || req.MinConst < q.MinConst {
+			return false
+		}
+	}
+	if len(q.Characters) == 0 && q.MinConst > 0 {
+		for _, req := range combo.RequiredChars {
+			if req.MinConst >= q.MinConst {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// QueryCombos 在已加载的组合中查询，结果按价值排序，同价值按名称排序
+func (n *NewRule) QueryCombos(q ComboQuery) []ComboRule {
+	var result []ComboRule
+	for _, combo := range n.rules.Combos {
+		if q.Match(combo) {
+			result = append(result, combo)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Value != result[j].Value {
+			return (result[i].Value < result[j].Value) == q.Ascending
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// 组合在账号上的状态
+const (
+	ComboMet     = "已命中"  // 满足且被最优方案选用
+	ComboBlocked = "被占用"  // 满足但角色或武器已被最优方案中的其他组合使用
+	ComboMissing = "缺少要求" // 账号不满足组合要求
+)
+
+// ComboStatus 是组合在账号上的状态及说明
+type ComboStatus struct {
+	Combo   ComboRule
+	Status  string
+	Details []string
+}
+
+func (s ComboStatus) String() string {
+	line := fmt.Sprintf("[%s] %s (%.0f)", s.Status, s.Combo.Name, s.Combo.Value)
+	if len(s.Details) > 0 {
+		line += ": " + strings.Join(s.Details, "; ")
+	}
+	return line
+}
+
+// ExplainCombos 说明给定组合在账号上的状态: 被最优方案选用、被其他组合占用，或缺少哪些要求
+func (n *NewRule) ExplainCombos(account eval.Assets, combos []ComboRule) []ComboStatus {
+	satisfied := findSatisfiedCombos(account, n.rules)
+	_, best := findBestComboSelection(satisfied, make(map[string]bool))
+
+	chosen := make(map[string]ComboRule, len(best))
+	users := make(map[string]string) // 角色或武器 -> 使用它的最优方案组合
+	for _, combo := range best {
+		chosen[combo.Name] = combo
+		for _, req := range combo.RequiredChars {
+			users[req.Name] = combo.Name
+		}
+		for _, req := range combo.RequiredWeapons {
+			users[weaponKey(req.Name)] = combo.Name
+		}
+	}
+	variants := make(map[string][]ComboRule)
+	for _, combo := range satisfied {
+		variants[combo.Name] = append(variants[combo.Name], combo)
+	}
+
+	statuses := make([]ComboStatus, 0, len(combos))
+	for _, combo := range combos {
+		if c, ok := chosen[combo.Name]; ok {
+			status := ComboStatus{Combo: c, Status: ComboMet}
+			if len(c.MatchedAlternatives) > 0 {
+				names := make([]string, 0, len(c.MatchedAlternatives))
+				for _, req := range c.MatchedAlternatives {
+					names = append(names, req.String())
+				}
+				status.Details = append(status.Details, "选用 "+strings.Join(names, ", "))
+			}
+			if len(c.SharedChars) > 0 {
+				status.Details = append(status.Details, "共享 "+strings.Join(c.SharedChars, ", "))
+			}
+			statuses = append(statuses, status)
+			continue
+		}
+		if vs, ok := variants[combo.Name]; ok {
+			statuses = append(statuses, ComboStatus{Combo: combo, Status: ComboBlocked, Details: blockingDetails(vs[0], users)})
+			continue
+		}
+		statuses = append(statuses, ComboStatus{Combo: combo, Status: ComboMissing, Details: missingRequirements(combo, account, n.rules)})
+	}
+	return statuses
+}
+
+// blockingDetails 列出组合中已被最优方案其他组合使用的角色与武器
+func blockingDetails(combo ComboRule, users map[string]string) []string {
+	var details []string
+	for _, req := range combo.RequiredChars {
+		if user, ok := users[req.Name]; ok {
+			details = append(details, fmt.Sprintf("%s 已用于 [%s]", req.Name, user))
+		}
+	}
+	for _, req := range combo.RequiredWeapons {
+		if user, ok := users[weaponKey(req.Name)]; ok {
+			details = append(details, fmt.Sprintf("%s 已用于 [%s]", req.Name, user))
+		}
+	}
+	if len(details) == 0 {
+		details = append(details, "选用后总附加价值更低")
+	}
+	return details
+}
+
+// describeOwned 描述账号中角色的当前命座
+func describeOwned(account eval.Assets, name string) string {
+	if c, ok := account.Characters[name]; ok {
+		return fmt.Sprintf("当前%d命", c)
+	}
+	return "未拥有"
+}
+
+// missingRequirements 列出账号未满足的组合要求
+func missingRequirements(combo ComboRule, account eval.Assets, set *ruleSet) []string {
+	var missing []string
+	for _, req := range combo.RequiredChars {
+		if !req.Satisfied(account) {
+			missing = append(missing, fmt.Sprintf("需要 %s (%s)", req, describeOwned(account, req.Name)))
+		}
+	}
+	for _, group := range combo.RequiredGroups {
+		met := 0
+		var options []string
+		for _, opt := range group.Options {
+			if opt.Satisfied(account) {
+				met++
+			}
+			options = append(options, opt.String())
+		}
+		if met < group.MinMatch {
+			missing = append(missing, fmt.Sprintf("需要 %s 中的 %d 个 (满足 %d 个)", strings.Join(options, "/"), group.MinMatch, met))
+		}
+	}
+	for _, attr := range combo.RequiredAttributes {
+		if got := len(attr.candidates(account, set.Characters)); got < attr.Count {
+			missing = append(missing, fmt.Sprintf("需要 %s (满足 %d 个)", attr, got))
+		}
+	}
+	for _, req := range combo.RequiredWeapons {
+		if !req.Satisfied(account) {
+			missing = append(missing, fmt.Sprintf("需要 %s", req))
+		}
+	}
+	if combo.Condition != "" {
+		if !set.conditions[combo.Condition].Matches(account) {
+			missing = append(missing, fmt.Sprintf("条件 %q 不成立", combo.Condition))
+		}
+	}
+	if len(missing) == 0 {
+		// 各项要求单独满足，但无法同时满足 (如属性要求与固定要求争用同一角色)
+		missing = append(missing, "各项要求无法同时满足")
+	}
+	return missing
+}
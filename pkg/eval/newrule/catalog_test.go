@@ -0,0 +1,56 @@
+package newrule
+
+import (
+	"testing"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+func TestQueryCombos(t *testing.T) {
+	rule := New()
+	combos := rule.QueryCombos(ComboQuery{Characters: []string{"哥伦比娅"}, MinConst: 6, Group: "月国满命溢价组合"})
+	if len(combos) == 0 {
+		t.Fatal("Expected combos requiring 6哥伦比娅")
+	}
+	for i, combo := range combos {
+		req, ok := comboRequirementFor(combo, "哥伦比娅")
+		if !ok || req.MinConst < 6 || combo.Group != "月国满命溢价组合" {
+			t.Errorf("Combo %s does not match the query", combo.Name)
+		}
+		if i > 0 && combo.Value > combos[i-1].Value {
+			t.Errorf("Expected descending order by value")
+		}
+	}
+
+	ranged := rule.QueryCombos(ComboQuery{MinValue: 100, MaxValue: 100, Ascending: true})
+	for _, combo := range ranged {
+		if combo.Value != 100 {
+			t.Errorf("Combo %s outside the value range", combo.Name)
+		}
+	}
+}
+
+func TestExplainCombos(t *testing.T) {
+	c6 := func(name string) RequiredChar { return RequiredChar{Name: name, MinConst: 6, MaxConst: 6} }
+	r := rules
+	r.Combos = []ComboRule{
+		{Name: "6甲+6乙", Value: 500, RequiredChars: []RequiredChar{c6("甲"), c6("乙")}},
+		{Name: "6乙+6丙", Value: 300, RequiredChars: []RequiredChar{c6("乙"), c6("丙")}},
+		{Name: "6甲+6丁", Value: 800, RequiredChars: []RequiredChar{c6("甲"), c6("丁")}},
+	}
+	account := eval.Assets{Characters: map[string]int{"甲": 6, "乙": 6, "丙": 6, "丁": 2}}
+
+	statuses := newRuleWith(t, r).ExplainCombos(account, r.Combos)
+	want := []string{ComboMet, ComboBlocked, ComboMissing}
+	for i, status := range statuses {
+		if status.Status != want[i] {
+			t.Errorf("%s: expected %s, got %s", status.Combo.Name, want[i], status.Status)
+		}
+	}
+	if got := statuses[1].Details; len(got) != 1 || got[0] != "乙 已用于 [6甲+6乙]" {
+		t.Errorf("Unexpected blocking details: %v", got)
+	}
+	if got := statuses[2].Details; len(got) != 1 || got[0] != "需要 6丁 (当前2命)" {
+		t.Errorf("Unexpected missing details: %v", got)
+	}
+}
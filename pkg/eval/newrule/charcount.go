@@ -0,0 +1,100 @@
+package newrule
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+// CharCountPolicy 定义角色数量乘数的计数口径
+type CharCountPolicy struct {
+	Name                string `json:"name"`                // 口径名称，用于明细展示
+	IncludePriced       bool   `json:"includePriced"`       // 计入价格表内的角色
+	IncludeLimited      bool   `json:"includeLimited"`      // 计入限定五星角色，不论是否在价格表内
+	IncludeStandard     bool   `json:"includeStandard"`     // 计入常驻五星角色
+	IncludeFourStars    bool   `json:"includeFourStars"`    // 计入四星角色
+	IncludeUnknown      bool   `json:"includeUnknown"`      // 计入规则中不存在的角色名
+	CountConstellations bool   `json:"countConstellations"` // 按持有数量计数，每个角色计 1+命座数
+}
+
+var (
+	// CountPricedFiveStars 仅计入价格表内的五星角色
+	CountPricedFiveStars = CharCountPolicy{Name: "价格表内五星角色", IncludePriced: true}
+	// CountLimitedFiveStars 仅计入限定五星角色，不计入常驻五星
+	CountLimitedFiveStars = CharCountPolicy{Name: "限定五星角色", IncludeLimited: true}
+	// CountAllFiveStars 计入价格表内的角色与限定、常驻五星角色
+	CountAllFiveStars = CharCountPolicy{Name: "全部五星角色", IncludePriced: true, IncludeLimited: true, IncludeStandard: true}
+	// CountAllCharacters 计入全部五星与四星角色
+	CountAllCharacters = CharCountPolicy{Name: "全部五星与四星角色", IncludePriced: true, IncludeLimited: true, IncludeStandard: true, IncludeFourStars: true}
+	// CountLegacy 账号内的所有角色名均计入 (旧口径)
+	CountLegacy = CharCountPolicy{Name: "账号内全部角色 (旧口径)", IncludePriced: true, IncludeLimited: true, IncludeStandard: true, IncludeFourStars: true, IncludeUnknown: true}
+)
+
+// characterClass 返回角色在规则中的分类，分类与角色是否在价格表内无关
+func characterClass(name string, set *ruleSet) string {
+	switch {
+	case slices.Contains(set.LimitedFiveStarChars, name):
+		return "限定五星"
+	case slices.Contains(set.StandardFiveStarChars, name):
+		return "常驻五星"
+	case slices.Contains(set.FourStarChars, name):
+		return "四星"
+	}
+	return "未知"
+}
+
+// countCharacters 按规则中的计数口径统计角色数量
+func countCharacters(account eval.Assets, set *ruleSet) (int, string) {
+	policy := set.CharCountPolicy
+
+	names := make([]string, 0, len(account.Characters))
+	for name := range account.Characters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	count := 0
+	var counted, skipped []string
+	for _, name := range names {
+		class := characterClass(name, set)
+		include := false
+		switch class {
+		case "限定五星":
+			include = policy.IncludeLimited
+		case "常驻五星":
+			include = policy.IncludeStandard
+		case "四星":
+			include = policy.IncludeFourStars
+		default:
+			include = policy.IncludeUnknown
+		}
+		if _, priced := set.Characters[name]; priced && policy.IncludePriced {
+			include = true
+		}
+		if !include {
+			skipped = append(skipped, fmt.Sprintf("%s(%s)", name, class))
+			continue
+		}
+		if policy.CountConstellations {
+			count += 1 + account.Characters[name]
+			counted = append(counted, fmt.Sprintf("%s x%d", name, 1+account.Characters[name]))
+		} else {
+			count++
+			counted = append(counted, name)
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "计数口径: %s", policy.Name)
+	if policy.CountConstellations {
+		sb.WriteString(" (按命座计数)")
+	}
+	fmt.Fprintf(&sb, "\n  - 计入 (%d): %s\n", count, strings.Join(counted, ", "))
+	if len(skipped) > 0 {
+		fmt.Fprintf(&sb, "  - 未计入: %s\n", strings.Join(skipped, ", "))
+	}
+	return count, sb.String()
+}
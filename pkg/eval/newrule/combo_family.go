@@ -0,0 +1,217 @@
+package newrule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ComboAddOn 是组合系列中的可选附加项，如 "爱可菲" 或 "茜特菈莉+希诺宁"
+type ComboAddOn struct {
+	Name          string // 附加在组合名称后的写法，为空时由角色要求生成
+	RequiredChars []RequiredChar
+	Value         float64 // 附加后增加的价值
+}
+
+// ComboFamily 由基础组合与可选附加项生成一系列超集组合
+// 生成的组合名称为基础名称依次拼接附加项名称，价值为基础价值与各附加项增量之和
+type ComboFamily struct {
+	Base   ComboRule
+	AddOns []ComboAddOn
+
+	// 需要生成的附加项选法，每种选法按名称列出附加项，名称按列出顺序拼接
+	// 为空时生成全部选法 (含不附加任何附加项的基础组合)，按 AddOns 的顺序拼接
+	Variants [][]string
+
+	MaxValue float64 // 价值上限，0 表示不封顶
+}
+
+// addOnName 返回附加项在组合名称中的写法
+func (a ComboAddOn) addOnName() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	names := make([]string, 0, len(a.RequiredChars))
+	for _, req := range a.RequiredChars {
+		names = append(names, req.String())
+	}
+	return strings.Join(names, "+")
+}
+
+// Expand 生成组合系列中的全部组合
+func (f ComboFamily) Expand() ([]ComboRule, error) {
+	byName := make(map[string]ComboAddOn, len(f.AddOns))
+	var allNames []string
+	for _, addOn := range f.AddOns {
+		name := addOn.addOnName()
+		if _, dup := byName[name]; dup {
+			return nil, fmt.Errorf("组合系列 %q 的附加项 %q 重复", f.Base.Name, name)
+		}
+		byName[name] = addOn
+		allNames = append(allNames, name)
+	}
+
+	variants := f.Variants
+	if len(variants) == 0 {
+		for mask := 0; mask < 1<<len(allNames); mask++ {
+			var variant []string
+			for i, name := range allNames {
+				if mask&(1<<i) != 0 {
+					variant = append(variant, name)
+				}
+			}
+			variants = append(variants, variant)
+		}
+	}
+
+	combos := make([]ComboRule, 0, len(variants))
+	for _, variant := range variants {
+		combo := f.Base
+		combo.RequiredChars = append([]RequiredChar{}, f.Base.RequiredChars...)
+		for _, name := range variant {
+			addOn, ok := byName[name]
+			if !ok {
+				return nil, fmt.Errorf("组合系列 %q 的选法引用了未定义的附加项 %q", f.Base.Name, name)
+			}
+			combo.Name += "+" + name
+			combo.Value += addOn.Value
+			combo.RequiredChars = append(combo.RequiredChars, addOn.RequiredChars...)
+		}
+		if f.MaxValue > 0 && combo.Value > f.MaxValue {
+			combo.Value = f.MaxValue
+		}
+		combos = append(combos, combo)
+	}
+	return combos, nil
+}
+
+// ExpandComboFamilies 依次生成多个组合系列中的全部组合
+func ExpandComboFamilies(families []ComboFamily) ([]ComboRule, error) {
+	var combos []ComboRule
+	for _, f := range families {
+		expanded, err := f.Expand()
+		if err != nil {
+			return nil, err
+		}
+		combos = append(combos, expanded...)
+	}
+	return combos, nil
+}
+
+// ComboDiff 是两份组合列表之间的一处差异
+type ComboDiff struct {
+	Kind   string // "缺失": 仅参照列表中存在; "多余": 仅生成列表中存在; "不一致": 同名组合的内容不同
+	Name   string
+	Detail string
+}
+
+func (d ComboDiff) String() string {
+	if d.Detail == "" {
+		return fmt.Sprintf("[%s] %s", d.Kind, d.Name)
+	}
+	return fmt.Sprintf("[%s] %s: %s", d.Kind, d.Name, d.Detail)
+}
+
+// comboSignature 以与顺序无关的方式描述组合的分组、价值与全部要求
+func comboSignature(combo ComboRule) string {
+	describe := func(reqs []RequiredChar) string {
+		parts := make([]string, 0, len(reqs))
+		for _, req := range reqs {
+			parts = append(parts, fmt.Sprintf("%s:%d-%d", req.Name, req.MinConst, req.MaxConst))
+		}
+		sort.Strings(parts)
+		return strings.Join(parts, ",")
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "分组=%s 价值=%.2f 角色=[%s]", combo.Group, combo.Value, describe(combo.RequiredChars))
+	for _, group := range combo.RequiredGroups {
+		fmt.Fprintf(&sb, " 其%d=[%s]", group.MinMatch, describe(group.Options))
+	}
+	for _, attr := range combo.RequiredAttributes {
+		fmt.Fprintf(&sb, " 属性=[%s]", attr)
+	}
+	weapons := make([]string, 0, len(combo.RequiredWeapons))
+	for _, req := range combo.RequiredWeapons {
+		weapons = append(weapons, req.String())
+	}
+	sort.Strings(weapons)
+	if len(weapons) > 0 {
+		fmt.Fprintf(&sb, " 武器=[%s]", strings.Join(weapons, ","))
+	}
+	multipliers := make([]string, 0, len(combo.WeaponMultipliers))
+	for name, factor := range combo.WeaponMultipliers {
+		multipliers = append(multipliers, fmt.Sprintf("%s x%.2f", name, factor))
+	}
+	sort.Strings(multipliers)
+	if len(multipliers) > 0 {
+		fmt.Fprintf(&sb, " 武器乘数=[%s]", strings.Join(multipliers, ","))
+	}
+	if len(combo.ShareableChars) > 0 {
+		shareable := append([]string{}, combo.ShareableChars...)
+		sort.Strings(shareable)
+		fmt.Fprintf(&sb, " 可共享=[%s] 折扣=%.2f", strings.Join(shareable, ","), combo.SharingDiscount)
+	}
+	if combo.Condition != "" {
+		fmt.Fprintf(&sb, " 条件=%s", combo.Condition)
+	}
+	return sb.String()
+}
+
+// CompareCombos 比较生成的组合列表与参照列表，按组合名称配对，忽略列表顺序与角色要求的先后
+// 返回的差异按名称排序，两份列表等价时返回空
+func CompareCombos(generated, reference []ComboRule) []ComboDiff {
+	return DiffCombos(generated, reference, "生成", "参照")
+}
+
+// DiffCombos 与 CompareCombos 相同，"不一致" 的说明中以 genLabel、refLabel 称呼两份列表
+func DiffCombos(generated, reference []ComboRule, genLabel, refLabel string) []ComboDiff {
+	gen := make(map[string][]string)
+	ref := make(map[string][]string)
+	names := make(map[string]bool)
+	for _, combo := range generated {
+		gen[combo.Name] = append(gen[combo.Name], comboSignature(combo))
+		names[combo.Name] = true
+	}
+	for _, combo := range reference {
+		ref[combo.Name] = append(ref[combo.Name], comboSignature(combo))
+		names[combo.Name] = true
+	}
+
+	sortedNames := make([]string, 0, len(names))
+	for name := range names {
+		sortedNames = append(sortedNames, name)
+	}
+	sort.Strings(sortedNames)
+
+	var diffs []ComboDiff
+	for _, name := range sortedNames {
+		// 同名组合可能不止一条，先去掉完全相同的配对
+		var onlyGen []string
+		remaining := append([]string{}, ref[name]...)
+		for _, sig := range gen[name] {
+			matched := false
+			for i, r := range remaining {
+				if r == sig {
+					remaining = append(remaining[:i], remaining[i+1:]...)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				onlyGen = append(onlyGen, sig)
+			}
+		}
+		for len(onlyGen) > 0 && len(remaining) > 0 {
+			diffs = append(diffs, ComboDiff{Kind: "不一致", Name: name, Detail: fmt.Sprintf("%s {%s}，%s {%s}", genLabel, onlyGen[0], refLabel, remaining[0])})
+			onlyGen, remaining = onlyGen[1:], remaining[1:]
+		}
+		for _, sig := range onlyGen {
+			diffs = append(diffs, ComboDiff{Kind: "多余", Name: name, Detail: sig})
+		}
+		for _, sig := range remaining {
+			diffs = append(diffs, ComboDiff{Kind: "缺失", Name: name, Detail: sig})
+		}
+	}
+	return diffs
+}
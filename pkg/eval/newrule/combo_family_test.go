@@ -0,0 +1,74 @@
+package newrule
+
+import (
+	"testing"
+)
+
+func TestComboFamily_Expand(t *testing.T) {
+	family := ComboFamily{
+		Base: ComboRule{Name: "6丝柯克+6玛薇卡", Value: 600, RequiredChars: []RequiredChar{{Name: "丝柯克", MinConst: 6, MaxConst: 6}, {Name: "玛薇卡", MinConst: 6, MaxConst: 6}}},
+		AddOns: []ComboAddOn{
+			{Value: 100, RequiredChars: []RequiredChar{{Name: "爱可菲", MaxConst: 6}}},
+			{Value: 300, RequiredChars: []RequiredChar{{Name: "茜特菈莉", MaxConst: 6}, {Name: "希诺宁", MaxConst: 6}}},
+		},
+		MaxValue: 900,
+	}
+	combos, err := family.Expand()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]float64{
+		"6丝柯克+6玛薇卡":              600,
+		"6丝柯克+6玛薇卡+爱可菲":          700,
+		"6丝柯克+6玛薇卡+茜特菈莉+希诺宁":     900,
+		"6丝柯克+6玛薇卡+爱可菲+茜特菈莉+希诺宁": 900, // 封顶
+	}
+	if len(combos) != len(want) {
+		t.Fatalf("Expected %d combos, got %d", len(want), len(combos))
+	}
+	for _, combo := range combos {
+		if v, ok := want[combo.Name]; !ok || v != combo.Value {
+			t.Errorf("Unexpected combo %s = %.0f", combo.Name, combo.Value)
+		}
+	}
+
+	family.Variants = [][]string{{"茜特菈莉+希诺宁", "爱可菲"}}
+	combos, err = family.Expand()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(combos) != 1 || combos[0].Name != "6丝柯克+6玛薇卡+茜特菈莉+希诺宁+爱可菲" || len(combos[0].RequiredChars) != 5 {
+		t.Errorf("Unexpected variant expansion: %v", combos)
+	}
+
+	family.Variants = [][]string{{"恰斯卡"}}
+	if _, err := family.Expand(); err == nil {
+		t.Error("Expected an error for an unknown add-on")
+	}
+}
+
+func TestGetCombos_EquivalentToHandWritten(t *testing.T) {
+	for _, diff := range CompareCombos(getCombos(), handWrittenCombos()) {
+		t.Error(diff)
+	}
+}
+
+func TestCompareCombos(t *testing.T) {
+	reference := []ComboRule{
+		{Name: "A", Value: 100, RequiredChars: []RequiredChar{{Name: "甲", MinConst: 6, MaxConst: 6}, {Name: "乙", MaxConst: 6}}},
+		{Name: "B", Value: 200},
+	}
+	generated := []ComboRule{
+		{Name: "A", Value: 100, RequiredChars: []RequiredChar{{Name: "乙", MaxConst: 6}, {Name: "甲", MinConst: 6, MaxConst: 6}}},
+		{Name: "C", Value: 300},
+	}
+	diffs := CompareCombos(generated, reference)
+	if len(diffs) != 2 || diffs[0].Kind != "缺失" || diffs[0].Name != "B" || diffs[1].Kind != "多余" || diffs[1].Name != "C" {
+		t.Errorf("Unexpected diffs: %v", diffs)
+	}
+
+	generated[0].Value = 150
+	if diffs := CompareCombos(generated[:1], reference[:1]); len(diffs) != 1 || diffs[0].Kind != "不一致" {
+		t.Errorf("Expected a mismatch, got %v", diffs)
+	}
+}
@@ -0,0 +1,241 @@
+package newrule
+
+// handWrittenCombos 是改用组合系列生成之前手工逐条列出的组合列表，
+// 作为参照验证 getCombos() 的生成结果与之等价
+func handWrittenCombos() []ComboRule {
+	c := func(name string, minConst int, maxConst ...int) RequiredChar {
+		rc := RequiredChar{Name: name, MinConst: minConst}
+		if len(maxConst) > 0 {
+			rc.MaxConst = maxConst[0]
+		} else {
+			rc.MaxConst = 6
+		}
+		return rc
+	}
+	oneOf := func(options ...RequiredChar) RequirementGroup {
+		return RequirementGroup{Options: options, MinMatch: 1}
+	}
+	group := func(name string, list []ComboRule) []ComboRule {
+		for i := range list {
+			list[i].Group = name
+		}
+		return list
+	}
+
+	var combos []ComboRule
+	// ==================== 纳塔满命溢价组合 ====================
+	combos = append(combos, group("纳塔满命溢价组合", []ComboRule{
+		// 6丝柯克+6玛薇卡 系列
+		{Name: "6丝柯克+6玛薇卡", Value: 600.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6)}},
+		{Name: "6丝柯克+6玛薇卡+6茜特菈莉", Value: 1000.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("茜特菈莉", 6)}},
+		{Name: "6丝柯克+6玛薇卡+6茜特菈莉+爱可菲+希诺宁", Value: 1200.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("茜特菈莉", 6), c("爱可菲", 0), c("希诺宁", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6芙宁娜", Value: 1400.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("芙宁娜", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6芙宁娜+爱可菲+茜特菈莉+希诺宁", Value: 1600.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("芙宁娜", 6), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6芙宁娜+6恰斯卡", Value: 2400.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("芙宁娜", 6), c("恰斯卡", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6芙宁娜+6恰斯卡+爱可菲+茜特菈莉+希诺宁", Value: 2600.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("芙宁娜", 6), c("恰斯卡", 6), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6玛薇卡+爱可菲", Value: 700.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6玛薇卡+茜特菈莉+希诺宁", Value: 700.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6丝柯克+6玛薇卡+爱可菲+茜特菈莉+希诺宁", Value: 800.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6恰斯卡", Value: 1400.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("恰斯卡", 6)}},
+		{Name: "6丝柯克+6玛薇卡+6恰斯卡+爱可菲+茜特菈莉+希诺宁", Value: 1600.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("恰斯卡", 6), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺", Value: 1200.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+爱可菲+茜特菈莉+希诺宁", Value: 1400.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		// 6丝柯克 (without 6玛薇卡) 系列
+		{Name: "6丝柯克+6那维莱特/6阿蕾奇诺", Value: 300.0, RequiredChars: []RequiredChar{c("丝柯克", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6那维莱特/6阿蕾奇诺+爱可菲", Value: 400.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("爱可菲", 0)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6那维莱特+6阿蕾奇诺", Value: 700.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("那维莱特", 6), c("阿蕾奇诺", 6)}},
+		{Name: "6丝柯克+6那维莱特+6阿蕾奇诺+爱可菲", Value: 800.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6那维莱特+6阿蕾奇诺+6芙宁娜", Value: 900.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("芙宁娜", 6)}},
+		{Name: "6丝柯克+6那维莱特+6阿蕾奇诺+6芙宁娜+爱可菲", Value: 1000.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("芙宁娜", 6), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6那维莱特+6阿蕾奇诺+6恰斯卡", Value: 1300.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6)}},
+		{Name: "6丝柯克+6那维莱特+6阿蕾奇诺+6恰斯卡+爱可菲", Value: 1500.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6那维莱特+6阿蕾奇诺+6恰斯卡+6芙宁娜", Value: 1800.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("芙宁娜", 6)}},
+		{Name: "6丝柯克+6那维莱特+6阿蕾奇诺+6恰斯卡+6芙宁娜+爱可菲", Value: 2000.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("芙宁娜", 6), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6恰斯卡", Value: 600.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("恰斯卡", 6)}},
+		{Name: "6丝柯克+6恰斯卡+爱可菲", Value: 700.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("恰斯卡", 6), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6那维莱特/6阿蕾奇诺+6恰斯卡", Value: 800.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("恰斯卡", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6那维莱特/6阿蕾奇诺+6恰斯卡+爱可菲", Value: 900.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("恰斯卡", 6), c("爱可菲", 0)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6那维莱特/6阿蕾奇诺+6恰斯卡+6芙宁娜", Value: 1100.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("恰斯卡", 6), c("芙宁娜", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6那维莱特/6阿蕾奇诺+6恰斯卡+6芙宁娜+爱可菲", Value: 1200.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("恰斯卡", 6), c("芙宁娜", 6), c("爱可菲", 0)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+大于2玛薇卡", Value: 150.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 2, 6)}},
+		{Name: "6丝柯克+大于2玛薇卡+爱可菲", Value: 200.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 2, 6), c("爱可菲", 0)}},
+		{Name: "6丝柯克+大于2玛薇卡+爱可菲+茜特菈莉+希诺宁", Value: 300.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 2, 6), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6丝柯克+爱可菲", Value: 100.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6爱可菲", Value: 500.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("爱可菲", 6)}},
+		// 6丝柯克+6玛薇卡+6茜特菈莉 大组合系列
+		{Name: "6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6茜特菈莉", Value: 2400.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("茜特菈莉", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6茜特菈莉+希诺宁+爱可菲", Value: 2600.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("茜特菈莉", 6), c("希诺宁", 0), c("爱可菲", 0)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6茜特菈莉+6芙宁娜", Value: 2800.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("茜特菈莉", 6), c("芙宁娜", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6茜特菈莉+6芙宁娜+希诺宁+爱可菲", Value: 3000.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("茜特菈莉", 6), c("芙宁娜", 6), c("希诺宁", 0), c("爱可菲", 0)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		// 6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺 大组合系列
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺", Value: 2000.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+爱可菲", Value: 2200.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+茜特菈莉+希诺宁", Value: 2200.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+茜特菈莉+希诺宁+爱可菲", Value: 2400.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("茜特菈莉", 0), c("希诺宁", 0), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6茜特菈莉", Value: 3300.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("茜特菈莉", 6)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6茜特菈莉+希诺宁+爱可菲", Value: 3500.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("茜特菈莉", 6), c("希诺宁", 0), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6芙宁娜", Value: 2400.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("芙宁娜", 6)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6芙宁娜+茜特菈莉+希诺宁+爱可菲", Value: 2600.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("芙宁娜", 6), c("茜特菈莉", 0), c("希诺宁", 0), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6恰斯卡", Value: 2600.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6恰斯卡+爱可菲", Value: 2800.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6恰斯卡+茜特菈莉+希诺宁", Value: 2800.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6恰斯卡+茜特菈莉+希诺宁+爱可菲", Value: 3000.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("茜特菈莉", 0), c("希诺宁", 0), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6恰斯卡+6茜特菈莉", Value: 3000.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("茜特菈莉", 6)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6恰斯卡+6茜特菈莉+希诺宁+爱可菲", Value: 3200.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("茜特菈莉", 6), c("希诺宁", 0), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6恰斯卡+6芙宁娜", Value: 3000.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("芙宁娜", 6)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6恰斯卡+6芙宁娜+茜特菈莉+希诺宁+爱可菲", Value: 3200.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("芙宁娜", 6), c("茜特菈莉", 0), c("希诺宁", 0), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6恰斯卡+6芙宁娜+6茜特菈莉", Value: 3600.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("芙宁娜", 6), c("茜特菈莉", 6)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6恰斯卡+6芙宁娜+6茜特菈莉+希诺宁+爱可菲", Value: 3800.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("芙宁娜", 6), c("茜特菈莉", 6), c("希诺宁", 0), c("爱可菲", 0)}},
+		// 6丝柯克+6玛薇卡+6恰斯卡 子系列
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6恰斯卡", Value: 2000.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("恰斯卡", 6)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6恰斯卡+爱可菲", Value: 2200.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("恰斯卡", 6), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6恰斯卡+茜特菈莉+希诺宁", Value: 2200.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("恰斯卡", 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6恰斯卡+爱可菲+茜特菈莉+希诺宁", Value: 2400.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("恰斯卡", 6), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6恰斯卡+6茜特菈莉", Value: 3000.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("恰斯卡", 6), c("茜特菈莉", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特+6恰斯卡+6茜特菈莉+希诺宁+爱可菲", Value: 3200.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("那维莱特", 6), c("恰斯卡", 6), c("茜特菈莉", 6), c("希诺宁", 0), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6恰斯卡+6茜特菈莉", Value: 2600.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("恰斯卡", 6), c("茜特菈莉", 6)}},
+		{Name: "6丝柯克+6玛薇卡+6恰斯卡+6茜特菈莉+爱可菲+希诺宁", Value: 2800.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("恰斯卡", 6), c("茜特菈莉", 6), c("爱可菲", 0), c("希诺宁", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6茜特菈莉(独立)", Value: 2600.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("茜特菈莉", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6茜特菈莉+爱可菲+希诺宁", Value: 2800.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("茜特菈莉", 6), c("爱可菲", 0), c("希诺宁", 0)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6恰斯卡+6芙宁娜", Value: 3000.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("恰斯卡", 6), c("芙宁娜", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6恰斯卡+6芙宁娜+爱可菲+茜特菈莉+希诺宁", Value: 3200.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("恰斯卡", 6), c("芙宁娜", 6), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6丝柯克+6玛薇卡+6阿蕾奇诺+6恰斯卡", Value: 2200.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6)}},
+		{Name: "6丝柯克+6玛薇卡+6阿蕾奇诺+6恰斯卡+爱可菲", Value: 2300.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("爱可菲", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6阿蕾奇诺+6恰斯卡+茜特菈莉+希诺宁", Value: 2300.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6阿蕾奇诺+6恰斯卡+爱可菲+茜特菈莉+希诺宁", Value: 2500.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6阿蕾奇诺+6恰斯卡+6茜特菈莉+爱可菲+希诺宁", Value: 3200.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("茜特菈莉", 6), c("爱可菲", 0), c("希诺宁", 0)}},
+		{Name: "6丝柯克+6玛薇卡+6茜特菈莉(base)", Value: 1200.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("茜特菈莉", 6)}},
+		{Name: "6丝柯克+6玛薇卡+6茜特菈莉+爱可菲+希诺宁", Value: 1400.0, RequiredChars: []RequiredChar{c("丝柯克", 6), c("玛薇卡", 6), c("茜特菈莉", 6), c("爱可菲", 0), c("希诺宁", 0)}},
+		// 6玛薇卡 系列（不含6丝柯克）
+		{Name: "6玛薇卡+6那维莱特/6阿蕾奇诺", Value: 200.0, RequiredChars: []RequiredChar{c("玛薇卡", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6玛薇卡+6那维莱特/6阿蕾奇诺+茜特菈莉+希诺宁", Value: 300.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("茜特菈莉", 0), c("希诺宁", 0)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6玛薇卡+6那维莱特+6芙宁娜", Value: 500.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("那维莱特", 6), c("芙宁娜", 6)}},
+		{Name: "6玛薇卡+6那维莱特+6芙宁娜+茜特菈莉+希诺宁", Value: 600.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("那维莱特", 6), c("芙宁娜", 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6玛薇卡+6恰斯卡", Value: 500.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("恰斯卡", 6)}},
+		{Name: "6玛薇卡+6恰斯卡+6茜特菈莉", Value: 700.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("恰斯卡", 6), c("茜特菈莉", 6)}},
+		{Name: "6玛薇卡+6恰斯卡+茜特菈莉+希诺宁", Value: 600.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("恰斯卡", 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6玛薇卡+大于2命丝柯克", Value: 100.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("丝柯克", 2, 6)}},
+		{Name: "6玛薇卡+大于2命丝柯克+爱可菲", Value: 150.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("丝柯克", 2, 6), c("爱可菲", 0)}},
+		{Name: "6玛薇卡+大于2命丝柯克+爱可菲+茜特菈莉+希诺宁", Value: 200.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("丝柯克", 2, 6), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6玛薇卡+6那维莱特+6阿蕾奇诺", Value: 700.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6)}},
+		{Name: "6玛薇卡+6那维莱特+6阿蕾奇诺+茜特菈莉+希诺宁", Value: 800.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6玛薇卡+6那维莱特+6阿蕾奇诺+6芙宁娜", Value: 1000.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("芙宁娜", 6)}},
+		{Name: "6玛薇卡+6那维莱特+6阿蕾奇诺+6芙宁娜+茜特菈莉+希诺宁", Value: 1100.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("芙宁娜", 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6玛薇卡+6那维莱特+6恰斯卡", Value: 700.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("那维莱特", 6), c("恰斯卡", 6)}},
+		{Name: "6玛薇卡+6那维莱特+6恰斯卡+茜特菈莉+希诺宁", Value: 800.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("那维莱特", 6), c("恰斯卡", 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6玛薇卡+6那维莱特+6恰斯卡+6芙宁娜", Value: 1000.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("那维莱特", 6), c("恰斯卡", 6), c("芙宁娜", 6)}},
+		{Name: "6玛薇卡+6那维莱特+6恰斯卡+6芙宁娜+茜特菈莉+希诺宁", Value: 1100.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("那维莱特", 6), c("恰斯卡", 6), c("芙宁娜", 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6玛薇卡+6阿蕾奇诺+6恰斯卡", Value: 1000.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6)}},
+		{Name: "6玛薇卡+6阿蕾奇诺+6恰斯卡+茜特菈莉+希诺宁", Value: 1100.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6玛薇卡+6阿蕾奇诺+6恰斯卡+6芙宁娜", Value: 1300.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("芙宁娜", 6)}},
+		{Name: "6玛薇卡+6阿蕾奇诺+6恰斯卡+6芙宁娜+茜特菈莉+希诺宁", Value: 1400.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("芙宁娜", 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6玛薇卡+6茜特菈莉", Value: 300.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("茜特菈莉", 6)}},
+		{Name: "6玛薇卡+6茜特菈莉+6那维莱特+6阿蕾奇诺", Value: 1200.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("茜特菈莉", 6), c("那维莱特", 6), c("阿蕾奇诺", 6)}},
+		{Name: "6玛薇卡+6茜特菈莉+6那维莱特/6阿蕾奇诺+6芙宁娜", Value: 1200.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("茜特菈莉", 6), c("芙宁娜", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6玛薇卡+6茜特菈莉+6那维莱特/6阿蕾奇诺+6恰斯卡", Value: 1800.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("茜特菈莉", 6), c("恰斯卡", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6玛薇卡+6茜特菈莉+6那维莱特/6阿蕾奇诺+6恰斯卡+6芙宁娜", Value: 2000.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("茜特菈莉", 6), c("恰斯卡", 6), c("芙宁娜", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6玛薇卡+6茜特菈莉+6那维莱特+6阿蕾奇诺+6恰斯卡", Value: 2600.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("茜特菈莉", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6)}},
+		{Name: "6玛薇卡+6茜特菈莉+6那维莱特+6阿蕾奇诺+6恰斯卡+6芙宁娜", Value: 2800.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("茜特菈莉", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("恰斯卡", 6), c("芙宁娜", 6)}},
+		{Name: "6玛薇卡+6恰斯卡+6那维莱特+6阿蕾奇诺", Value: 2000.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("恰斯卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6)}},
+		{Name: "6玛薇卡+6恰斯卡+6那维莱特+6阿蕾奇诺+茜特菈莉+希诺宁", Value: 2100.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("恰斯卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "6玛薇卡+6恰斯卡+6那维莱特+6阿蕾奇诺+6芙宁娜", Value: 2200.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("恰斯卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("芙宁娜", 6)}},
+		{Name: "6玛薇卡+6恰斯卡+6那维莱特+6阿蕾奇诺+6芙宁娜+茜特菈莉+希诺宁", Value: 2300.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("恰斯卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("芙宁娜", 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		// 6恰斯卡 系列（不含玛薇卡/丝柯克）
+		{Name: "6恰斯卡+6那维莱特/6阿蕾奇诺", Value: 200.0, RequiredChars: []RequiredChar{c("恰斯卡", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6恰斯卡+6那维莱特/6阿蕾奇诺+6芙宁娜", Value: 500.0, RequiredChars: []RequiredChar{c("恰斯卡", 6), c("芙宁娜", 6)}, RequiredGroups: []RequirementGroup{oneOf(c("那维莱特", 6), c("阿蕾奇诺", 6))}},
+		{Name: "6恰斯卡+6那维莱特+6阿蕾奇诺", Value: 1000.0, RequiredChars: []RequiredChar{c("恰斯卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6)}},
+		{Name: "6恰斯卡+6那维莱特+6阿蕾奇诺+6芙宁娜", Value: 1200.0, RequiredChars: []RequiredChar{c("恰斯卡", 6), c("那维莱特", 6), c("阿蕾奇诺", 6), c("芙宁娜", 6)}},
+		{Name: "6恰斯卡+大于2玛薇卡+茜特菈莉+希诺宁", Value: 200.0, RequiredChars: []RequiredChar{c("恰斯卡", 6), c("玛薇卡", 2, 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		// 其他小型纳塔组合
+		{Name: "6阿蕾奇诺+6茜特菈莉", Value: 100.0, RequiredChars: []RequiredChar{c("阿蕾奇诺", 6), c("茜特菈莉", 6)}},
+		{Name: "6那维莱特+6阿蕾奇诺", Value: 100.0, RequiredChars: []RequiredChar{c("那维莱特", 6), c("阿蕾奇诺", 6)}},
+		{Name: "6阿蕾奇诺+6芙宁娜", Value: 100.0, RequiredChars: []RequiredChar{c("阿蕾奇诺", 6), c("芙宁娜", 6)}},
+		{Name: "6那维莱特+6阿蕾奇诺+6芙宁娜", Value: 200.0, RequiredChars: []RequiredChar{c("那维莱特", 6), c("阿蕾奇诺", 6), c("芙宁娜", 6)}},
+		{Name: "6玛薇卡+茜特菈莉+希诺宁", Value: 100.0, RequiredChars: []RequiredChar{c("玛薇卡", 6), c("茜特菈莉", 0), c("希诺宁", 0)}},
+	})...)
+
+	// ==================== 月国满命溢价组合 ====================
+	combos = append(combos, group("月国满命溢价组合", []ComboRule{
+		{Name: "6哥伦比娅+6兹白", Value: 600.0, RequiredChars: []RequiredChar{c("哥伦比娅", 6), c("兹白", 6)}},
+		{Name: "6莉奈娅+6兹白", Value: 600.0, RequiredChars: []RequiredChar{c("莉奈娅", 6), c("兹白", 6)}},
+		{Name: "6哥伦比娅+6兹白+6莉奈娅", Value: 2000.0, RequiredChars: []RequiredChar{c("哥伦比娅", 6), c("兹白", 6), c("莉奈娅", 6)}},
+		{Name: "6哥伦比娅+6奈芙尔", Value: 600.0, RequiredChars: []RequiredChar{c("哥伦比娅", 6), c("奈芙尔", 6)}},
+		{Name: "6奈芙尔+6菈乌玛", Value: 600.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菈乌玛", 6)}},
+		{Name: "6哥伦比娅+6奈芙尔+6菈乌玛", Value: 2000.0, RequiredChars: []RequiredChar{c("哥伦比娅", 6), c("奈芙尔", 6), c("菈乌玛", 6)}},
+		{Name: "6哥伦比娅+6菲林斯", Value: 600.0, RequiredChars: []RequiredChar{c("哥伦比娅", 6), c("菲林斯", 6)}},
+		{Name: "6菲林斯+6伊涅芙", Value: 600.0, RequiredChars: []RequiredChar{c("菲林斯", 6), c("伊涅芙", 6)}},
+		{Name: "6哥伦比娅+6菲林斯+6伊涅芙", Value: 2000.0, RequiredChars: []RequiredChar{c("哥伦比娅", 6), c("菲林斯", 6), c("伊涅芙", 6)}},
+		{Name: "6奈芙尔+6菲林斯", Value: 2000.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6)}},
+		{Name: "6奈芙尔+6菲林斯+哥伦比娅", Value: 2100.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6), c("哥伦比娅", 0)}},
+		{Name: "6奈芙尔+6菲林斯+哥伦比娅+伊涅芙+菈乌玛", Value: 2200.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6), c("哥伦比娅", 0), c("伊涅芙", 0), c("菈乌玛", 0)}},
+		{Name: "6奈芙尔+6菲林斯+6哥伦比娅", Value: 2800.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6), c("哥伦比娅", 6)}},
+		{Name: "6奈芙尔+6菲林斯+6哥伦比娅+伊涅芙+菈乌玛", Value: 3000.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6), c("哥伦比娅", 6), c("伊涅芙", 0), c("菈乌玛", 0)}},
+		{Name: "6奈芙尔+6菲林斯+6兹白", Value: 3500.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6), c("兹白", 6)}},
+		{Name: "6奈芙尔+6菲林斯+6兹白+哥伦比娅", Value: 3600.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6), c("兹白", 6), c("哥伦比娅", 0)}},
+		{Name: "6奈芙尔+6菲林斯+6兹白+哥伦比娅+伊涅芙+菈乌玛+莉奈娅", Value: 3800.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6), c("兹白", 6), c("哥伦比娅", 0), c("伊涅芙", 0), c("菈乌玛", 0), c("莉奈娅", 0)}},
+		{Name: "6奈芙尔+6菲林斯+6兹白+6哥伦比娅", Value: 4800.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6), c("兹白", 6), c("哥伦比娅", 6)}},
+		{Name: "6奈芙尔+6菲林斯+6兹白+6哥伦比娅+伊涅芙+菈乌玛+莉奈娅", Value: 5000.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6), c("兹白", 6), c("哥伦比娅", 6), c("伊涅芙", 0), c("菈乌玛", 0), c("莉奈娅", 0)}},
+		{Name: "6奈芙尔+6菲林斯+6兹白+6哥伦比娅+6伊涅芙+菈乌玛+莉奈娅", Value: 6000.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6), c("兹白", 6), c("哥伦比娅", 6), c("伊涅芙", 6), c("菈乌玛", 0), c("莉奈娅", 0)}},
+		{Name: "6奈芙尔+6菲林斯+6兹白+6哥伦比娅+伊涅芙+6菈乌玛+莉奈娅", Value: 6000.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6), c("兹白", 6), c("哥伦比娅", 6), c("伊涅芙", 0), c("菈乌玛", 6), c("莉奈娅", 0)}},
+		{Name: "6奈芙尔+6菲林斯+6兹白+6哥伦比娅+伊涅芙+菈乌玛+6莉奈娅", Value: 6000.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6), c("兹白", 6), c("哥伦比娅", 6), c("伊涅芙", 0), c("菈乌玛", 0), c("莉奈娅", 6)}},
+		{Name: "6奈芙尔+6菲林斯+6兹白+6哥伦比娅+6伊涅芙+6菈乌玛+莉奈娅", Value: 8000.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6), c("兹白", 6), c("哥伦比娅", 6), c("伊涅芙", 6), c("菈乌玛", 6), c("莉奈娅", 0)}},
+		{Name: "6奈芙尔+6菲林斯+6兹白+6哥伦比娅+6伊涅芙+菈乌玛+6莉奈娅", Value: 8000.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6), c("兹白", 6), c("哥伦比娅", 6), c("伊涅芙", 6), c("菈乌玛", 0), c("莉奈娅", 6)}},
+		{Name: "6奈芙尔+6菲林斯+6兹白+6哥伦比娅+伊涅芙+6菈乌玛+6莉奈娅", Value: 8000.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6), c("兹白", 6), c("哥伦比娅", 6), c("伊涅芙", 0), c("菈乌玛", 6), c("莉奈娅", 6)}},
+		{Name: "6奈芙尔+6菲林斯+6兹白+6哥伦比娅+6伊涅芙+6菈乌玛+6莉奈娅", Value: 10000.0, RequiredChars: []RequiredChar{c("奈芙尔", 6), c("菲林斯", 6), c("兹白", 6), c("哥伦比娅", 6), c("伊涅芙", 6), c("菈乌玛", 6), c("莉奈娅", 6)}},
+	})...)
+
+	// ==================== 低命溢价组合 ====================
+	combos = append(combos, group("低命溢价组合", []ComboRule{
+		{Name: "2-5丝柯克+2-5玛薇卡+爱可菲+茜特菈莉+希诺宁", Value: 300.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("玛薇卡", 2, 5), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "2-5丝柯克+2-5玛薇卡", Value: 100.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("玛薇卡", 2, 5)}},
+		{Name: "2-5丝柯克+2-5玛薇卡+0-6爱可菲", Value: 200.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("玛薇卡", 2, 5), c("爱可菲", 0)}},
+		{Name: "2-5丝柯克+2-5玛薇卡+0-6茜特菈莉+希诺宁", Value: 200.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("玛薇卡", 2, 5), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "2-5丝柯克+2-5玛薇卡+2-5菲林斯", Value: 500.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("玛薇卡", 2, 5), c("菲林斯", 2, 5)}},
+		{Name: "2-5丝柯克+2-5玛薇卡+2-5菲林斯+爱可菲+茜特菈莉+希诺宁", Value: 600.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("玛薇卡", 2, 5), c("菲林斯", 2, 5), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "2-5丝柯克+2-5玛薇卡+2-5菲林斯+哥伦比娅+伊涅芙", Value: 600.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("玛薇卡", 2, 5), c("菲林斯", 2, 5), c("哥伦比娅", 0), c("伊涅芙", 0)}},
+		{Name: "2-5丝柯克+2-5玛薇卡+2-5菲林斯+爱可菲+茜特菈莉+希诺宁+哥伦比娅+伊涅芙", Value: 700.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("玛薇卡", 2, 5), c("菲林斯", 2, 5), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0), c("哥伦比娅", 0), c("伊涅芙", 0)}},
+		{Name: "2-5丝柯克+2-5玛薇卡+2-5奈芙尔", Value: 500.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("玛薇卡", 2, 5), c("奈芙尔", 2, 5)}},
+		{Name: "2-5丝柯克+2-5玛薇卡+2-5奈芙尔+爱可菲+茜特菈莉+希诺宁", Value: 600.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("玛薇卡", 2, 5), c("奈芙尔", 2, 5), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "2-5丝柯克+2-5玛薇卡+2-5奈芙尔+哥伦比娅+菈乌玛", Value: 600.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("玛薇卡", 2, 5), c("奈芙尔", 2, 5), c("哥伦比娅", 0), c("菈乌玛", 0)}},
+		{Name: "2-5丝柯克+2-5玛薇卡+2-5奈芙尔+爱可菲+茜特菈莉+希诺宁+哥伦比娅+菈乌玛", Value: 700.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("玛薇卡", 2, 5), c("奈芙尔", 2, 5), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0), c("哥伦比娅", 0), c("菈乌玛", 0)}},
+		{Name: "2-5丝柯克+2-5玛薇卡+2-5兹白", Value: 500.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("玛薇卡", 2, 5), c("兹白", 2, 5)}},
+		{Name: "2-5丝柯克+2-5玛薇卡+2-5兹白+爱可菲+茜特菈莉+希诺宁", Value: 600.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("玛薇卡", 2, 5), c("兹白", 2, 5), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "2-5丝柯克+2-5玛薇卡+2-5兹白+哥伦比娅", Value: 600.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("玛薇卡", 2, 5), c("兹白", 2, 5), c("哥伦比娅", 0)}},
+		{Name: "2-5丝柯克+2-5玛薇卡+2-5兹白+爱可菲+茜特菈莉+希诺宁+哥伦比娅", Value: 700.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("玛薇卡", 2, 5), c("兹白", 2, 5), c("爱可菲", 0), c("茜特菈莉", 0), c("希诺宁", 0), c("哥伦比娅", 0)}},
+		{Name: "2-5玛薇卡+0-6茜特菈莉+0-6希诺宁", Value: 50.0, RequiredChars: []RequiredChar{c("玛薇卡", 2, 5), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		// 月国低命组合
+		{Name: "2-5菲林斯+2-5奈芙尔", Value: 200.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("奈芙尔", 2, 5)}},
+		{Name: "2-5菲林斯+2-5奈芙尔+哥伦比娅", Value: 250.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("奈芙尔", 2, 5), c("哥伦比娅", 0)}},
+		{Name: "2-5菲林斯+2-5奈芙尔+哥伦比娅+伊涅芙", Value: 300.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("奈芙尔", 2, 5), c("哥伦比娅", 0), c("伊涅芙", 0)}},
+		{Name: "2-5菲林斯+2-5奈芙尔+哥伦比娅+菈乌玛", Value: 300.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("奈芙尔", 2, 5), c("哥伦比娅", 0), c("菈乌玛", 0)}},
+		{Name: "2-5菲林斯+2-5奈芙尔+哥伦比娅+伊涅芙+菈乌玛", Value: 400.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("奈芙尔", 2, 5), c("哥伦比娅", 0), c("伊涅芙", 0), c("菈乌玛", 0)}},
+		{Name: "2-5菲林斯+2-5兹白", Value: 200.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("兹白", 2, 5)}},
+		{Name: "2-5菲林斯+2-5兹白+哥伦比娅", Value: 250.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("兹白", 2, 5), c("哥伦比娅", 0)}},
+		{Name: "2-5菲林斯+2-5兹白+哥伦比娅+伊涅芙", Value: 300.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("兹白", 2, 5), c("哥伦比娅", 0), c("伊涅芙", 0)}},
+		{Name: "2-5菲林斯+2-5兹白+哥伦比娅+莉奈娅", Value: 300.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("兹白", 2, 5), c("哥伦比娅", 0), c("莉奈娅", 0)}},
+		{Name: "2-5菲林斯+2-5兹白+哥伦比娅+伊涅芙+莉奈娅", Value: 400.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("兹白", 2, 5), c("哥伦比娅", 0), c("伊涅芙", 0), c("莉奈娅", 0)}},
+		{Name: "2-5奈芙尔+2-5兹白", Value: 200.0, RequiredChars: []RequiredChar{c("奈芙尔", 2, 5), c("兹白", 2, 5)}},
+		{Name: "2-5奈芙尔+2-5兹白+哥伦比娅", Value: 250.0, RequiredChars: []RequiredChar{c("奈芙尔", 2, 5), c("兹白", 2, 5), c("哥伦比娅", 0)}},
+		{Name: "2-5奈芙尔+2-5兹白+哥伦比娅+菈乌玛", Value: 300.0, RequiredChars: []RequiredChar{c("奈芙尔", 2, 5), c("兹白", 2, 5), c("哥伦比娅", 0), c("菈乌玛", 0)}},
+		{Name: "2-5奈芙尔+2-5兹白+哥伦比娅+莉奈娅", Value: 300.0, RequiredChars: []RequiredChar{c("奈芙尔", 2, 5), c("兹白", 2, 5), c("哥伦比娅", 0), c("莉奈娅", 0)}},
+		{Name: "2-5奈芙尔+2-5兹白+哥伦比娅+菈乌玛+莉奈娅", Value: 400.0, RequiredChars: []RequiredChar{c("奈芙尔", 2, 5), c("兹白", 2, 5), c("哥伦比娅", 0), c("菈乌玛", 0), c("莉奈娅", 0)}},
+		{Name: "2-5菲林斯+2-5奈芙尔+2-5兹白", Value: 500.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("奈芙尔", 2, 5), c("兹白", 2, 5)}},
+		{Name: "2-5菲林斯+2-5奈芙尔+2-5兹白+哥伦比娅", Value: 550.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("奈芙尔", 2, 5), c("兹白", 2, 5), c("哥伦比娅", 0)}},
+		{Name: "2-5菲林斯+2-5奈芙尔+2-5兹白+哥伦比娅+菈乌玛", Value: 600.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("奈芙尔", 2, 5), c("兹白", 2, 5), c("哥伦比娅", 0), c("菈乌玛", 0)}},
+		{Name: "2-5菲林斯+2-5奈芙尔+2-5兹白+哥伦比娅+伊涅芙", Value: 600.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("奈芙尔", 2, 5), c("兹白", 2, 5), c("哥伦比娅", 0), c("伊涅芙", 0)}},
+		{Name: "2-5菲林斯+2-5奈芙尔+2-5兹白+哥伦比娅+莉奈娅", Value: 600.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("奈芙尔", 2, 5), c("兹白", 2, 5), c("哥伦比娅", 0), c("莉奈娅", 0)}},
+		{Name: "2-5菲林斯+2-5奈芙尔+2-5兹白+哥伦比娅+菈乌玛+伊涅芙", Value: 800.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("奈芙尔", 2, 5), c("兹白", 2, 5), c("哥伦比娅", 0), c("菈乌玛", 0), c("伊涅芙", 0)}},
+		// 小型低命组合
+		{Name: "0-1丝柯克+爱可菲", Value: 25.0, RequiredChars: []RequiredChar{c("丝柯克", 0, 1), c("爱可菲", 0)}},
+		{Name: "0-1玛薇卡+茜特菈莉+希诺宁", Value: 20.0, RequiredChars: []RequiredChar{c("玛薇卡", 0, 1), c("茜特菈莉", 0), c("希诺宁", 0)}},
+		{Name: "2-5丝柯克+爱可菲", Value: 50.0, RequiredChars: []RequiredChar{c("丝柯克", 2, 5), c("爱可菲", 0)}},
+		{Name: "2-5奈芙尔+哥伦比娅+菈乌玛", Value: 100.0, RequiredChars: []RequiredChar{c("奈芙尔", 2, 5), c("哥伦比娅", 0), c("菈乌玛", 0)}},
+		{Name: "2-5菲林斯+哥伦比娅+伊涅芙", Value: 100.0, RequiredChars: []RequiredChar{c("菲林斯", 2, 5), c("哥伦比娅", 0), c("伊涅芙", 0)}},
+		{Name: "2-5兹白+哥伦比娅+莉奈娅", Value: 100.0, RequiredChars: []RequiredChar{c("兹白", 2, 5), c("哥伦比娅", 0), c("莉奈娅", 0)}},
+		{Name: "0-1奈芙尔+哥伦比娅+菈乌玛", Value: 50.0, RequiredChars: []RequiredChar{c("奈芙尔", 0, 1), c("哥伦比娅", 0), c("菈乌玛", 0)}},
+		{Name: "0-1菲林斯+哥伦比娅+伊涅芙", Value: 50.0, RequiredChars: []RequiredChar{c("菲林斯", 0, 1), c("哥伦比娅", 0), c("伊涅芙", 0)}},
+		{Name: "0-1兹白+哥伦比娅+莉奈娅", Value: 50.0, RequiredChars: []RequiredChar{c("兹白", 0, 1), c("哥伦比娅", 0), c("莉奈娅", 0)}},
+	})...)
+
+	return combos
+}
@@ -0,0 +1,57 @@
+package newrule
+
+import (
+	"fmt"
+	"slices"
+)
+
+// 枚举类型在 JSON 中以名称表示，下标为枚举值
+var (
+	tierModeNames          = []string{"step", "interpolate"}
+	resourceValueModeNames = []string{"tier", "expectation", "monte_carlo"}
+	bannerKindNames        = []string{"character", "weapon"}
+)
+
+func marshalEnum(typ string, names []string, v int) ([]byte, error) {
+	if v < 0 || v >= len(names) {
+		return nil, fmt.Errorf("无效的 %s 值 %d", typ, v)
+	}
+	return []byte(names[v]), nil
+}
+
+func unmarshalEnum(typ string, names []string, text []byte) (int, error) {
+	if i := slices.Index(names, string(text)); i >= 0 {
+		return i, nil
+	}
+	return 0, fmt.Errorf("无效的 %s 值 %q，可选值: %v", typ, text, names)
+}
+
+func (m TierMode) MarshalText() ([]byte, error) {
+	return marshalEnum("TierMode", tierModeNames, int(m))
+}
+
+func (m *TierMode) UnmarshalText(text []byte) error {
+	v, err := unmarshalEnum("TierMode", tierModeNames, text)
+	*m = TierMode(v)
+	return err
+}
+
+func (m ResourceValueMode) MarshalText() ([]byte, error) {
+	return marshalEnum("ResourceValueMode", resourceValueModeNames, int(m))
+}
+
+func (m *ResourceValueMode) UnmarshalText(text []byte) error {
+	v, err := unmarshalEnum("ResourceValueMode", resourceValueModeNames, text)
+	*m = ResourceValueMode(v)
+	return err
+}
+
+func (k BannerKind) MarshalText() ([]byte, error) {
+	return marshalEnum("BannerKind", bannerKindNames, int(k))
+}
+
+func (k *BannerKind) UnmarshalText(text []byte) error {
+	v, err := unmarshalEnum("BannerKind", bannerKindNames, text)
+	*k = BannerKind(v)
+	return err
+}
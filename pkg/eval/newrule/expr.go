@@ -0,0 +1,859 @@
+package newrule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+// 规则表达式语言
+//
+// 条件表达式支持:
+//   - 字面量: 数字、"字符串"、true、false
+//   - 角色集合: owned (账号全部角色)、c0..c6 (账号中命座不低于N的角色)、
+//     region("纳塔")、element("水")、weapontype("法器")、version("5.3") (规则中具有该属性的角色)
+//   - 函数: count(集合)、has("角色"[, 最低命座])、const("角色") (未拥有为-1)、
+//     refine("武器") (未拥有为0)、fates() (总抽数)
+//   - 运算: A in B (集合交集)、+ - * /、== != < <= > >=、and or not、括号
+//
+// 规则语句形如 "条件 => 动作"，动作支持:
+//   - +N / -N: 附加价值
+//   - weapon xN: 条件中通过 refine() 引用的武器价格乘以N
+//   - weapon("武器") xN: 指定武器价格乘以N
+//
+// 示例:
+//   count(c6 in region("纳塔")) >= 3 => +500
+//   has("玛薇卡", 6) and refine("焚曜千阳") == 5 => weapon x2
+
+// ExprError 是表达式的语法、类型或求值错误，指向出错的规则及位置
+type ExprError struct {
+	Rule   string
+	Source string
+	Pos    int // 出错位置 (按字符计，从0开始)
+	Msg    string
+}
+
+func (e *ExprError) Error() string {
+	return fmt.Sprintf("规则 %q 第 %d 列: %s\n    %s\n    %s^", e.Rule, e.Pos+1, e.Msg, e.Source, strings.Repeat(" ", e.Pos))
+}
+
+// ---------- 词法分析 ----------
+
+type exprTokenKind int
+
+const (
+	tokEOF exprTokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+	tokTimes // 动作中的 x
+)
+
+type exprToken struct {
+	kind exprTokenKind
+	text string
+	num  float64
+	pos  int
+}
+
+func lexExpr(rule string, src string) ([]exprToken, error) {
+	runes := []rune(src)
+	var tokens []exprToken
+	errAt := func(pos int, format string, args ...any) error {
+		return &ExprError{Rule: rule, Source: src, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+	}
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			text := string(runes[start:i])
+			n, err := strconv.ParseFloat(text, 64)
+			if err != nil {
+				return nil, errAt(start, "无效的数字 %q", text)
+			}
+			tokens = append(tokens, exprToken{kind: tokNumber, text: text, num: n, pos: start})
+		case r == '"' || r == '“':
+			closing := '"'
+			if r == '“' {
+				closing = '”'
+			}
+			start := i
+			i++
+			for i < len(runes) && runes[i] != closing {
+				i++
+			}
+			if i >= len(runes) {
+				return nil, errAt(start, "字符串缺少结束引号")
+			}
+			tokens = append(tokens, exprToken{kind: tokString, text: string(runes[start+1 : i]), pos: start})
+			i++
+		case r == 'x' && i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.'):
+			tokens = append(tokens, exprToken{kind: tokTimes, text: "x", pos: i})
+			i++
+		case r == '_' || (r < unicode.MaxASCII && unicode.IsLetter(r)):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || (runes[i] < unicode.MaxASCII && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])))) {
+				i++
+			}
+			tokens = append(tokens, exprToken{kind: tokIdent, text: string(runes[start:i]), pos: start})
+		default:
+			start := i
+			two := ""
+			if i+1 < len(runes) {
+				two = string(runes[i : i+2])
+			}
+			switch two {
+			case "==", "!=", "<=", ">=", "=>", "&&", "||":
+				tokens = append(tokens, exprToken{kind: tokOp, text: two, pos: start})
+				i += 2
+				continue
+			}
+			if strings.ContainsRune("()+-*/<>,!", r) {
+				tokens = append(tokens, exprToken{kind: tokOp, text: string(r), pos: start})
+				i++
+				continue
+			}
+			return nil, errAt(start, "无法识别的字符 %q", r)
+		}
+	}
+	tokens = append(tokens, exprToken{kind: tokEOF, pos: len(runes)})
+	return tokens, nil
+}
+
+// ---------- 语法树 ----------
+
+type exprType int
+
+const (
+	typeNumber exprType = iota
+	typeBool
+	typeString
+	typeSet
+)
+
+func (t exprType) String() string {
+	switch t {
+	case typeNumber:
+		return "数字"
+	case typeBool:
+		return "布尔"
+	case typeString:
+		return "字符串"
+	default:
+		return "角色集合"
+	}
+}
+
+type exprNode interface {
+	position() int
+}
+
+type (
+	numberLit struct {
+		pos   int
+		value float64
+	}
+	stringLit struct {
+		pos   int
+		value string
+	}
+	boolLit struct {
+		pos   int
+		value bool
+	}
+	identExpr struct {
+		pos  int
+		name string
+	}
+	unaryExpr struct {
+		pos int
+		op  string
+		x   exprNode
+	}
+	binaryExpr struct {
+		pos  int
+		op   string
+		l, r exprNode
+	}
+	callExpr struct {
+		pos  int
+		name string
+		args []exprNode
+	}
+)
+
+func (n *numberLit) position() int  { return n.pos }
+func (n *stringLit) position() int  { return n.pos }
+func (n *boolLit) position() int    { return n.pos }
+func (n *identExpr) position() int  { return n.pos }
+func (n *unaryExpr) position() int  { return n.pos }
+func (n *binaryExpr) position() int { return n.pos }
+func (n *callExpr) position() int   { return n.pos }
+
+// ---------- 语法分析 ----------
+
+type exprParser struct {
+	rule   string
+	src    string
+	tokens []exprToken
+	i      int
+
+	characters map[string]CharacterInfo // has()、const() 引用的角色须在其中
+	weapons    map[string]WeaponInfo    // refine() 引用的武器须在其中
+}
+
+func (p *exprParser) peek() exprToken { return p.tokens[p.i] }
+
+func (p *exprParser) next() exprToken {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *exprParser) errorf(pos int, format string, args ...any) error {
+	return &ExprError{Rule: p.rule, Source: p.src, Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *exprParser) isOp(text string) bool {
+	t := p.peek()
+	return t.kind == tokOp && t.text == text
+}
+
+func (p *exprParser) isKeyword(word string) bool {
+	t := p.peek()
+	return t.kind == tokIdent && t.text == word
+}
+
+func (p *exprParser) expectOp(text string) error {
+	if !p.isOp(text) {
+		t := p.peek()
+		return p.errorf(t.pos, "期望 %q，实际为 %s", text, describeToken(t))
+	}
+	p.next()
+	return nil
+}
+
+func describeToken(t exprToken) string {
+	switch t.kind {
+	case tokEOF:
+		return "表达式结尾"
+	case tokString:
+		return fmt.Sprintf("字符串 %q", t.text)
+	default:
+		return fmt.Sprintf("%q", t.text)
+	}
+}
+
+// 运算符优先级，数值越大结合越紧
+var exprBinaryPrec = map[string]int{
+	"or": 1, "||": 1,
+	"and": 2, "&&": 2,
+	"==": 3, "!=": 3, "<": 3, "<=": 3, ">": 3, ">=": 3,
+	"in": 4,
+	"+":  5, "-": 5,
+	"*": 6, "/": 6,
+}
+
+func (p *exprParser) binaryOp() (string, int, bool) {
+	t := p.peek()
+	if t.kind != tokOp && t.kind != tokIdent {
+		return "", 0, false
+	}
+	prec, ok := exprBinaryPrec[t.text]
+	return t.text, prec, ok
+}
+
+func (p *exprParser) parseExpr(minPrec int) (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, prec, ok := p.binaryOp()
+		if !ok || prec < minPrec {
+			return left, nil
+		}
+		opTok := p.next()
+		right, err := p.parseExpr(prec + 1)
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case "&&":
+			op = "and"
+		case "||":
+			op = "or"
+		}
+		left = &binaryExpr{pos: opTok.pos, op: op, l: left, r: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if p.isKeyword("not") || p.isOp("!") || p.isOp("-") {
+		t := p.next()
+		op := t.text
+		if op == "!" {
+			op = "not"
+		}
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryExpr{pos: t.pos, op: op, x: x}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	t := p.next()
+	switch t.kind {
+	case tokNumber:
+		return &numberLit{pos: t.pos, value: t.num}, nil
+	case tokString:
+		return &stringLit{pos: t.pos, value: t.text}, nil
+	case tokIdent:
+		switch t.text {
+		case "true", "false":
+			return &boolLit{pos: t.pos, value: t.text == "true"}, nil
+		}
+		if !p.isOp("(") {
+			return &identExpr{pos: t.pos, name: t.text}, nil
+		}
+		p.next()
+		call := &callExpr{pos: t.pos, name: t.text}
+		if !p.isOp(")") {
+			for {
+				arg, err := p.parseExpr(1)
+				if err != nil {
+					return nil, err
+				}
+				call.args = append(call.args, arg)
+				if !p.isOp(",") {
+					break
+				}
+				p.next()
+			}
+		}
+		if err := p.expectOp(")"); err != nil {
+			return nil, err
+		}
+		return call, nil
+	case tokOp:
+		if t.text == "(" {
+			x, err := p.parseExpr(1)
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectOp(")"); err != nil {
+				return nil, err
+			}
+			return x, nil
+		}
+	}
+	return nil, p.errorf(t.pos, "此处不应出现 %s", describeToken(t))
+}
+
+// ---------- 类型检查 ----------
+
+type exprFunc struct {
+	params  []exprType
+	minArgs int
+	ret     exprType
+	call    func(env *exprEnv, args []exprValue) exprValue
+}
+
+var exprSetIdents = map[string]int{"owned": 0, "c0": 0, "c1": 1, "c2": 2, "c3": 3, "c4": 4, "c5": 5, "c6": 6}
+
+var exprFuncs = map[string]exprFunc{
+	"count": {params: []exprType{typeSet}, minArgs: 1, ret: typeNumber, call: func(env *exprEnv, args []exprValue) exprValue {
+		return exprValue{num: float64(len(args[0].set))}
+	}},
+	"has": {params: []exprType{typeString, typeNumber}, minArgs: 1, ret: typeBool, call: func(env *exprEnv, args []exprValue) exprValue {
+		c, ok := env.account.Characters[args[0].str]
+		if !ok {
+			return exprValue{b: false}
+		}
+		return exprValue{b: len(args) < 2 || float64(c) >= args[1].num}
+	}},
+	"const": {params: []exprType{typeString}, minArgs: 1, ret: typeNumber, call: func(env *exprEnv, args []exprValue) exprValue {
+		if c, ok := env.account.Characters[args[0].str]; ok {
+			return exprValue{num: float64(c)}
+		}
+		return exprValue{num: -1}
+	}},
+	"refine": {params: []exprType{typeString}, minArgs: 1, ret: typeNumber, call: func(env *exprEnv, args []exprValue) exprValue {
+		return exprValue{num: float64(env.account.Weapons[args[0].str])}
+	}},
+	"fates": {ret: typeNumber, call: func(env *exprEnv, args []exprValue) exprValue {
+		return exprValue{num: float64(totalFates(env.account))}
+	}},
+	"region":     attributeSetFunc(func(c CharacterInfo) string { return c.Region }),
+	"element":    attributeSetFunc(func(c CharacterInfo) string { return c.Element }),
+	"weapontype": attributeSetFunc(func(c CharacterInfo) string { return c.WeaponType }),
+	"version":    attributeSetFunc(func(c CharacterInfo) string { return c.Version }),
+}
+
+func attributeSetFunc(attr func(CharacterInfo) string) exprFunc {
+	return exprFunc{params: []exprType{typeString}, minArgs: 1, ret: typeSet, call: func(env *exprEnv, args []exprValue) exprValue {
+		set := make(map[string]bool)
+		for name, info := range env.characters {
+			if attr(info) == args[0].str {
+				set[name] = true
+			}
+		}
+		return exprValue{set: set}
+	}}
+}
+
+func (p *exprParser) check(n exprNode) (exprType, error) {
+	switch n := n.(type) {
+	case *numberLit:
+		return typeNumber, nil
+	case *stringLit:
+		return typeString, nil
+	case *boolLit:
+		return typeBool, nil
+	case *identExpr:
+		if _, ok := exprSetIdents[n.name]; ok {
+			return typeSet, nil
+		}
+		return 0, p.errorf(n.pos, "未知的标识符 %q", n.name)
+	case *unaryExpr:
+		t, err := p.check(n.x)
+		if err != nil {
+			return 0, err
+		}
+		want := typeNumber
+		if n.op == "not" {
+			want = typeBool
+		}
+		if t != want {
+			return 0, p.errorf(n.pos, "%q 需要%s，实际为%s", n.op, want, t)
+		}
+		return want, nil
+	case *binaryExpr:
+		lt, err := p.check(n.l)
+		if err != nil {
+			return 0, err
+		}
+		rt, err := p.check(n.r)
+		if err != nil {
+			return 0, err
+		}
+		var operand, result exprType
+		switch n.op {
+		case "and", "or":
+			operand, result = typeBool, typeBool
+		case "in":
+			operand, result = typeSet, typeSet
+		case "+", "-", "*", "/":
+			operand, result = typeNumber, typeNumber
+		case "<", "<=", ">", ">=":
+			operand, result = typeNumber, typeBool
+		case "==", "!=":
+			if lt != rt || lt == typeSet {
+				return 0, p.errorf(n.pos, "无法比较%s与%s", lt, rt)
+			}
+			return typeBool, nil
+		}
+		if lt != operand {
+			return 0, p.errorf(n.l.position(), "%q 的左侧需要%s，实际为%s", n.op, operand, lt)
+		}
+		if rt != operand {
+			return 0, p.errorf(n.r.position(), "%q 的右侧需要%s，实际为%s", n.op, operand, rt)
+		}
+		return result, nil
+	case *callExpr:
+		fn, ok := exprFuncs[n.name]
+		if !ok {
+			return 0, p.errorf(n.pos, "未知的函数 %q", n.name)
+		}
+		if len(n.args) < fn.minArgs || len(n.args) > len(fn.params) {
+			return 0, p.errorf(n.pos, "函数 %s 需要 %d-%d 个参数，实际为 %d 个", n.name, fn.minArgs, len(fn.params), len(n.args))
+		}
+		for i, arg := range n.args {
+			t, err := p.check(arg)
+			if err != nil {
+				return 0, err
+			}
+			if t != fn.params[i] {
+				return 0, p.errorf(arg.position(), "函数 %s 的第 %d 个参数需要%s，实际为%s", n.name, i+1, fn.params[i], t)
+			}
+		}
+		if err := p.checkNameArg(n); err != nil {
+			return 0, err
+		}
+		return fn.ret, nil
+	}
+	return 0, p.errorf(n.position(), "无法识别的表达式")
+}
+
+// checkNameArg 检查 has()、const()、refine() 引用的角色或武器是否在规则集中
+func (p *exprParser) checkNameArg(n *callExpr) error {
+	lit, ok := n.args[0].(*stringLit)
+	if !ok {
+		return nil
+	}
+	switch n.name {
+	case "has", "const":
+		if _, ok := p.characters[lit.value]; !ok {
+			return p.errorf(lit.pos, "未知的角色 %q", lit.value)
+		}
+	case "refine":
+		if _, ok := p.weapons[lit.value]; !ok {
+			return p.errorf(lit.pos, "未知的武器 %q", lit.value)
+		}
+	}
+	return nil
+}
+
+// ---------- 求值 ----------
+
+type exprValue struct {
+	num float64
+	b   bool
+	str string
+	set map[string]bool
+}
+
+type exprEnv struct {
+	account    eval.Assets
+	characters map[string]CharacterInfo // 规则集中的角色属性
+}
+
+func (env *exprEnv) eval(n exprNode) exprValue {
+	switch n := n.(type) {
+	case *numberLit:
+		return exprValue{num: n.value}
+	case *stringLit:
+		return exprValue{str: n.value}
+	case *boolLit:
+		return exprValue{b: n.value}
+	case *identExpr:
+		minConst := exprSetIdents[n.name]
+		set := make(map[string]bool)
+		for name, c := range env.account.Characters {
+			if c >= minConst {
+				set[name] = true
+			}
+		}
+		return exprValue{set: set}
+	case *unaryExpr:
+		x := env.eval(n.x)
+		if n.op == "not" {
+			return exprValue{b: !x.b}
+		}
+		return exprValue{num: -x.num}
+	case *binaryExpr:
+		l := env.eval(n.l)
+		// and/or 短路求值
+		switch n.op {
+		case "and":
+			if !l.b {
+				return exprValue{b: false}
+			}
+			return exprValue{b: env.eval(n.r).b}
+		case "or":
+			if l.b {
+				return exprValue{b: true}
+			}
+			return exprValue{b: env.eval(n.r).b}
+		}
+		r := env.eval(n.r)
+		switch n.op {
+		case "in":
+			set := make(map[string]bool)
+			for name := range l.set {
+				if r.set[name] {
+					set[name] = true
+				}
+			}
+			return exprValue{set: set}
+		case "+":
+			return exprValue{num: l.num + r.num}
+		case "-":
+			return exprValue{num: l.num - r.num}
+		case "*":
+			return exprValue{num: l.num * r.num}
+		case "/":
+			if r.num == 0 {
+				return exprValue{num: 0}
+			}
+			return exprValue{num: l.num / r.num}
+		case "<":
+			return exprValue{b: l.num < r.num}
+		case "<=":
+			return exprValue{b: l.num <= r.num}
+		case ">":
+			return exprValue{b: l.num > r.num}
+		case ">=":
+			return exprValue{b: l.num >= r.num}
+		case "==":
+			return exprValue{b: l.num == r.num && l.b == r.b && l.str == r.str}
+		case "!=":
+			return exprValue{b: l.num != r.num || l.b != r.b || l.str != r.str}
+		}
+	case *callExpr:
+		args := make([]exprValue, len(n.args))
+		for i, arg := range n.args {
+			args[i] = env.eval(arg)
+		}
+		return exprFuncs[n.name].call(env, args)
+	}
+	return exprValue{}
+}
+
+// ---------- 规则语句 ----------
+
+// ExprAction 表示规则语句的动作种类
+type ExprAction int
+
+const (
+	ActionBonus ExprAction = iota
+	ActionWeaponMultiplier
+)
+
+// ExprRule 是编译后的规则语句
+type ExprRule struct {
+	Name    string
+	Source  string
+	cond    exprNode
+	Action  ExprAction
+	Amount  float64  // 附加价值或乘数
+	Weapons []string // 武器乘数作用的武器
+
+	characters map[string]CharacterInfo // 编译时所在规则集的角色，供 region() 等属性函数使用
+}
+
+// CompileCondition 编译一个布尔条件表达式，引用的角色、武器须在规则集 r 中，属性函数按 r 中的角色求值
+func CompileCondition(name, src string, r ValuationRules) (*ExprRule, error) {
+	p, err := newExprParser(name, src, r)
+	if err != nil {
+		return nil, err
+	}
+	cond, err := p.parseCondition()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t.pos, "条件之后不应出现 %s", describeToken(t))
+	}
+	return &ExprRule{Name: name, Source: src, cond: cond, characters: r.Characters}, nil
+}
+
+// CompileRule 编译一条 "条件 => 动作" 规则语句，条件与 weapon() 引用的角色、武器须在规则集 r 中
+func CompileRule(name, src string, r ValuationRules) (*ExprRule, error) {
+	p, err := newExprParser(name, src, r)
+	if err != nil {
+		return nil, err
+	}
+	cond, err := p.parseCondition()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expectOp("=>"); err != nil {
+		return nil, err
+	}
+	rule := &ExprRule{Name: name, Source: src, cond: cond, characters: r.Characters}
+
+	t := p.next()
+	switch {
+	case t.kind == tokOp && (t.text == "+" || t.text == "-"):
+		n := p.next()
+		if n.kind != tokNumber {
+			return nil, p.errorf(n.pos, "附加价值需要数字，实际为 %s", describeToken(n))
+		}
+		rule.Action, rule.Amount = ActionBonus, n.num
+		if t.text == "-" {
+			rule.Amount = -n.num
+		}
+	case t.kind == tokIdent && t.text == "weapon":
+		rule.Action = ActionWeaponMultiplier
+		if p.isOp("(") {
+			p.next()
+			w := p.next()
+			if w.kind != tokString {
+				return nil, p.errorf(w.pos, "weapon() 需要武器名字符串，实际为 %s", describeToken(w))
+			}
+			if _, ok := r.Weapons[w.text]; !ok {
+				return nil, p.errorf(w.pos, "未知的武器 %q", w.text)
+			}
+			rule.Weapons = []string{w.text}
+			if err := p.expectOp(")"); err != nil {
+				return nil, err
+			}
+		} else {
+			rule.Weapons = referencedWeapons(cond)
+			if len(rule.Weapons) == 0 {
+				return nil, p.errorf(t.pos, "weapon 动作未指定武器，且条件中没有 refine() 引用的武器")
+			}
+		}
+		x := p.next()
+		if x.kind != tokTimes {
+			return nil, p.errorf(x.pos, "武器乘数需要形如 x2，实际为 %s", describeToken(x))
+		}
+		n := p.next()
+		if n.kind != tokNumber {
+			return nil, p.errorf(n.pos, "武器乘数需要数字，实际为 %s", describeToken(n))
+		}
+		rule.Amount = n.num
+	default:
+		return nil, p.errorf(t.pos, "无法识别的动作 %s，支持 +N、-N、weapon xN", describeToken(t))
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, p.errorf(t.pos, "动作之后不应出现 %s", describeToken(t))
+	}
+	return rule, nil
+}
+
+func newExprParser(name, src string, r ValuationRules) (*exprParser, error) {
+	tokens, err := lexExpr(name, src)
+	if err != nil {
+		return nil, err
+	}
+	return &exprParser{rule: name, src: src, tokens: tokens, characters: r.Characters, weapons: r.Weapons}, nil
+}
+
+func (p *exprParser) parseCondition() (exprNode, error) {
+	if p.peek().kind == tokEOF {
+		return nil, p.errorf(p.peek().pos, "缺少条件表达式")
+	}
+	cond, err := p.parseExpr(1)
+	if err != nil {
+		return nil, err
+	}
+	t, err := p.check(cond)
+	if err != nil {
+		return nil, err
+	}
+	if t != typeBool {
+		return nil, p.errorf(cond.position(), "条件需要布尔值，实际为%s", t)
+	}
+	return cond, nil
+}
+
+// referencedWeapons 收集条件中通过 refine() 引用的武器
+func referencedWeapons(n exprNode) []string {
+	seen := make(map[string]bool)
+	var walk func(exprNode)
+	walk = func(n exprNode) {
+		switch n := n.(type) {
+		case *unaryExpr:
+			walk(n.x)
+		case *binaryExpr:
+			walk(n.l)
+			walk(n.r)
+		case *callExpr:
+			if n.name == "refine" && len(n.args) == 1 {
+				if s, ok := n.args[0].(*stringLit); ok {
+					seen[s.value] = true
+				}
+			}
+			for _, arg := range n.args {
+				walk(arg)
+			}
+		}
+	}
+	walk(n)
+	weapons := make([]string, 0, len(seen))
+	for w := range seen {
+		weapons = append(weapons, w)
+	}
+	sort.Strings(weapons)
+	return weapons
+}
+
+// Matches 判断账号是否满足规则条件
+func (r *ExprRule) Matches(account eval.Assets) bool {
+	env := &exprEnv{account: account, characters: r.characters}
+	return env.eval(r.cond).b
+}
+
+// weaponMultiplier 是规则语句或组合对某把武器施加的价格乘数
+type weaponMultiplier struct {
+	Rule   string
+	Factor float64
+}
+
+// exprWeaponMultipliers 收集特殊规则中条件成立的武器乘数
+func exprWeaponMultipliers(account eval.Assets, set *ruleSet) map[string][]weaponMultiplier {
+	result := make(map[string][]weaponMultiplier)
+	for _, special := range set.specials {
+		expr, ok := special.(*exprSpecialRule)
+		if !ok || expr.rule.Action != ActionWeaponMultiplier || !expr.rule.Matches(account) {
+			continue
+		}
+		for _, w := range expr.rule.Weapons {
+			result[w] = append(result[w], weaponMultiplier{Rule: expr.rule.Name, Factor: expr.rule.Amount})
+		}
+	}
+	return result
+}
+
+// ValidateRules 编译规则集中的全部表达式并检查估值流程，返回所有错误
+func ValidateRules(r ValuationRules) []error {
+	var errs []error
+	for _, combo := range r.Combos {
+		if combo.SharingDiscount < 0 || combo.SharingDiscount > 1 {
+			errs = append(errs, fmt.Errorf("组合 %q 的共享折扣 %.2f 超出 0-1 范围", combo.Name, combo.SharingDiscount))
+		}
+		for _, name := range combo.ShareableChars {
+			if !comboRequires(combo, name) {
+				errs = append(errs, fmt.Errorf("组合 %q 声明可共享的角色 %q 不在组合要求中", combo.Name, name))
+			}
+		}
+		if combo.Condition == "" {
+			continue
+		}
+		if _, err := CompileCondition(combo.Name, combo.Condition, r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, cfg := range r.SpecialRules {
+		if _, err := buildSpecialRule(cfg, r); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, cfg := range r.Pipeline {
+		if _, err := buildStage(cfg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, promo := range r.Promotions {
+		errs = append(errs, validatePromotion(promo, r)...)
+	}
+	if len(r.Promotions) > 0 && !hasPromoStage(r.Pipeline) {
+		errs = append(errs, fmt.Errorf("规则含有促销，但估值流程中没有 promo 阶段"))
+	}
+	return errs
+}
+
+// comboRequires 判断组合的固定要求或替代要求组中是否包含角色
+func comboRequires(combo ComboRule, name string) bool {
+	for _, req := range combo.RequiredChars {
+		if req.Name == name {
+			return true
+		}
+	}
+	for _, group := range combo.RequiredGroups {
+		for _, opt := range group.Options {
+			if opt.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package newrule
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+func TestCompileRule_Evaluate(t *testing.T) {
+	account := eval.Assets{
+		Characters: map[string]int{"玛薇卡": 6, "茜特菈莉": 6, "基尼奇": 6, "芙宁娜": 6},
+		Weapons:    map[string]int{"焚曜千阳": 5},
+	}
+
+	cases := []struct {
+		src    string
+		match  bool
+		action ExprAction
+		amount float64
+	}{
+		{`count(c6 in region("纳塔")) >= 3 => +500`, true, ActionBonus, 500},
+		{`count(c6 in region("纳塔")) >= 4 => +500`, false, ActionBonus, 500},
+		{`has("玛薇卡", 6) and refine("焚曜千阳") == 5 => weapon x2`, true, ActionWeaponMultiplier, 2},
+		{`not has("丝柯克") || const("芙宁娜") < 2 => -100`, true, ActionBonus, -100},
+	}
+	for _, c := range cases {
+		rule, err := CompileRule("测试", c.src, rules)
+		if err != nil {
+			t.Fatalf("CompileRule(%q): %v", c.src, err)
+		}
+		if got := rule.Matches(account); got != c.match {
+			t.Errorf("%q: Matches = %v, want %v", c.src, got, c.match)
+		}
+		if rule.Action != c.action || rule.Amount != c.amount {
+			t.Errorf("%q: action = %v %.2f, want %v %.2f", c.src, rule.Action, rule.Amount, c.action, c.amount)
+		}
+	}
+}
+
+func TestCompileRule_Errors(t *testing.T) {
+	cases := []struct {
+		src string
+		pos int
+	}{
+		{`count(c6) >= "3" => +1`, 13},
+		{`has(6) => +1`, 4},
+		{`unknown("a") => +1`, 0},
+		{`count(c6) => +1`, 0},
+		{`has("玛薇卡") => weapon x2`, 14},
+		{`has("玛薇卡") => +`, 15},
+		{`has("玛微卡", 6) => +1`, 4},
+		{`refine("焚耀千阳") >= 1 => +1`, 7},
+		{`const("不存在") > 2 => +1`, 6},
+	}
+	for _, c := range cases {
+		_, err := CompileRule("错误规则", c.src, rules)
+		var exprErr *ExprError
+		if !errors.As(err, &exprErr) {
+			t.Fatalf("CompileRule(%q): expected ExprError, got %v", c.src, err)
+		}
+		if exprErr.Pos != c.pos || exprErr.Rule != "错误规则" {
+			t.Errorf("CompileRule(%q): error at %d, want %d\n%v", c.src, exprErr.Pos, c.pos, err)
+		}
+	}
+}
+
+func TestCompileRule_WeaponsFromRuleSet(t *testing.T) {
+	src := `has("玛薇卡") => weapon("焚曜千阳") x2`
+	if _, err := CompileRule("武器规则", src, rules); err != nil {
+		t.Fatalf("CompileRule: %v", err)
+	}
+	// weapon() 按传入的规则集检查，而不是内置规则
+	if _, err := CompileRule("武器规则", src, ValuationRules{Characters: rules.Characters}); err == nil {
+		t.Error("weapon missing from the given rule set should be rejected")
+	}
+}
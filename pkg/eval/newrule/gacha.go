@@ -0,0 +1,156 @@
+package newrule
+
+// BannerKind 区分角色池与武器池
+type BannerKind int
+
+const (
+	CharacterBanner BannerKind = iota
+	WeaponBanner
+)
+
+// BannerModel 描述卡池的出金概率模型 (公开的软保底模型)
+type BannerModel struct {
+	Kind          BannerKind `json:"kind"`
+	BaseRate      float64    `json:"baseRate"`      // 基础五星概率
+	SoftPityStart int        `json:"softPityStart"` // 软保底起始抽数，从该抽起每抽概率提升
+	SoftPityStep  float64    `json:"softPityStep"`  // 软保底每抽提升的概率
+	HardPity      int        `json:"hardPity"`      // 硬保底抽数
+	FeaturedRate  float64    `json:"featuredRate"`  // 非大保底时五星为UP的概率
+
+	// 角色池: 连续歪的次数达到该值后必定捕获明光，0 表示不启用
+	RadianceThreshold int `json:"radianceThreshold,omitempty"`
+
+	// 武器池: UP武器中命中定轨武器的概率与命定值上限
+	TargetRate    float64 `json:"targetRate,omitempty"`
+	MaxFatePoints int     `json:"maxFatePoints,omitempty"`
+}
+
+// DefaultCharacterBanner 角色活动祈愿的概率模型
+var DefaultCharacterBanner = BannerModel{
+	Kind:              CharacterBanner,
+	BaseRate:          0.006,
+	SoftPityStart:     74,
+	SoftPityStep:      0.06,
+	HardPity:          90,
+	FeaturedRate:      0.5,
+	RadianceThreshold: 3,
+}
+
+// DefaultWeaponBanner 武器活动祈愿的概率模型
+var DefaultWeaponBanner = BannerModel{
+	Kind:          WeaponBanner,
+	BaseRate:      0.007,
+	SoftPityStart: 63,
+	SoftPityStep:  0.07,
+	HardPity:      80,
+	FeaturedRate:  0.75,
+	TargetRate:    0.5,
+	MaxFatePoints: 1,
+}
+
+// bannerState 是卡池在某一时刻的状态
+// Counter 在角色池中为连续歪的次数，在武器池中为命定值
+type bannerState struct {
+	Pity       int
+	Guaranteed bool
+	Counter    int
+}
+
+// bannerOutcome 是出五星后的一种可能结果
+type bannerOutcome struct {
+	Prob   float64
+	Target bool
+	Next   bannerState
+}
+
+// Rate 返回距离上个五星第 pull 抽 (从1开始) 的五星概率
+func (m BannerModel) Rate(pull int) float64 {
+	if pull >= m.HardPity {
+		return 1
+	}
+	rate := m.BaseRate
+	if pull >= m.SoftPityStart {
+		rate += m.SoftPityStep * float64(pull-m.SoftPityStart+1)
+	}
+	if rate > 1 {
+		return 1
+	}
+	return rate
+}
+
+// normalize 将外部传入的卡池状态限制在模型允许的范围内
+func (m BannerModel) normalize(s bannerState) bannerState {
+	if s.Pity < 0 {
+		s.Pity = 0
+	}
+	if s.Pity > m.HardPity-1 {
+		s.Pity = m.HardPity - 1
+	}
+	if s.Counter < 0 {
+		s.Counter = 0
+	}
+	if m.Kind == WeaponBanner && s.Counter > m.MaxFatePoints {
+		s.Counter = m.MaxFatePoints
+	}
+	if m.Kind == CharacterBanner && m.RadianceThreshold > 0 && s.Counter > m.RadianceThreshold {
+		s.Counter = m.RadianceThreshold
+	}
+	return s
+}
+
+// outcomes 列出出五星时的所有可能结果及其概率
+func (m BannerModel) outcomes(s bannerState) []bannerOutcome {
+	if m.Kind == WeaponBanner {
+		if s.Counter >= m.MaxFatePoints {
+			return []bannerOutcome{{Prob: 1, Target: true, Next: bannerState{}}}
+		}
+		featured := m.FeaturedRate
+		if s.Guaranteed {
+			featured = 1
+		}
+		miss := bannerState{Counter: s.Counter + 1}
+		out := []bannerOutcome{
+			{Prob: featured * m.TargetRate, Target: true, Next: bannerState{}},
+			{Prob: featured * (1 - m.TargetRate), Next: miss},
+		}
+		if featured < 1 {
+			miss.Guaranteed = true
+			out = append(out, bannerOutcome{Prob: 1 - featured, Next: miss})
+		}
+		return out
+	}
+
+	if s.Guaranteed {
+		return []bannerOutcome{{Prob: 1, Target: true, Next: bannerState{Counter: s.Counter}}}
+	}
+	if m.RadianceThreshold > 0 && s.Counter >= m.RadianceThreshold {
+		return []bannerOutcome{{Prob: 1, Target: true, Next: bannerState{}}}
+	}
+	return []bannerOutcome{
+		{Prob: m.FeaturedRate, Target: true, Next: bannerState{}},
+		{Prob: 1 - m.FeaturedRate, Next: bannerState{Guaranteed: true, Counter: s.Counter + 1}},
+	}
+}
+
+// ExpectedTargets 计算从给定状态起抽 pulls 次后，获得目标五星的期望数量
+func (m BannerModel) ExpectedTargets(start bannerState, pulls int) float64 {
+	dist := map[bannerState]float64{m.normalize(start): 1}
+	expected := 0.0
+	for i := 0; i < pulls; i++ {
+		next := make(map[bannerState]float64, len(dist))
+		for s, p := range dist {
+			rate := m.Rate(s.Pity + 1)
+			if rate < 1 {
+				next[bannerState{Pity: s.Pity + 1, Guaranteed: s.Guaranteed, Counter: s.Counter}] += p * (1 - rate)
+			}
+			for _, o := range m.outcomes(s) {
+				if o.Target {
+					expected += p * rate * o.Prob
+				}
+				next[o.Next] += p * rate * o.Prob
+			}
+		}
+		dist = next
+	}
+	return expected
+}
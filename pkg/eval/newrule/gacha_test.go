@@ -0,0 +1,70 @@
+package newrule
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+func TestExpectedTargets_HardPity(t *testing.T) {
+	// 已垫89抽且大保底，下一抽必出UP
+	got := DefaultCharacterBanner.ExpectedTargets(bannerState{Pity: 89, Guaranteed: true}, 1)
+	if math.Abs(got-1) > 1e-9 {
+		t.Errorf("Expected 1 target, got %.4f", got)
+	}
+}
+
+func TestCalculateResourceValue_Expectation(t *testing.T) {
+	r := rules
+	r.ResourceValueMode = ResourceValueByExpectation
+	set := newRuleWith(t, r).rules
+
+	fresh := eval.Assets{JiuChanZhiYuan: 300}
+	primed := eval.Assets{JiuChanZhiYuan: 300, CharacterPity: 75, CharacterGuaranteed: true}
+
+	freshValue, _ := calculateResourceValue(fresh, set, time.Time{}, nil)
+	primedValue, breakdown := calculateResourceValue(primed, set, time.Time{}, nil)
+	t.Log(breakdown)
+	if primedValue <= freshValue {
+		t.Errorf("Expected primed account (%.2f) to be worth more than fresh account (%.2f)", primedValue, freshValue)
+	}
+}
+
+func TestSimulatePulls_Deterministic(t *testing.T) {
+	cfg := rules.MonteCarlo
+	cfg.Trials = 500
+	cfg.TargetCharacter = "玛薇卡"
+	cfg.TargetWeapon = "焚曜千阳"
+	account := eval.Assets{
+		Characters:     map[string]int{"玛薇卡": 2},
+		JiuChanZhiYuan: 400,
+		CharacterPity:  30,
+	}
+
+	first := New().SimulatePulls(account, cfg)
+	second := New().SimulatePulls(account, cfg)
+	if first.ExpectedGain != second.ExpectedGain || first.Percentiles[50] != second.Percentiles[50] {
+		t.Errorf("Expected identical results for the same seed, got %.2f and %.2f", first.ExpectedGain, second.ExpectedGain)
+	}
+	if first.ExpectedGain <= 0 {
+		t.Errorf("Expected a positive valuation gain, got %.2f", first.ExpectedGain)
+	}
+	if first.Percentiles[5] > first.Percentiles[95] {
+		t.Errorf("Expected P5 <= P95, got %.2f > %.2f", first.Percentiles[5], first.Percentiles[95])
+	}
+
+	// JSON 字段名与 ValuationReport 一致，使用小驼峰
+	data, err := json.Marshal(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{`"trials":500`, `"expectedGain":`, `"constellationOf":`, `"percentiles":`} {
+		if !strings.Contains(string(data), key) {
+			t.Errorf("Expected %s in JSON, got %s", key, data)
+		}
+	}
+}
@@ -0,0 +1,323 @@
+package newrule
+
+//go:generate go run ../../../cmd/combo-lint -dot ../../../docs/combos.dot
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ComboLintIssue 是组合规则一致性检查发现的一个问题
+type ComboLintIssue struct {
+	Kind    string // "不可达"、"重复"、"价值倒挂"、"冗余"
+	Combo   string
+	Related []string // 相关的其他组合
+	Detail  string
+}
+
+func (i ComboLintIssue) String() string {
+	if len(i.Related) == 0 {
+		return fmt.Sprintf("[%s] %s: %s", i.Kind, i.Combo, i.Detail)
+	}
+	return fmt.Sprintf("[%s] %s (相关: %s): %s", i.Kind, i.Combo, strings.Join(i.Related, ", "), i.Detail)
+}
+
+// ComboLattice 是组合之间的子集/超集关系
+// Edges 只保留直接的包含关系 (A ⊂ B 且不存在 C 使 A ⊂ C ⊂ B)，边从子集指向超集
+type ComboLattice struct {
+	Combos []ComboRule
+	Edges  [][2]int
+
+	subset [][]bool // subset[i][j]: 组合 i 的要求被组合 j 蕴含，即 j 是 i 的超集
+}
+
+// charImplied 判断角色要求 req 是否被 reqs 中更严格的同名要求蕴含
+func charImplied(req RequiredChar, reqs []RequiredChar) bool {
+	for _, r := range reqs {
+		if r.Name == req.Name && r.MinConst >= req.MinConst && r.MaxConst <= req.MaxConst {
+			return true
+		}
+	}
+	return false
+}
+
+// groupImplied 判断替代要求组是否被另一组合蕴含: 对方有相同的要求组，或对方的固定要求已满足足够多的选项
+func groupImplied(group RequirementGroup, other ComboRule) bool {
+	met := 0
+	for _, opt := range group.Options {
+		if charImplied(opt, other.RequiredChars) {
+			met++
+		}
+	}
+	if met >= group.MinMatch {
+		return true
+	}
+	for _, g := range other.RequiredGroups {
+		if g.MinMatch >= group.MinMatch && len(g.Options) == len(group.Options) {
+			same := true
+			for _, opt := range g.Options {
+				if !charImplied(opt, group.Options) {
+					same = false
+					break
+				}
+			}
+			if same {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// comboImplies 判断满足组合 sup 的账号是否一定满足组合 sub
+// 属性要求、武器要求与附加条件需完全相同才视为蕴含
+func comboImplies(sup, sub ComboRule) bool {
+	for _, req := range sub.RequiredChars {
+		if !charImplied(req, sup.RequiredChars) {
+			return false
+		}
+	}
+	for _, group := range sub.RequiredGroups {
+		if !groupImplied(group, sup) {
+			return false
+		}
+	}
+	if sub.Condition != "" && sub.Condition != sup.Condition {
+		return false
+	}
+	for _, attr := range sub.RequiredAttributes {
+		found := false
+		for _, a := range sup.RequiredAttributes {
+			if a == attr {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for _, req := range sub.RequiredWeapons {
+		found := false
+		for _, w := range sup.RequiredWeapons {
+			if w.Name == req.Name && w.MinRefine >= req.MinRefine && w.MaxRefine <= req.MaxRefine {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// BuildComboLattice 计算组合之间的包含关系
+func BuildComboLattice(combos []ComboRule) *ComboLattice {
+	n := len(combos)
+	l := &ComboLattice{Combos: combos, subset: make([][]bool, n)}
+	for i := range combos {
+		l.subset[i] = make([]bool, n)
+		for j := range combos {
+			l.subset[i][j] = i != j && comboImplies(combos[j], combos[i])
+		}
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if !l.strictSubset(i, j) {
+				continue
+			}
+			direct := true
+			for k := 0; k < n; k++ {
+				if l.strictSubset(i, k) && l.strictSubset(k, j) {
+					direct = false
+					break
+				}
+			}
+			if direct {
+				l.Edges = append(l.Edges, [2]int{i, j})
+			}
+		}
+	}
+	return l
+}
+
+// strictSubset 判断组合 i 是否为组合 j 的真子集
+func (l *ComboLattice) strictSubset(i, j int) bool {
+	return l.subset[i][j] && !l.subset[j][i]
+}
+
+// comboChars 返回组合可能使用的角色，替代要求组的全部选项都计入
+func comboChars(combo ComboRule) map[string]bool {
+	chars := make(map[string]bool, len(combo.RequiredChars))
+	for _, req := range combo.RequiredChars {
+		chars[req.Name] = true
+	}
+	for _, group := range combo.RequiredGroups {
+		for _, opt := range group.Options {
+			chars[opt.Name] = true
+		}
+	}
+	return chars
+}
+
+// disjointCover 在候选子组合中寻找角色互不重叠、价值之和不低于 target 的至少两个组合
+func disjointCover(combos []ComboRule, candidates []int, target float64) []int {
+	var best []int
+	var search func(start int, used map[string]bool, chosen []int, sum float64) bool
+	search = func(start int, used map[string]bool, chosen []int, sum float64) bool {
+		if len(chosen) >= 2 && sum >= target {
+			best = append([]int{}, chosen...)
+			return true
+		}
+		for k := start; k < len(candidates); k++ {
+			chars := comboChars(combos[candidates[k]])
+			overlap := false
+			for name := range chars {
+				if used[name] {
+					overlap = true
+					break
+				}
+			}
+			if overlap {
+				continue
+			}
+			for name := range chars {
+				used[name] = true
+			}
+			found := search(k+1, used, append(chosen, candidates[k]), sum+combos[candidates[k]].Value)
+			for name := range chars {
+				delete(used, name)
+			}
+			if found {
+				return true
+			}
+		}
+		return false
+	}
+	search(0, make(map[string]bool), nil, 0)
+	return best
+}
+
+// unreachableReasons 返回组合永远无法被满足的原因
+func unreachableReasons(combo ComboRule) []string {
+	var reasons []string
+	seen := make(map[string]RequiredChar)
+	checkChar := func(req RequiredChar) {
+		if req.MinConst > req.MaxConst || req.MinConst > 6 || req.MaxConst < 0 {
+			reasons = append(reasons, fmt.Sprintf("角色 %s 的命座要求 %d-%d 无法满足", req.Name, req.MinConst, req.MaxConst))
+		}
+	}
+	for _, req := range combo.RequiredChars {
+		checkChar(req)
+		if prev, dup := seen[req.Name]; dup && (req.MinConst > prev.MaxConst || prev.MinConst > req.MaxConst) {
+			reasons = append(reasons, fmt.Sprintf("角色 %s 的两条命座要求 %d-%d 与 %d-%d 互斥", req.Name, prev.MinConst, prev.MaxConst, req.MinConst, req.MaxConst))
+		}
+		seen[req.Name] = req
+	}
+	for _, group := range combo.RequiredGroups {
+		for _, opt := range group.Options {
+			checkChar(opt)
+		}
+		if group.MinMatch < 1 || group.MinMatch > len(group.Options) {
+			reasons = append(reasons, fmt.Sprintf("替代要求组需满足 %d 项，但只有 %d 个选项", group.MinMatch, len(group.Options)))
+		}
+	}
+	for _, attr := range combo.RequiredAttributes {
+		if attr.Count < 1 || attr.MinConst > attr.MaxConst {
+			reasons = append(reasons, fmt.Sprintf("属性要求 %s 无法满足", attr))
+		}
+	}
+	for _, req := range combo.RequiredWeapons {
+		if req.MinRefine > req.MaxRefine || req.MinRefine > 5 || req.MaxRefine < 1 {
+			reasons = append(reasons, fmt.Sprintf("武器 %s 的精炼要求 %d-%d 无法满足", req.Name, req.MinRefine, req.MaxRefine))
+		}
+	}
+	return reasons
+}
+
+// LintCombos 检查组合规则的一致性:
+//   - 不可达: 组合的要求永远无法满足，如 MinConst > MaxConst
+//   - 重复: 两个组合的要求互相蕴含
+//   - 价值倒挂: 超集组合的价值低于其子集组合
+//   - 冗余: 超集组合的价值不高于若干角色互不重叠的子集组合之和，最优方案总会选择后者
+func LintCombos(combos []ComboRule) []ComboLintIssue {
+	var issues []ComboLintIssue
+	for _, combo := range combos {
+		for _, reason := range unreachableReasons(combo) {
+			issues = append(issues, ComboLintIssue{Kind: "不可达", Combo: combo.Name, Detail: reason})
+		}
+	}
+
+	l := BuildComboLattice(combos)
+	for i := range combos {
+		for j := i + 1; j < len(combos); j++ {
+			if l.subset[i][j] && l.subset[j][i] {
+				issues = append(issues, ComboLintIssue{Kind: "重复", Combo: combos[i].Name, Related: []string{combos[j].Name},
+					Detail: fmt.Sprintf("要求相同，价值分别为 %.0f 与 %.0f", combos[i].Value, combos[j].Value)})
+			}
+		}
+	}
+
+	for j, sup := range combos {
+		var subsets []int
+		for i, sub := range combos {
+			if !l.strictSubset(i, j) {
+				continue
+			}
+			subsets = append(subsets, i)
+			if sup.Value < sub.Value {
+				issues = append(issues, ComboLintIssue{Kind: "价值倒挂", Combo: sup.Name, Related: []string{sub.Name},
+					Detail: fmt.Sprintf("超集价值 %.0f 低于子集价值 %.0f", sup.Value, sub.Value)})
+			}
+		}
+		if cover := disjointCover(combos, subsets, sup.Value); cover != nil {
+			var names []string
+			var sum float64
+			for _, k := range cover {
+				names = append(names, combos[k].Name)
+				sum += combos[k].Value
+			}
+			issues = append(issues, ComboLintIssue{Kind: "冗余", Combo: sup.Name, Related: names,
+				Detail: fmt.Sprintf("价值 %.0f 不高于互不重叠的子集组合之和 %.0f", sup.Value, sum)})
+		}
+	}
+	return issues
+}
+
+// DOT 以 Graphviz DOT 格式导出组合的包含关系，按分组聚类，价值倒挂的边标红
+func (l *ComboLattice) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph combos {\n")
+	sb.WriteString("  rankdir=LR;\n")
+	sb.WriteString("  node [shape=box, fontname=\"sans-serif\"];\n")
+
+	groups := make(map[string][]int)
+	var groupNames []string
+	for i, combo := range l.Combos {
+		if _, ok := groups[combo.Group]; !ok {
+			groupNames = append(groupNames, combo.Group)
+		}
+		groups[combo.Group] = append(groups[combo.Group], i)
+	}
+	sort.Strings(groupNames)
+	for n, group := range groupNames {
+		fmt.Fprintf(&sb, "  subgraph cluster_%d {\n", n)
+		fmt.Fprintf(&sb, "    label=%q;\n", group)
+		for _, i := range groups[group] {
+			fmt.Fprintf(&sb, "    c%d [label=%q];\n", i, fmt.Sprintf("%s\n%.0f", l.Combos[i].Name, l.Combos[i].Value))
+		}
+		sb.WriteString("  }\n")
+	}
+	for _, e := range l.Edges {
+		sub, sup := l.Combos[e[0]], l.Combos[e[1]]
+		if sup.Value < sub.Value {
+			fmt.Fprintf(&sb, "  c%d -> c%d [color=red, label=%q];\n", e[0], e[1], fmt.Sprintf("%+.0f", sup.Value-sub.Value))
+		} else {
+			fmt.Fprintf(&sb, "  c%d -> c%d [label=%q];\n", e[0], e[1], fmt.Sprintf("%+.0f", sup.Value-sub.Value))
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
@@ -0,0 +1,101 @@
+package newrule
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLintCombos(t *testing.T) {
+	c6 := func(name string) RequiredChar { return RequiredChar{Name: name, MinConst: 6, MaxConst: 6} }
+	combos := []ComboRule{
+		{Name: "6甲", Value: 300, RequiredChars: []RequiredChar{c6("甲")}},
+		{Name: "6乙", Value: 200, RequiredChars: []RequiredChar{c6("乙")}},
+		{Name: "6甲+6乙", Value: 500, RequiredChars: []RequiredChar{c6("甲"), c6("乙")}},
+		{Name: "6甲+6乙+6丙", Value: 250, RequiredChars: []RequiredChar{c6("甲"), c6("乙"), c6("丙")}},
+		{Name: "6甲(重复)", Value: 300, RequiredChars: []RequiredChar{c6("甲")}},
+		{Name: "7丁", Value: 100, RequiredChars: []RequiredChar{{Name: "丁", MinConst: 7, MaxConst: 6}}},
+	}
+
+	kinds := make(map[string][]string)
+	for _, issue := range LintCombos(combos) {
+		kinds[issue.Kind] = append(kinds[issue.Kind], issue.Combo)
+	}
+	if got := kinds["不可达"]; len(got) != 1 || got[0] != "7丁" {
+		t.Errorf("Unexpected unreachable combos: %v", got)
+	}
+	if got := kinds["重复"]; len(got) != 1 || got[0] != "6甲" {
+		t.Errorf("Unexpected duplicate combos: %v", got)
+	}
+	if got := kinds["冗余"]; len(got) != 2 || got[0] != "6甲+6乙" || got[1] != "6甲+6乙+6丙" {
+		t.Errorf("Unexpected redundant combos: %v", got)
+	}
+	inverted := false
+	for _, name := range kinds["价值倒挂"] {
+		if name != "6甲+6乙+6丙" {
+			t.Errorf("Unexpected inversion on %s", name)
+		}
+		inverted = true
+	}
+	if !inverted {
+		t.Error("Expected the superset worth less than its subsets to be flagged")
+	}
+
+	dot := BuildComboLattice(combos).DOT()
+	if !strings.HasPrefix(dot, "digraph combos {") || !strings.Contains(dot, "color=red") {
+		t.Errorf("Unexpected DOT output:\n%s", dot)
+	}
+}
+
+func TestLintCombos_Groups(t *testing.T) {
+	c6 := func(name string) RequiredChar { return RequiredChar{Name: name, MinConst: 6, MaxConst: 6} }
+	combos := []ComboRule{
+		{Name: "6甲+6乙/6丙", Value: 300, RequiredChars: []RequiredChar{c6("甲")},
+			RequiredGroups: []RequirementGroup{{Options: []RequiredChar{c6("乙"), c6("丙")}, MinMatch: 1}}},
+		{Name: "6乙", Value: 200, RequiredChars: []RequiredChar{c6("乙")}},
+		{Name: "6甲+6乙", Value: 400, RequiredChars: []RequiredChar{c6("甲"), c6("乙")}},
+	}
+	l := BuildComboLattice(combos)
+	if !l.strictSubset(0, 2) || !l.strictSubset(1, 2) || l.strictSubset(1, 0) {
+		t.Errorf("Unexpected lattice edges: %v", l.Edges)
+	}
+	// 替代要求组可能选用乙，因此不能与 "6乙" 拼成不重叠的覆盖
+	for _, issue := range LintCombos(combos) {
+		if issue.Kind == "冗余" {
+			t.Errorf("Unexpected issue: %v", issue)
+		}
+	}
+}
+
+// knownComboLintIssues 是内置规则中已知、暂予保留的检查结果，键为 "类型|组合|相关组合"
+// 调整组合价值会改变现有报价，修正前在此逐条列出；新增的问题或已修正的条目都会使测试失败
+var knownComboLintIssues = []string{
+	"重复|6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6恰斯卡+6芙宁娜+爱可菲+茜特菈莉+希诺宁|6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6芙宁娜+6恰斯卡+爱可菲+茜特菈莉+希诺宁",
+	"重复|6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6恰斯卡+6芙宁娜|6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6芙宁娜+6恰斯卡",
+	"重复|6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6茜特菈莉+爱可菲+希诺宁|6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6茜特菈莉+希诺宁+爱可菲",
+	"重复|6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6茜特菈莉(独立)|6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6茜特菈莉",
+	"重复|6丝柯克+6玛薇卡+6茜特菈莉+爱可菲+希诺宁|6丝柯克+6玛薇卡+6茜特菈莉+爱可菲+希诺宁",
+	"重复|6丝柯克+6玛薇卡+6茜特菈莉(base)|6丝柯克+6玛薇卡+6茜特菈莉",
+	"价值倒挂|6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6恰斯卡+6茜特菈莉+希诺宁+爱可菲|6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6茜特菈莉+希诺宁+爱可菲",
+	"价值倒挂|6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6恰斯卡+6茜特菈莉+希诺宁+爱可菲|6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6茜特菈莉",
+	"价值倒挂|6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6恰斯卡+6茜特菈莉|6丝柯克+6玛薇卡+6那维莱特+6阿蕾奇诺+6茜特菈莉",
+	"价值倒挂|6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6芙宁娜+6恰斯卡+爱可菲+茜特菈莉+希诺宁|6丝柯克+6玛薇卡+6那维莱特/6阿蕾奇诺+6恰斯卡+6芙宁娜",
+}
+
+func TestLintCombos_Builtin(t *testing.T) {
+	known := make(map[string]bool)
+	for _, key := range knownComboLintIssues {
+		known[key] = true
+	}
+	for _, issue := range LintCombos(New().Rules().Combos) {
+		key := issue.Kind + "|" + issue.Combo + "|" + strings.Join(issue.Related, ",")
+		if !known[key] {
+			t.Errorf("Unexpected lint issue: %s", issue)
+		}
+		delete(known, key)
+	}
+	for _, key := range knownComboLintIssues {
+		if known[key] {
+			t.Errorf("Known lint issue no longer reported, remove it from knownComboLintIssues: %s", key)
+		}
+	}
+}
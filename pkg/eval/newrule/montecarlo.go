@@ -0,0 +1,204 @@
+package newrule
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+// MonteCarloRules 蒙特卡洛抽卡模拟的参数
+type MonteCarloRules struct {
+	Seed            uint64      `json:"seed"`                   // 随机种子，相同种子得到相同结果
+	Trials          int         `json:"trials"`                 // 模拟次数
+	TargetCharacter string      `json:"targetCharacter"`        // 目标UP角色
+	TargetWeapon    string      `json:"targetWeapon,omitempty"` // 目标定轨武器，为空则不抽武器池
+	CharacterBanner BannerModel `json:"characterBanner"`
+	WeaponBanner    BannerModel `json:"weaponBanner"`
+}
+
+// MonteCarloResult 蒙特卡洛模拟的结果
+type MonteCarloResult struct {
+	Trials          int             `json:"trials"`
+	Pulls           int             `json:"pulls"`
+	ConstellationOf map[int]int     `json:"constellationOf"` // 模拟结束时目标角色命座 -> 次数，-1 表示未获得
+	RefinementOf    map[int]int     `json:"refinementOf"`    // 模拟结束时目标武器精炼 -> 次数，0 表示未获得
+	ExpectedGain    float64         `json:"expectedGain"`    // 估值增量的期望
+	Percentiles     map[int]float64 `json:"percentiles"`     // 估值增量的分位数
+}
+
+var monteCarloPercentiles = []int{5, 25, 50, 75, 95}
+
+// pull 模拟一次抽卡，返回新的卡池状态以及是否获得目标五星
+func (m BannerModel) pull(s bannerState, rng *rand.Rand) (bannerState, bool) {
+	if rng.Float64() >= m.Rate(s.Pity+1) {
+		s.Pity++
+		return s, false
+	}
+	roll := rng.Float64()
+	outcomes := m.outcomes(s)
+	for _, o := range outcomes {
+		if roll < o.Prob {
+			return o.Next, o.Target
+		}
+		roll -= o.Prob
+	}
+	last := outcomes[len(outcomes)-1]
+	return last.Next, last.Target
+}
+
+// SimulatePulls 对账号的剩余抽数进行蒙特卡洛模拟，模拟结果按当前规则与当前时间估值
+// 先在角色池抽目标角色至满命，剩余抽数再投入武器池抽目标武器至满精
+func (n *NewRule) SimulatePulls(account eval.Assets, cfg MonteCarloRules) MonteCarloResult {
+	return simulatePulls(account, cfg, n.rules, time.Now())
+}
+
+func simulatePulls(account eval.Assets, cfg MonteCarloRules, set *ruleSet, at time.Time) MonteCarloResult {
+	pulls := totalFates(account)
+	result := MonteCarloResult{
+		Trials:          cfg.Trials,
+		Pulls:           pulls,
+		ConstellationOf: make(map[int]int),
+		RefinementOf:    make(map[int]int),
+		Percentiles:     make(map[int]float64),
+	}
+	if cfg.Trials <= 0 {
+		return result
+	}
+
+	startConst := -1
+	if c, ok := account.Characters[cfg.TargetCharacter]; ok {
+		startConst = c
+	}
+	startRefine := account.Weapons[cfg.TargetWeapon]
+
+	// 模拟结果只取决于最终命座和精炼，估值按结果缓存
+	base := assetValue(account, startConst, startRefine, cfg, set, at)
+	gainCache := make(map[[2]int]float64)
+	gainOf := func(constellation, refine int) float64 {
+		key := [2]int{constellation, refine}
+		if g, ok := gainCache[key]; ok {
+			return g
+		}
+		g := assetValue(account, constellation, refine, cfg, set, at) - base
+		gainCache[key] = g
+		return g
+	}
+
+	rng := rand.New(rand.NewPCG(cfg.Seed, cfg.Seed))
+	gains := make([]float64, 0, cfg.Trials)
+	total := 0.0
+	for i := 0; i < cfg.Trials; i++ {
+		remaining := pulls
+		constellation := startConst
+		charState := cfg.CharacterBanner.normalize(bannerState{Pity: account.CharacterPity, Guaranteed: account.CharacterGuaranteed})
+		for remaining > 0 && constellation < 6 && cfg.TargetCharacter != "" {
+			var hit bool
+			charState, hit = cfg.CharacterBanner.pull(charState, rng)
+			if hit {
+				constellation++
+			}
+			remaining--
+		}
+
+		refine := startRefine
+		weaponState := cfg.WeaponBanner.normalize(bannerState{Pity: account.WeaponPity, Guaranteed: account.WeaponGuaranteed, Counter: account.FatePoints})
+		for remaining > 0 && refine < 5 && cfg.TargetWeapon != "" {
+			var hit bool
+			weaponState, hit = cfg.WeaponBanner.pull(weaponState, rng)
+			if hit {
+				refine++
+			}
+			remaining--
+		}
+
+		result.ConstellationOf[constellation]++
+		result.RefinementOf[refine]++
+		g := gainOf(constellation, refine)
+		gains = append(gains, g)
+		total += g
+	}
+
+	sort.Float64s(gains)
+	result.ExpectedGain = total / float64(cfg.Trials)
+	for _, p := range monteCarloPercentiles {
+		idx := p * (len(gains) - 1) / 100
+		result.Percentiles[p] = gains[idx]
+	}
+	return result
+}
+
+// assetValue 计算目标角色/武器替换为指定命座/精炼后，账号不含资源的估值
+func assetValue(account eval.Assets, constellation, refine int, cfg MonteCarloRules, set *ruleSet, at time.Time) float64 {
+	modified := account
+	modified.YuanShi = 0
+	modified.JiuChanZhiYuan = 0
+	modified.Characters = make(map[string]int, len(account.Characters)+1)
+	for k, v := range account.Characters {
+		modified.Characters[k] = v
+	}
+	modified.Weapons = make(map[string]int, len(account.Weapons)+1)
+	for k, v := range account.Weapons {
+		modified.Weapons[k] = v
+	}
+	if cfg.TargetCharacter != "" && constellation >= 0 {
+		modified.Characters[cfg.TargetCharacter] = constellation
+	}
+	if cfg.TargetWeapon != "" && refine > 0 {
+		modified.Weapons[cfg.TargetWeapon] = refine
+	}
+	state, _ := runPipeline(modified, set, at)
+	return state.Total
+}
+
+// calculateMonteCarloResourceValue 以蒙特卡洛模拟的期望估值增量作为资源价值
+func calculateMonteCarloResourceValue(account eval.Assets, set *ruleSet, at time.Time) (float64, string) {
+	cfg := set.MonteCarlo
+	pulls := totalFates(account)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "账号总资源: %d 原石 + %d 纠缠之源 = %d 总抽数\n", account.YuanShi, account.JiuChanZhiYuan, pulls)
+	if pulls == 0 || cfg.TargetCharacter == "" {
+		sb.WriteString("无剩余抽数或未配置目标卡池，不计价。\n")
+		return 0, sb.String()
+	}
+
+	result := simulatePulls(account, cfg, set, at)
+	fmt.Fprintf(&sb, "目标卡池: %s", cfg.TargetCharacter)
+	if cfg.TargetWeapon != "" {
+		fmt.Fprintf(&sb, " + %s", cfg.TargetWeapon)
+	}
+	fmt.Fprintf(&sb, ", 模拟 %d 次 (种子 %d)\n", result.Trials, cfg.Seed)
+
+	sb.WriteString("  - 角色命座分布:\n")
+	for c := -1; c <= 6; c++ {
+		if n := result.ConstellationOf[c]; n > 0 {
+			label := fmt.Sprintf("%d命", c)
+			if c < 0 {
+				label = "未获得"
+			}
+			fmt.Fprintf(&sb, "      %s: %.2f%%\n", label, float64(n)*100/float64(result.Trials))
+		}
+	}
+	if cfg.TargetWeapon != "" {
+		sb.WriteString("  - 武器精炼分布:\n")
+		for r := 0; r <= 5; r++ {
+			if n := result.RefinementOf[r]; n > 0 {
+				label := fmt.Sprintf("精%d", r)
+				if r == 0 {
+					label = "未获得"
+				}
+				fmt.Fprintf(&sb, "      %s: %.2f%%\n", label, float64(n)*100/float64(result.Trials))
+			}
+		}
+	}
+	sb.WriteString("  - 估值增量分位数:")
+	for _, p := range monteCarloPercentiles {
+		fmt.Fprintf(&sb, " P%d=%.2f", p, result.Percentiles[p])
+	}
+	sb.WriteString("\n")
+	fmt.Fprintf(&sb, "资源总价值 (期望估值增量): %.2f\n", result.ExpectedGain)
+	return result.ExpectedGain, sb.String()
+}
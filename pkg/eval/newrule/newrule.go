@@ -2,69 +2,279 @@ package newrule
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/sdojjy/genshin-value-rule/pkg/eval"
 )
 
 type NewRule struct {
+	rules *ruleSet
 }
 
+// New 使用当前加载的内置规则，规则在此编译一次，内置规则有误时 panic
 func New() *NewRule {
-	return &NewRule{}
+	set, err := compileRuleSet(rules, BuiltinVersion)
+	if err != nil {
+		panic(err)
+	}
+	return &NewRule{rules: set}
 }
 
 // CharacterInfo 存储角色的价格和专武信息
 type CharacterInfo struct {
-	Name              string
-	Prices            [7]float64 // 0命到6命的价格
-	SpecializedWeapon string
+	Name              string     `json:"name"`
+	Prices            [7]float64 `json:"prices"` // 0命到6命的价格
+	SpecializedWeapon string     `json:"specializedWeapon,omitempty"`
+
+	// 角色属性，供组合与特殊规则按属性匹配，未知时为空
+	Region     string `json:"region,omitempty"`     // 地区
+	Element    string `json:"element,omitempty"`    // 元素
+	WeaponType string `json:"weaponType,omitempty"` // 武器类型
+	Version    string `json:"version,omitempty"`    // 首次实装版本
+
+	Provenance Provenance `json:"provenance,omitzero"`
 }
 
 // WeaponInfo 存储武器的价格信息
 type WeaponInfo struct {
-	Name   string
-	Prices [5]float64 // 精1到精5的价格
+	Name   string     `json:"name"`
+	Prices [5]float64 `json:"prices"` // 精1到精5的价格
+
+	Provenance Provenance `json:"provenance,omitzero"`
 }
 
 // RequiredChar 定义了溢价组合中对角色的要求
 type RequiredChar struct {
-	Name     string
-	MinConst int // 最小命座要求
-	MaxConst int // 最大命座要求
+	Name     string `json:"name"`
+	MinConst int    `json:"minConst"` // 最小命座要求
+	MaxConst int    `json:"maxConst"` // 最大命座要求
+}
+
+// Satisfied 判断账号是否满足该角色要求
+func (req RequiredChar) Satisfied(account eval.Assets) bool {
+	constellation, ok := account.Characters[req.Name]
+	return ok && constellation >= req.MinConst && constellation <= req.MaxConst
+}
+
+// String 以组合名称中的写法描述角色要求，如 "6玛薇卡"、"2-5丝柯克"
+func (req RequiredChar) String() string {
+	switch {
+	case req.MinConst == 0 && req.MaxConst == 6:
+		return req.Name
+	case req.MinConst == req.MaxConst:
+		return fmt.Sprintf("%d%s", req.MinConst, req.Name)
+	default:
+		return fmt.Sprintf("%d-%d%s", req.MinConst, req.MaxConst, req.Name)
+	}
+}
+
+// RequiredWeapon 定义了溢价组合中对武器的要求
+type RequiredWeapon struct {
+	Name      string `json:"name"`
+	MinRefine int    `json:"minRefine"` // 最小精炼要求
+	MaxRefine int    `json:"maxRefine"` // 最大精炼要求
+}
+
+// Satisfied 判断账号是否满足该武器要求
+func (req RequiredWeapon) Satisfied(account eval.Assets) bool {
+	refine, ok := account.Weapons[req.Name]
+	return ok && refine >= req.MinRefine && refine <= req.MaxRefine
+}
+
+// String 以组合名称中的写法描述武器要求，如 "精5焚曜千阳"
+func (req RequiredWeapon) String() string {
+	if req.MinRefine == req.MaxRefine {
+		return fmt.Sprintf("精%d%s", req.MinRefine, req.Name)
+	}
+	return fmt.Sprintf("精%d-%d%s", req.MinRefine, req.MaxRefine, req.Name)
+}
+
+// AttributeRequirement 定义了按角色属性匹配的要求，如 "3个6命纳塔角色"
+// 属性为空表示不限，Version 按前缀匹配 (如 "5" 匹配所有 5.x 版本角色)
+type AttributeRequirement struct {
+	Region     string `json:"region,omitempty"`
+	Element    string `json:"element,omitempty"`
+	WeaponType string `json:"weaponType,omitempty"`
+	Version    string `json:"version,omitempty"`
+	MinConst   int    `json:"minConst"`
+	MaxConst   int    `json:"maxConst"`
+	Count      int    `json:"count"` // 需要的角色数量
+}
+
+// Matches 判断角色是否具备要求的属性
+func (req AttributeRequirement) Matches(info CharacterInfo) bool {
+	return (req.Region == "" || info.Region == req.Region) &&
+		(req.Element == "" || info.Element == req.Element) &&
+		(req.WeaponType == "" || info.WeaponType == req.WeaponType) &&
+		(req.Version == "" || strings.HasPrefix(info.Version, req.Version))
+}
+
+// candidates 返回账号中满足属性与命座要求的角色，按角色名排序
+func (req AttributeRequirement) candidates(account eval.Assets, characters map[string]CharacterInfo) []RequiredChar {
+	var result []RequiredChar
+	for name, constellation := range account.Characters {
+		info, ok := characters[name]
+		if !ok || !req.Matches(info) || constellation < req.MinConst || constellation > req.MaxConst {
+			continue
+		}
+		result = append(result, RequiredChar{Name: name, MinConst: req.MinConst, MaxConst: req.MaxConst})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// String 描述属性要求，如 "3个6命纳塔角色"
+func (req AttributeRequirement) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d个", req.Count)
+	switch {
+	case req.MinConst == req.MaxConst:
+		fmt.Fprintf(&sb, "%d命", req.MinConst)
+	case req.MinConst > 0 || req.MaxConst < 6:
+		fmt.Fprintf(&sb, "%d-%d命", req.MinConst, req.MaxConst)
+	}
+	if req.Version != "" {
+		fmt.Fprintf(&sb, "%s版本", req.Version)
+	}
+	sb.WriteString(req.Region)
+	sb.WriteString(req.Element)
+	if req.WeaponType != "" {
+		sb.WriteString(req.WeaponType)
+	}
+	sb.WriteString("角色")
+	return sb.String()
+}
+
+// RequirementGroup 定义了一组可相互替代的角色要求，需满足其中至少 MinMatch 个
+// 组合按账号满足的每种选法各展开为一条，估值与逐条列出各选法的写法相同
+type RequirementGroup struct {
+	Options  []RequiredChar `json:"options"`
+	MinMatch int            `json:"minMatch"` // 1 表示满足其一即可
 }
 
 // ComboRule 定义了一条溢价组合规则
 type ComboRule struct {
-	Name          string
-	Value         float64
-	RequiredChars []RequiredChar
+	Name           string             `json:"name"`
+	Group          string             `json:"group"` // 组合所属分组，如 "纳塔满命溢价组合"
+	Value          float64            `json:"value"`
+	RequiredChars  []RequiredChar     `json:"requiredChars"`
+	RequiredGroups []RequirementGroup `json:"requiredGroups,omitempty"` // 可选的替代要求组
+	Condition      string             `json:"condition,omitempty"`      // 可选的附加条件表达式，见 expr.go
+
+	// 可选的属性要求，如 "任意3个6命纳塔角色"，命中时选用的具体角色记入 MatchedAlternatives
+	RequiredAttributes []AttributeRequirement `json:"requiredAttributes,omitempty"`
+
+	// 可选的武器要求，以及命中组合时组合自定义的武器价格乘数 (武器名 -> 乘数)
+	// 定义了乘数的武器不再适用默认的专武翻倍规则
+	RequiredWeapons   []RequiredWeapon   `json:"requiredWeapons,omitempty"`
+	WeaponMultipliers map[string]float64 `json:"weaponMultipliers,omitempty"`
+
+	// 可与其他组合共享的角色，以及共享角色时本组合价值的折扣比例
+	// 角色只有在两个组合都声明可共享时才能共享，且最多同时计入两个组合，由其中一方按折扣计价
+	ShareableChars  []string `json:"shareableChars,omitempty"`
+	SharingDiscount float64  `json:"sharingDiscount,omitempty"`
+
+	Provenance Provenance `json:"provenance,omitzero"`
+
+	// 命中时由 findSatisfiedCombos 填写: 替代要求组中实际选用的要求
+	MatchedAlternatives []RequiredChar `json:"-"`
+	// 选中时由 findBestComboSelection 填写: 与其他组合共享、由本组合按折扣计价的角色
+	SharedChars []string `json:"-"`
+}
+
+// canShare 判断组合是否声明角色可共享
+func (combo ComboRule) canShare(name string) bool {
+	for _, n := range combo.ShareableChars {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectiveValue 返回组合计入的附加价值，共享了角色时按折扣计算
+func (combo ComboRule) EffectiveValue() float64 {
+	if len(combo.SharedChars) == 0 {
+		return combo.Value
+	}
+	return combo.Value * (1 - combo.SharingDiscount)
+}
+
+// ResourceValueMode 资源价值的计价方式
+type ResourceValueMode int
+
+const (
+	// ResourceValueByTier 按总抽数分档计价
+	ResourceValueByTier ResourceValueMode = iota
+	// ResourceValueByExpectation 结合垫抽与保底状态，按期望获得的五星计价
+	ResourceValueByExpectation
+	// ResourceValueByMonteCarlo 对目标卡池进行蒙特卡洛模拟，按期望估值增量计价
+	ResourceValueByMonteCarlo
+)
+
+// ExpectedResourceRules 期望计价模式的参数
+type ExpectedResourceRules struct {
+	CharacterBanner BannerModel `json:"characterBanner"`
+	WeaponBanner    BannerModel `json:"weaponBanner"`
+	CharacterPrice  float64     `json:"characterPrice"` // 每个期望UP角色的价格
+	WeaponPrice     float64     `json:"weaponPrice"`    // 每把期望定轨武器的价格
+	WeaponShare     float64     `json:"weaponShare"`    // 投入武器池的抽数比例
 }
 
 // ValuationRules 包含所有估值规则
 type ValuationRules struct {
-	Characters map[string]CharacterInfo
-	Weapons    map[string]WeaponInfo
-	Combos     []ComboRule
+	Characters map[string]CharacterInfo `json:"characters"`
+	Weapons    map[string]WeaponInfo    `json:"weapons"`
+	Combos     []ComboRule              `json:"combos"`
 
 	// 角色数量溢价规则
-	CharCountMultiplierTiers []struct {
-		MinCount int
-		MaxCount int
-		Factor   float64
-	}
+	CharCountMultiplierTiers []CharCountTier `json:"charCountMultiplierTiers"`
+	CharCountMultiplierMode  TierMode        `json:"charCountMultiplierMode"`
+	CharCountPolicy          CharCountPolicy `json:"charCountPolicy"`
 
 	// 资源价值规则
-	ResourceValueTiers []struct {
-		MinFates int
-		Price    float64
-	}
+	ResourceValueTiers    []ResourceTier        `json:"resourceValueTiers"`
+	ResourceValueTierMode TierMode              `json:"resourceValueTierMode"`
+	ResourceValueMode     ResourceValueMode     `json:"resourceValueMode"`
+	ExpectedResourceValue ExpectedResourceRules `json:"expectedResourceValue"`
+	MonteCarlo            MonteCarloRules       `json:"monteCarlo"`
+
+	// 命中组合内6命角色的精5专武价格乘数
+	SignatureWeaponComboMultiplier float64 `json:"signatureWeaponComboMultiplier"`
 
 	// 特殊规则相关角色列表
-	HotC6CharsT1     []string // 第一梯队 (+300, 但命中月国满命溢价时不再+300)
-	HotC6CharsT2     []string // 第二梯队 (+200)
-	SpecialC2C5Chars []string
+	HotC6CharsT1     []string `json:"hotC6CharsT1"` // 第一梯队 (+300, 但命中豁免地区满命溢价时不再+300)
+	HotC6CharsT2     []string `json:"hotC6CharsT2"` // 第二梯队 (+200)
+	SpecialC2C5Chars []string `json:"specialC2C5Chars"`
+	// 包含这些地区6命角色的组合视为该地区满命溢价组合，组合内第一梯队角色不再+300
+	HotC6T1ExemptRegions []string `json:"hotC6T1ExemptRegions"`
+
+	// 按顺序应用的特殊规则
+	SpecialRules []SpecialRuleConfig `json:"specialRules"`
+
+	// 估值流程，按顺序执行
+	Pipeline []StageConfig `json:"pipeline"`
+
+	// 限时促销，估值时按估值时间生效
+	Promotions []Promotion `json:"promotions,omitempty"`
+
+	// 角色分类，用于角色数量计数，与价格表分开维护
+	LimitedFiveStarChars  []string `json:"limitedFiveStarChars"`
+	StandardFiveStarChars []string `json:"standardFiveStarChars"`
+	FourStarChars         []string `json:"fourStarChars"`
+}
+
+// SortCombos 按价值降序排列组合，同价值的组合按名称排序，使估值结果不依赖组合在数据中的书写顺序
+func SortCombos(combos []ComboRule) {
+	sort.SliceStable(combos, func(i, j int) bool {
+		if combos[i].Value != combos[j].Value {
+			return combos[i].Value > combos[j].Value
+		}
+		return combos[i].Name < combos[j].Name
+	})
 }
 
 // 全局变量，存储加载后的所有规则
@@ -75,70 +285,77 @@ func loadValuationRules() ValuationRules {
 	r := ValuationRules{}
 	// 角色价格表 [cite: 22, 228]
 	r.Characters = map[string]CharacterInfo{
-		"杜林":    {Name: "杜林", Prices: [7]float64{5, 10, 80, 90, 100, 200, 500}, SpecializedWeapon: "黑蚀"},
-		"伊涅芙":   {Name: "伊涅芙", Prices: [7]float64{5, 10, 80, 90, 40, 100, 500}, SpecializedWeapon: "支离轮光"},
-		"丝柯克":   {Name: "丝柯克", Prices: [7]float64{5, 10, 80, 90, 100, 200, 500}, SpecializedWeapon: "苍耀"},  // 550->500
-		"爱可菲":   {Name: "爱可菲", Prices: [7]float64{5, 10, 50, 55, 60, 100, 400}, SpecializedWeapon: "香韵奏者"},
-		"瓦雷莎":   {Name: "瓦雷莎", Prices: [7]float64{5, 10, 80, 90, 100, 200, 600}, SpecializedWeapon: "溢彩心念"}, // 800->600
-		"茜特菈莉":  {Name: "茜特菈莉", Prices: [7]float64{5, 10, 50, 55, 60, 100, 400}, SpecializedWeapon: "祭星者之望"},
-		"玛薇卡":   {Name: "玛薇卡", Prices: [7]float64{5, 10, 80, 90, 100, 200, 500}, SpecializedWeapon: "焚曜千阳"}, // 550->500
-		"恰斯卡":   {Name: "恰斯卡", Prices: [7]float64{5, 10, 50, 60, 70, 100, 600}, SpecializedWeapon: "星鹫赤羽"},  // 650->600
-		"希诺宁":   {Name: "希诺宁", Prices: [7]float64{5, 10, 50, 55, 60, 100, 300}, SpecializedWeapon: "岩峰巡歌"},
-		"基尼奇":   {Name: "基尼奇", Prices: [7]float64{5, 10, 50, 60, 70, 100, 380}, SpecializedWeapon: "山王长牙"},
-		"玛拉妮":   {Name: "玛拉妮", Prices: [7]float64{5, 10, 50, 60, 70, 100, 380}, SpecializedWeapon: "冲浪时光"},
-		"艾梅莉埃":  {Name: "艾梅莉埃", Prices: [7]float64{5, 10, 20, 25, 30, 50, 200}, SpecializedWeapon: "柔灯挽歌"},
-		"克洛琳德":  {Name: "克洛琳德", Prices: [7]float64{5, 10, 25, 30, 35, 50, 360}, SpecializedWeapon: "赦罪"},
-		"阿蕾奇诺":  {Name: "阿蕾奇诺", Prices: [7]float64{5, 10, 25, 30, 35, 100, 380}, SpecializedWeapon: "赤月之形"},
-		"希格雯":   {Name: "希格雯", Prices: [7]float64{5, 10, 15, 20, 25, 50, 200}, SpecializedWeapon: "白雨心弦"},
-		"千织":    {Name: "千织", Prices: [7]float64{5, 10, 15, 20, 25, 30, 300}, SpecializedWeapon: "有乐御簾切"},
-		"闲云":    {Name: "闲云", Prices: [7]float64{5, 10, 25, 30, 35, 40, 200}, SpecializedWeapon: "鹤鸣余音"},
-		"娜维娅":   {Name: "娜维娅", Prices: [7]float64{5, 10, 15, 20, 25, 30, 200}, SpecializedWeapon: "裁断"},
-		"芙宁娜":   {Name: "芙宁娜", Prices: [7]float64{5, 10, 30, 35, 40, 80, 250}, SpecializedWeapon: "静水流涌之辉"},
-		"那维莱特":  {Name: "那维莱特", Prices: [7]float64{5, 10, 15, 20, 25, 80, 300}, SpecializedWeapon: "万世流涌大典"},
-		"莱欧斯利":  {Name: "莱欧斯利", Prices: [7]float64{5, 10, 15, 20, 25, 30, 250}, SpecializedWeapon: "金流监督"},
-		"林尼":    {Name: "林尼", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "最初的大魔术"},
-		"白术":    {Name: "白术", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "碧落之珑"},
-		"艾尔海森":  {Name: "艾尔海森", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "裁叶萃光"},
-		"流浪者":   {Name: "流浪者", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "图莱杜拉的回忆"},
-		"纳西妲":   {Name: "纳西妲", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "千夜浮梦"},
-		"赛诺":    {Name: "赛诺", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "赤沙之杖"},
-		"妮露":    {Name: "妮露", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "圣显之钥"},
-		"神里绫人":  {Name: "神里绫人", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "波乱月白经津"},
-		"申鹤":    {Name: "申鹤", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "息灾"},
-		"夜兰":    {Name: "夜兰", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "若水"},
-		"八重神子":  {Name: "八重神子", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "神乐之真意"},
-		"荒泷一斗":  {Name: "荒泷一斗", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "赤角石溃杵"},
-		"珊瑚宫心海": {Name: "珊瑚宫心海", Prices: [7]float64{5, 10, 15, 20, 25, 30, 100}, SpecializedWeapon: "不灭月华"},
-		"雷电将军":  {Name: "雷电将军", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "薙草之稻光"},
-		"优菈":    {Name: "优菈", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "松籁响起之时"},
-		"宵宫":    {Name: "宵宫", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "飞雷之弦振"},
-		"枫原万叶":  {Name: "枫原万叶", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "苍古自由之誓"},
-		"胡桃":    {Name: "胡桃", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "护摩之杖"},
-		"甘雨":    {Name: "甘雨", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "阿莫斯之弓"},
-		"达达利亚":  {Name: "达达利亚", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "冬极白星"},
-		"钟离":    {Name: "钟离", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "贯虹之槊"},
-		"魈":     {Name: "魈", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "和璞鸢"},
-		"可莉":    {Name: "可莉", Prices: [7]float64{5, 10, 15, 20, 25, 30, 100}, SpecializedWeapon: "四风原典"},
-		"温迪":    {Name: "温迪", Prices: [7]float64{5, 10, 15, 20, 25, 30, 180}, SpecializedWeapon: "终末嗟叹之诗"},
-		"菈乌玛":   {Name: "菈乌玛", Prices: [7]float64{5, 10, 80, 90, 100, 200, 400}, SpecializedWeapon: "纺夜天镜"},
-		"菲林斯":   {Name: "菲林斯", Prices: [7]float64{5, 10, 80, 90, 100, 200, 500}, SpecializedWeapon: "血染荒城"},
-		"奈芙尔":   {Name: "奈芙尔", Prices: [7]float64{5, 10, 80, 90, 100, 200, 650}, SpecializedWeapon: "真语秘匣"},
-		"哥伦比娅":  {Name: "哥伦比娅", Prices: [7]float64{5, 10, 80, 90, 100, 200, 500}, SpecializedWeapon: "帷间夜曲"},
-		"兹白":    {Name: "兹白", Prices: [7]float64{5, 10, 80, 90, 100, 200, 550}, SpecializedWeapon: "朏魄含光"},
-		"法尔伽":   {Name: "法尔伽", Prices: [7]float64{5, 10, 80, 90, 100, 200, 600}, SpecializedWeapon: "狼的武功歌"},
-		"莉奈娅":   {Name: "莉奈娅", Prices: [7]float64{5, 10, 80, 90, 100, 200, 500}, SpecializedWeapon: "霜结的誓金枝"},
+		"杜林":    {Name: "杜林", Prices: [7]float64{5, 10, 80, 90, 100, 200, 500}, SpecializedWeapon: "黑蚀", Region: "蒙德", Element: "火", WeaponType: "单手剑", Version: "6.2"},
+		"伊涅芙":   {Name: "伊涅芙", Prices: [7]float64{5, 10, 80, 90, 40, 100, 500}, SpecializedWeapon: "支离轮光", Region: "月国", Element: "雷", WeaponType: "长柄武器", Version: "5.8"},
+		"丝柯克":   {Name: "丝柯克", Prices: [7]float64{5, 10, 80, 90, 100, 200, 500}, SpecializedWeapon: "苍耀", Element: "冰", WeaponType: "单手剑", Version: "5.7"}, // 不属于七国，不设地区
+		"爱可菲":   {Name: "爱可菲", Prices: [7]float64{5, 10, 50, 55, 60, 100, 400}, SpecializedWeapon: "香韵奏者", Region: "枫丹", Element: "冰", WeaponType: "长柄武器", Version: "5.6"},
+		"瓦雷莎":   {Name: "瓦雷莎", Prices: [7]float64{5, 10, 80, 90, 100, 200, 600}, SpecializedWeapon: "溢彩心念", Region: "纳塔", Element: "雷", WeaponType: "法器", Version: "5.5"},
+		"茜特菈莉":  {Name: "茜特菈莉", Prices: [7]float64{5, 10, 50, 55, 60, 100, 400}, SpecializedWeapon: "祭星者之望", Region: "纳塔", Element: "冰", WeaponType: "法器", Version: "5.3"},
+		"玛薇卡":   {Name: "玛薇卡", Prices: [7]float64{5, 10, 80, 90, 100, 200, 500}, SpecializedWeapon: "焚曜千阳", Region: "纳塔", Element: "火", WeaponType: "双手剑", Version: "5.3"},
+		"恰斯卡":   {Name: "恰斯卡", Prices: [7]float64{5, 10, 50, 60, 70, 100, 600}, SpecializedWeapon: "星鹫赤羽", Region: "纳塔", Element: "风", WeaponType: "弓", Version: "5.2"},
+		"希诺宁":   {Name: "希诺宁", Prices: [7]float64{5, 10, 50, 55, 60, 100, 300}, SpecializedWeapon: "岩峰巡歌", Region: "纳塔", Element: "岩", WeaponType: "单手剑", Version: "5.1"},
+		"基尼奇":   {Name: "基尼奇", Prices: [7]float64{5, 10, 50, 60, 70, 100, 380}, SpecializedWeapon: "山王长牙", Region: "纳塔", Element: "草", WeaponType: "双手剑", Version: "5.0"},
+		"玛拉妮":   {Name: "玛拉妮", Prices: [7]float64{5, 10, 50, 60, 70, 100, 380}, SpecializedWeapon: "冲浪时光", Region: "纳塔", Element: "水", WeaponType: "法器", Version: "5.0"},
+		"艾梅莉埃":  {Name: "艾梅莉埃", Prices: [7]float64{5, 10, 20, 25, 30, 50, 200}, SpecializedWeapon: "柔灯挽歌", Region: "枫丹", Element: "草", WeaponType: "长柄武器", Version: "4.8"},
+		"克洛琳德":  {Name: "克洛琳德", Prices: [7]float64{5, 10, 25, 30, 35, 50, 360}, SpecializedWeapon: "赦罪", Region: "枫丹", Element: "雷", WeaponType: "单手剑", Version: "4.7"},
+		"阿蕾奇诺":  {Name: "阿蕾奇诺", Prices: [7]float64{5, 10, 25, 30, 35, 100, 380}, SpecializedWeapon: "赤月之形", Region: "至冬", Element: "火", WeaponType: "长柄武器", Version: "4.6"},
+		"希格雯":   {Name: "希格雯", Prices: [7]float64{5, 10, 15, 20, 25, 50, 200}, SpecializedWeapon: "白雨心弦", Region: "枫丹", Element: "水", WeaponType: "弓", Version: "4.7"},
+		"千织":    {Name: "千织", Prices: [7]float64{5, 10, 15, 20, 25, 30, 300}, SpecializedWeapon: "有乐御簾切", Region: "稻妻", Element: "岩", WeaponType: "单手剑", Version: "4.5"},
+		"闲云":    {Name: "闲云", Prices: [7]float64{5, 10, 25, 30, 35, 40, 200}, SpecializedWeapon: "鹤鸣余音", Region: "璃月", Element: "风", WeaponType: "法器", Version: "4.4"},
+		"娜维娅":   {Name: "娜维娅", Prices: [7]float64{5, 10, 15, 20, 25, 30, 200}, SpecializedWeapon: "裁断", Region: "枫丹", Element: "岩", WeaponType: "双手剑", Version: "4.3"},
+		"芙宁娜":   {Name: "芙宁娜", Prices: [7]float64{5, 10, 30, 35, 40, 80, 250}, SpecializedWeapon: "静水流涌之辉", Region: "枫丹", Element: "水", WeaponType: "单手剑", Version: "4.2"},
+		"那维莱特":  {Name: "那维莱特", Prices: [7]float64{5, 10, 15, 20, 25, 80, 300}, SpecializedWeapon: "万世流涌大典", Region: "枫丹", Element: "水", WeaponType: "法器", Version: "4.1"},
+		"莱欧斯利":  {Name: "莱欧斯利", Prices: [7]float64{5, 10, 15, 20, 25, 30, 250}, SpecializedWeapon: "金流监督", Region: "枫丹", Element: "冰", WeaponType: "法器", Version: "4.1"},
+		"林尼":    {Name: "林尼", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "最初的大魔术", Region: "枫丹", Element: "火", WeaponType: "弓", Version: "4.0"},
+		"白术":    {Name: "白术", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "碧落之珑", Region: "璃月", Element: "草", WeaponType: "法器", Version: "3.6"},
+		"艾尔海森":  {Name: "艾尔海森", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "裁叶萃光", Region: "须弥", Element: "草", WeaponType: "单手剑", Version: "3.4"},
+		"流浪者":   {Name: "流浪者", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "图莱杜拉的回忆", Region: "须弥", Element: "风", WeaponType: "法器", Version: "3.3"},
+		"纳西妲":   {Name: "纳西妲", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "千夜浮梦", Region: "须弥", Element: "草", WeaponType: "法器", Version: "3.2"},
+		"赛诺":    {Name: "赛诺", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "赤沙之杖", Region: "须弥", Element: "雷", WeaponType: "长柄武器", Version: "3.1"},
+		"妮露":    {Name: "妮露", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "圣显之钥", Region: "须弥", Element: "水", WeaponType: "单手剑", Version: "3.1"},
+		"神里绫人":  {Name: "神里绫人", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "波乱月白经津", Region: "稻妻", Element: "水", WeaponType: "单手剑", Version: "2.6"},
+		"申鹤":    {Name: "申鹤", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "息灾", Region: "璃月", Element: "冰", WeaponType: "长柄武器", Version: "2.4"},
+		"夜兰":    {Name: "夜兰", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "若水", Region: "璃月", Element: "水", WeaponType: "弓", Version: "2.7"},
+		"八重神子":  {Name: "八重神子", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "神乐之真意", Region: "稻妻", Element: "雷", WeaponType: "法器", Version: "2.5"},
+		"荒泷一斗":  {Name: "荒泷一斗", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "赤角石溃杵", Region: "稻妻", Element: "岩", WeaponType: "双手剑", Version: "2.3"},
+		"珊瑚宫心海": {Name: "珊瑚宫心海", Prices: [7]float64{5, 10, 15, 20, 25, 30, 100}, SpecializedWeapon: "不灭月华", Region: "稻妻", Element: "水", WeaponType: "法器", Version: "2.1"},
+		"雷电将军":  {Name: "雷电将军", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "薙草之稻光", Region: "稻妻", Element: "雷", WeaponType: "长柄武器", Version: "2.1"},
+		"优菈":    {Name: "优菈", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "松籁响起之时", Region: "蒙德", Element: "冰", WeaponType: "双手剑", Version: "1.5"},
+		"宵宫":    {Name: "宵宫", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "飞雷之弦振", Region: "稻妻", Element: "火", WeaponType: "弓", Version: "2.0"},
+		"枫原万叶":  {Name: "枫原万叶", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "苍古自由之誓", Region: "稻妻", Element: "风", WeaponType: "单手剑", Version: "1.6"},
+		"胡桃":    {Name: "胡桃", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "护摩之杖", Region: "璃月", Element: "火", WeaponType: "长柄武器", Version: "1.3"},
+		"甘雨":    {Name: "甘雨", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "阿莫斯之弓", Region: "璃月", Element: "冰", WeaponType: "弓", Version: "1.2"},
+		"达达利亚":  {Name: "达达利亚", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "冬极白星", Region: "至冬", Element: "水", WeaponType: "弓", Version: "1.1"},
+		"钟离":    {Name: "钟离", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "贯虹之槊", Region: "璃月", Element: "岩", WeaponType: "长柄武器", Version: "1.1"},
+		"魈":     {Name: "魈", Prices: [7]float64{5, 10, 15, 20, 25, 30, 150}, SpecializedWeapon: "和璞鸢", Region: "璃月", Element: "风", WeaponType: "长柄武器", Version: "1.3"},
+		"可莉":    {Name: "可莉", Prices: [7]float64{5, 10, 15, 20, 25, 30, 100}, SpecializedWeapon: "四风原典", Region: "蒙德", Element: "火", WeaponType: "法器", Version: "1.0"},
+		"温迪":    {Name: "温迪", Prices: [7]float64{5, 10, 15, 20, 25, 30, 180}, SpecializedWeapon: "终末嗟叹之诗", Region: "蒙德", Element: "风", WeaponType: "弓", Version: "1.0"},
+		"菈乌玛":   {Name: "菈乌玛", Prices: [7]float64{5, 10, 80, 90, 100, 200, 400}, SpecializedWeapon: "纺夜天镜", Region: "月国", Element: "草", WeaponType: "法器", Version: "6.0"},
+		"菲林斯":   {Name: "菲林斯", Prices: [7]float64{5, 10, 80, 90, 100, 200, 500}, SpecializedWeapon: "血染荒城", Region: "月国", Element: "雷", WeaponType: "长柄武器", Version: "6.0"},
+		"奈芙尔":   {Name: "奈芙尔", Prices: [7]float64{5, 10, 80, 90, 100, 200, 650}, SpecializedWeapon: "真语秘匣", Region: "月国", Element: "草", WeaponType: "法器", Version: "6.1"},
+		"哥伦比娅":  {Name: "哥伦比娅", Prices: [7]float64{5, 10, 80, 90, 100, 200, 500}, SpecializedWeapon: "帷间夜曲", Region: "月国", Element: "水", WeaponType: "法器", Version: "6.3"},
+		"兹白":    {Name: "兹白", Prices: [7]float64{5, 10, 80, 90, 100, 200, 550}, SpecializedWeapon: "朏魄含光", Region: "月国", Element: "岩", WeaponType: "单手剑", Version: "6.3"},
+		"法尔伽":   {Name: "法尔伽", Prices: [7]float64{5, 10, 80, 90, 100, 200, 600}, SpecializedWeapon: "狼的武功歌", Region: "蒙德", Element: "风", WeaponType: "双手剑", Version: "6.4"},
+		"莉奈娅":   {Name: "莉奈娅", Prices: [7]float64{5, 10, 80, 90, 100, 200, 500}, SpecializedWeapon: "霜结的誓金枝", Region: "月国", Element: "岩", WeaponType: "弓", Version: "6.5"},
+	}
+
+	// 调价前的角色价格
+	previousCharPrices := map[string]string{"丝柯克": "6命 550", "瓦雷莎": "6命 800", "玛薇卡": "6命 550", "恰斯卡": "6命 650"}
+	for name, info := range r.Characters {
+		info.Provenance = Provenance{Source: ruleDocument, Section: "角色价格表 [cite: 22, 228]", Previous: previousCharPrices[name]}
+		r.Characters[name] = info
 	}
 
 	// 武器价格表
 	r.Weapons = map[string]WeaponInfo{
 		"黑蚀":      {Name: "黑蚀", Prices: [5]float64{5, 10, 15, 20, 200}}, // 新增武器
 		"支离轮光":    {Name: "支离轮光", Prices: [5]float64{5, 10, 15, 20, 150}},
-		"苍耀":      {Name: "苍耀", Prices: [5]float64{5, 10, 15, 20, 200}}, // 300->200
+		"苍耀":      {Name: "苍耀", Prices: [5]float64{5, 10, 15, 20, 200}},
 		"香韵奏者":    {Name: "香韵奏者", Prices: [5]float64{5, 10, 15, 20, 150}},
 		"溢彩心念":    {Name: "溢彩心念", Prices: [5]float64{5, 10, 15, 20, 250}},
 		"祭星者之望":   {Name: "祭星者之望", Prices: [5]float64{5, 10, 15, 20, 150}},
-		"焚曜千阳":    {Name: "焚曜千阳", Prices: [5]float64{5, 10, 15, 20, 250}}, // 300->250
-		"星鹫赤羽":    {Name: "星鹫赤羽", Prices: [5]float64{5, 10, 15, 20, 250}}, // 300->250
+		"焚曜千阳":    {Name: "焚曜千阳", Prices: [5]float64{5, 10, 15, 20, 250}},
+		"星鹫赤羽":    {Name: "星鹫赤羽", Prices: [5]float64{5, 10, 15, 20, 250}},
 		"岩峰巡歌":    {Name: "岩峰巡歌", Prices: [5]float64{5, 10, 15, 20, 100}},
 		"山王长牙":    {Name: "山王长牙", Prices: [5]float64{5, 10, 15, 20, 150}},
 		"冲浪时光":    {Name: "冲浪时光", Prices: [5]float64{5, 10, 15, 20, 150}},
@@ -185,115 +402,128 @@ func loadValuationRules() ValuationRules {
 		"霜结的誓金枝":  {Name: "霜结的誓金枝", Prices: [5]float64{5, 10, 15, 20, 200}},
 	}
 
-	// 完整溢价组合 [cite: 25-175, 177-184]
+	// 调价前的武器价格
+	previousWeaponPrices := map[string]string{"苍耀": "精5 300", "焚曜千阳": "精5 300", "星鹫赤羽": "精5 300"}
+	for name, info := range r.Weapons {
+		info.Provenance = Provenance{Source: ruleDocument, Section: "武器价格表", Previous: previousWeaponPrices[name]}
+		r.Weapons[name] = info
+	}
+
 	// 完整溢价组合 [cite: 25-175, 177-184]
 	r.Combos = getCombos()
+	for i := range r.Combos {
+		r.Combos[i].Provenance = Provenance{Source: ruleDocument, Section: r.Combos[i].Group + " [cite: 25-175, 177-184]"}
+	}
 
-	sort.Slice(r.Combos, func(i, j int) bool {
-		return r.Combos[i].Value > r.Combos[j].Value
-	})
+	SortCombos(r.Combos)
+
+	r.SignatureWeaponComboMultiplier = 2
 
 	// 角色数量乘数规则（未变动）
-	r.CharCountMultiplierTiers = []struct {
-		MinCount int
-		MaxCount int
-		Factor   float64
-	}{
-		{0, 10, 0.6}, {11, 20, 0.8}, {21, 39, 1.0},
-		{40, 45, 1.2}, {46, 50, 1.4}, {51, 999, 1.6},
+	r.CharCountMultiplierTiers = []CharCountTier{
+		{MinCount: 0, MaxCount: 10, Factor: 0.6}, {MinCount: 11, MaxCount: 20, Factor: 0.8}, {MinCount: 21, MaxCount: 39, Factor: 1.0},
+		{MinCount: 40, MaxCount: 45, Factor: 1.2}, {MinCount: 46, MaxCount: 50, Factor: 1.4}, {MinCount: 51, MaxCount: 999, Factor: 1.6},
 	}
+	for i := range r.CharCountMultiplierTiers {
+		r.CharCountMultiplierTiers[i].Provenance = Provenance{Source: ruleDocument, Section: "角色数量乘数规则"}
+	}
+	r.CharCountMultiplierMode = TierStep
+	r.CharCountPolicy = CountAllFiveStars
 
 	// 资源价值规则 [cite: 396-404]
-	r.ResourceValueTiers = []struct {
-		MinFates int
-		Price    float64
-	}{
-		{1000, 1.7},
-		{900, 1.6},
-		{800, 1.5},
-		{700, 1.4},
-		{600, 1.3},
-		{500, 1.2},
-		{300, 1.0},
-		{200, 0.5},
+	r.ResourceValueTiers = []ResourceTier{
+		{MinFates: 1000, Price: 1.7},
+		{MinFates: 900, Price: 1.6},
+		{MinFates: 800, Price: 1.5},
+		{MinFates: 700, Price: 1.4},
+		{MinFates: 600, Price: 1.3},
+		{MinFates: 500, Price: 1.2},
+		{MinFates: 300, Price: 1.0},
+		{MinFates: 200, Price: 0.5},
+	}
+	for i := range r.ResourceValueTiers {
+		r.ResourceValueTiers[i].Provenance = Provenance{Source: ruleDocument, Section: "资源价值规则 [cite: 396-404]"}
+	}
+	r.ResourceValueTierMode = TierStep
+	r.ResourceValueMode = ResourceValueByTier
+	r.ExpectedResourceValue = ExpectedResourceRules{
+		CharacterBanner: DefaultCharacterBanner,
+		WeaponBanner:    DefaultWeaponBanner,
+		CharacterPrice:  100,
+		WeaponPrice:     80,
+	}
+	r.MonteCarlo = MonteCarloRules{
+		Seed:            1,
+		Trials:          5000,
+		CharacterBanner: DefaultCharacterBanner,
+		WeaponBanner:    DefaultWeaponBanner,
 	}
 
 	// 特殊规则相关角色列表
-	// 第一梯队热门6命角色 (+300，但命中月国满命溢价时不再+300)
+	// 第一梯队热门6命角色 (+300，但命中月国等豁免地区满命溢价时不再+300)
 	r.HotC6CharsT1 = []string{"杜林", "奈芙尔", "菈乌玛", "菲林斯", "哥伦比娅", "兹白", "法尔伽"}
 	// 第二梯队热门6命角色 (+200)
 	r.HotC6CharsT2 = []string{"基尼奇", "瓦雷莎", "克洛琳德", "玛拉妮"}
+	r.HotC6T1ExemptRegions = []string{"月国"}
 	r.SpecialC2C5Chars = []string{"茜特菈莉", "希诺宁", "爱可菲", "哥伦比娅", "菈乌玛", "伊涅芙", "莉奈娅"}
 
+	r.SpecialRules = []SpecialRuleConfig{
+		// 满命组合内含2/3种特定2-5命角色: +200/+400
+		{Type: "combo_count", Name: "特定2-5命角色", Characters: r.SpecialC2C5Chars, MinConst: 2, MaxConst: 5,
+			CountBonuses: map[int]float64{2: 200, 3: 400}},
+		{Type: "hot_character", Name: "第一梯队热门6命角色", Characters: r.HotC6CharsT1, MinConst: 6, MaxConst: 6,
+			Bonus: 300, ExemptRegions: r.HotC6T1ExemptRegions, RequireMaxConstCombo: true},
+		{Type: "hot_character", Name: "第二梯队热门6命角色", Characters: r.HotC6CharsT2, MinConst: 6, MaxConst: 6,
+			Bonus: 200, RequireMaxConstCombo: true},
+	}
+
+	// 角色分类，仅用于角色数量计数，限定五星名单含不在价格表内的角色
+	r.LimitedFiveStarChars = []string{
+		"温迪", "可莉", "达达利亚", "钟离", "阿贝多", "甘雨", "魈", "胡桃", "优菈", "枫原万叶",
+		"神里绫华", "宵宫", "雷电将军", "珊瑚宫心海", "荒泷一斗", "申鹤", "八重神子", "神里绫人", "夜兰", "赛诺",
+		"妮露", "纳西妲", "流浪者", "艾尔海森", "白术", "林尼", "那维莱特", "莱欧斯利", "芙宁娜", "娜维娅",
+		"闲云", "千织", "阿蕾奇诺", "希格雯", "克洛琳德", "艾梅莉埃", "基尼奇", "玛拉妮", "恰斯卡", "希诺宁",
+		"玛薇卡", "茜特菈莉", "瓦雷莎", "爱可菲", "丝柯克", "伊涅芙", "杜林", "菈乌玛", "菲林斯", "奈芙尔",
+		"哥伦比娅", "兹白", "法尔伽", "莉奈娅",
+	}
+	r.StandardFiveStarChars = []string{"琴", "迪卢克", "莫娜", "七七", "刻晴", "提纳里", "迪希雅", "梦见月瑞希"}
+	r.FourStarChars = []string{
+		"香菱", "行秋", "班尼特", "芭芭拉", "北斗", "凝光", "菲谢尔", "砂糖", "重云", "诺艾尔",
+		"雷泽", "安柏", "凯亚", "丽莎", "迪奥娜", "辛焱", "罗莎莉亚", "烟绯", "早柚", "九条裟罗",
+		"托马", "五郎", "云堇", "久岐忍", "鹿野院平藏", "柯莱", "多莉", "坎蒂丝", "莱依拉", "珐露珊",
+		"瑶瑶", "米卡", "卡维", "绮良良", "琳妮特", "菲米尼", "夏洛蒂", "夏沃蕾", "嘉明", "赛索斯",
+		"卡齐娜", "欧洛伦", "伊安珊", "蓝砚", "伊法",
+	}
+
+	r.Pipeline = DefaultPipeline
+
 	return r
 }
 
-// CalculateValuation 是估值的主入口函数
+// CalculateValuation 是估值的主入口函数，按规则集中的估值流程依次执行各阶段
+// 与 Evaluate 一样以当前时间为估值时间，规则含促销时结果随时间变化
 func (n *NewRule) CalculateValuation(account eval.Assets) eval.ValuationResult {
-	var sb strings.Builder
-
-	// --- 步骤一: 计算最优溢价组合 ---
-	fmt.Fprintf(&sb, "<div class='step'><h3>步骤一: 计算最优溢价组合附加价值</h3><pre>")
-	satisfiedCombos := findSatisfiedCombos(account)
-	bestComboBonus, bestComboSelection := findBestComboSelection(satisfiedCombos, make(map[string]bool))
-	if len(bestComboSelection) > 0 {
-		fmt.Fprintf(&sb, "命中以下最优组合方案，获得附加价值: %.2f\n", bestComboBonus)
-		for _, combo := range bestComboSelection {
-			fmt.Fprintf(&sb, "  - %s (附加 %.2f)\n", combo.Name, combo.Value)
-		}
-	} else {
-		sb.WriteString("未命中任何溢价组合。\n")
-	}
-	sb.WriteString("</pre></div>")
-
-	// --- 步骤二: 区分并计算基础价值 ---
-	fmt.Fprintf(&sb, "<div class='step'><h3>步骤二: 计算并区分角色与武器的基础价值</h3><pre>")
-	applicableValue, exemptValue, baseValueBreakdown := calculateBaseValue(account, bestComboSelection)
-	sb.WriteString(baseValueBreakdown)
-	fmt.Fprintf(&sb, "\n&gt;&gt; 适用乘数的基础价值: %.2f\n", applicableValue)
-	fmt.Fprintf(&sb, "&gt;&gt; 豁免乘数的基础价值: %.2f\n", exemptValue)
-	sb.WriteString("</pre></div>")
-
-	// --- 步骤三: 应用角色数量乘数 ---
-	fmt.Fprintf(&sb, "<div class='step'><h3>步骤三: 对适用部分应用角色数量乘数</h3><pre>")
-	adjustedApplicableValue, multiplierBreakdown := applyCharacterCountMultiplier(applicableValue, len(account.Characters))
-	sb.WriteString(multiplierBreakdown)
-	sb.WriteString("</pre></div>")
-
-	// --- 步骤四: 计算总基础价值 ---
-	totalAdjustedBaseValue := adjustedApplicableValue + exemptValue
-	fmt.Fprintf(&sb, "<div class='subtotal'><p>总基础价值 = 调整后适用价值 (%.2f) + 豁免价值 (%.2f) = <strong>%.2f</strong></p></div>", adjustedApplicableValue, exemptValue, totalAdjustedBaseValue)
-
-	// --- 步骤五: 计算资源价值 ---
-	fmt.Fprintf(&sb, "<div class='step'><h3>步骤五: 计算资源价值</h3><pre>")
-	resourceValue, resourceBreakdown := calculateResourceValue(account)
-	sb.WriteString(resourceBreakdown)
-	sb.WriteString("</pre></div>")
-
-	// --- 步骤六: 应用特殊规则增益 ---
-	fmt.Fprintf(&sb, "<div class='step'><h3>步骤六: 应用特殊规则附加增益</h3><pre>")
-	specialBonus, specialBonusBreakdown := applySpecialRules(account, bestComboSelection)
-	sb.WriteString(specialBonusBreakdown)
-	sb.WriteString("</pre></div>")
-
-	// --- 步骤七: 最终合计 ---
-	totalValue := totalAdjustedBaseValue + bestComboBonus + resourceValue + specialBonus
-	fmt.Fprintf(&sb, "<div class='final-total'><h3>最终合计</h3>")
-	fmt.Fprintf(&sb, "<p>总基础价值    : %.2f</p>", totalAdjustedBaseValue)
-	fmt.Fprintf(&sb, "<p>组合附加价值  : %.2f</p>", bestComboBonus)
-	fmt.Fprintf(&sb, "<p>资源价值      : %.2f</p>", resourceValue)
-	fmt.Fprintf(&sb, "<p>特殊规则增益  : %.2f</p>", specialBonus)
-	fmt.Fprintf(&sb, "<hr><p><strong>账号总估值: %.2f</strong></p>", totalValue)
-	fmt.Fprintf(&sb, "</div>")
-
+	report := n.Evaluate(account)
 	return eval.ValuationResult{
-		FinalTotal: totalValue,
-		Breakdown:  sb.String(),
+		FinalTotal:  report.FinalTotal,
+		Breakdown:   report.Breakdown,
+		RuleVersion: report.RuleVersion,
 	}
 }
 
-// calculateBaseValue 区分计算适用和豁免乘数的基础价值
-func calculateBaseValue(account eval.Assets, bestRules []ComboRule) (applicableValue float64, exemptValue float64, breakdown string) {
+// characterBookedValue 返回角色计入基础价值的价格及说明，2-6命未持有专武时按8折计
+func characterBookedValue(account eval.Assets, info CharacterInfo, constellation int) (float64, string) {
+	value := info.Prices[constellation]
+	if constellation >= 2 && constellation <= 6 {
+		if _, hasWeapon := account.Weapons[info.SpecializedWeapon]; !hasWeapon {
+			return value * 0.8, " (无专武, 8折)"
+		}
+	}
+	return value, ""
+}
+
+// calculateBaseValue 区分计算适用和豁免乘数的基础价值，明细行注明所依据的规则条目
+func calculateBaseValue(account eval.Assets, bestRules []ComboRule, set *ruleSet, sources RuleSources) (applicableValue float64, exemptValue float64, breakdown string) {
 	var sb strings.Builder
 
 	premiumC6Chars := make(map[string]bool)
@@ -305,12 +535,12 @@ func calculateBaseValue(account eval.Assets, bestRules []ComboRule) (applicableV
 		}
 	}
 	if hasMaxConstCombo(bestRules) {
-		for _, hotChar := range rules.HotC6CharsT1 {
+		for _, hotChar := range set.HotC6CharsT1 {
 			if c, ok := account.Characters[hotChar]; ok && c == 6 {
 				premiumC6Chars[hotChar] = true
 			}
 		}
-		for _, hotChar := range rules.HotC6CharsT2 {
+		for _, hotChar := range set.HotC6CharsT2 {
 			if c, ok := account.Characters[hotChar]; ok && c == 6 {
 				premiumC6Chars[hotChar] = true
 			}
@@ -320,7 +550,7 @@ func calculateBaseValue(account eval.Assets, bestRules []ComboRule) (applicableV
 	c6CharWeapons := make(map[string]bool)
 	for name, constellation := range account.Characters {
 		if constellation == 6 {
-			if charInfo, ok := rules.Characters[name]; ok && charInfo.SpecializedWeapon != "" {
+			if charInfo, ok := set.Characters[name]; ok && charInfo.SpecializedWeapon != "" {
 				c6CharWeapons[charInfo.SpecializedWeapon] = true
 			}
 		}
@@ -334,18 +564,12 @@ func calculateBaseValue(account eval.Assets, bestRules []ComboRule) (applicableV
 
 	for _, name := range charNames {
 		constellation := account.Characters[name]
-		charInfo, ok := rules.Characters[name]
+		charInfo, ok := set.Characters[name]
 		if !ok {
 			continue
 		}
-		value := charInfo.Prices[constellation]
-		reason := ""
-		if constellation >= 2 && constellation <= 6 {
-			if _, hasWeapon := account.Weapons[charInfo.SpecializedWeapon]; !hasWeapon {
-				value *= 0.8
-				reason = " (无专武, 8折)"
-			}
-		}
+		value, reason := characterBookedValue(account, charInfo, constellation)
+		reason += sources.cite(charInfo.ref())
 
 		if constellation == 6 {
 			exemptValue += value
@@ -356,6 +580,18 @@ func calculateBaseValue(account eval.Assets, bestRules []ComboRule) (applicableV
 		}
 	}
 
+	// 组合自定义的武器乘数，同一武器取最大值
+	comboWeaponMultipliers := make(map[string]weaponMultiplier)
+	comboWeaponRefs := make(map[string]ruleRef)
+	for _, combo := range bestRules {
+		for w, factor := range combo.WeaponMultipliers {
+			if m, ok := comboWeaponMultipliers[w]; !ok || factor > m.Factor {
+				comboWeaponMultipliers[w] = weaponMultiplier{Rule: combo.Name, Factor: factor}
+				comboWeaponRefs[w] = combo.ref()
+			}
+		}
+	}
+	weaponMultipliers := exprWeaponMultipliers(account, set)
 	weaponNames := make([]string, 0, len(account.Weapons))
 	for name := range account.Weapons {
 		weaponNames = append(weaponNames, name)
@@ -364,7 +600,7 @@ func calculateBaseValue(account eval.Assets, bestRules []ComboRule) (applicableV
 
 	for _, name := range weaponNames {
 		refine := account.Weapons[name]
-		weaponInfo, ok := rules.Weapons[name]
+		weaponInfo, ok := set.Weapons[name]
 		if !ok {
 			continue
 		}
@@ -373,9 +609,10 @@ func calculateBaseValue(account eval.Assets, bestRules []ComboRule) (applicableV
 		}
 		value := weaponInfo.Prices[refine-1]
 		reason := ""
+		refs := []ruleRef{weaponInfo.ref()}
 
 		ownerName := ""
-		for charName, charInfo := range rules.Characters {
+		for charName, charInfo := range set.Characters {
 			if charInfo.SpecializedWeapon == name {
 				ownerName = charName
 				break
@@ -391,11 +628,22 @@ func calculateBaseValue(account eval.Assets, bestRules []ComboRule) (applicableV
 			}
 		}
 
-		if refine == 5 && ownerName != "" && premiumC6Chars[ownerName] {
-			value *= 2
-			reason += " (命中组合内6命角色专武, 价格x2)"
+		if m, ok := comboWeaponMultipliers[name]; ok {
+			value *= m.Factor
+			reason += fmt.Sprintf(" (命中组合[%s], 价格x%g)", m.Rule, m.Factor)
+			refs = append(refs, comboWeaponRefs[name])
+		} else if refine == 5 && ownerName != "" && premiumC6Chars[ownerName] {
+			value *= set.SignatureWeaponComboMultiplier
+			reason += fmt.Sprintf(" (命中组合内6命角色专武, 价格x%g)", set.SignatureWeaponComboMultiplier)
+		}
+
+		for _, m := range weaponMultipliers[name] {
+			value *= m.Factor
+			reason += fmt.Sprintf(" (规则[%s], 价格x%g)", m.Rule, m.Factor)
 		}
 
+		reason += sources.cite(refs...)
+
 		if c6CharWeapons[name] {
 			exemptValue += value
 			sb.WriteString(fmt.Sprintf("  - [豁免] 武器 [%s 精%d]: %.2f%s\n", name, refine, value, reason))
@@ -412,79 +660,321 @@ func calculateBaseValue(account eval.Assets, bestRules []ComboRule) (applicableV
 }
 
 // findSatisfiedCombos 找出账号满足的所有组合
-func findSatisfiedCombos(account eval.Assets) []ComboRule {
+// 含替代要求组的组合按账号满足的每种选法各展开为一条，选用的要求并入 RequiredChars
+func findSatisfiedCombos(account eval.Assets, set *ruleSet) []ComboRule {
 	var satisfied []ComboRule
-	for _, combo := range rules.Combos {
+	for _, combo := range set.Combos {
 		isSatisfied := true
 		for _, req := range combo.RequiredChars {
-			constellation, ok := account.Characters[req.Name]
-			if !ok || constellation < req.MinConst || constellation > req.MaxConst {
+			if !req.Satisfied(account) {
 				isSatisfied = false
 				break
 			}
 		}
+		for _, req := range combo.RequiredWeapons {
+			if !isSatisfied {
+				break
+			}
+			isSatisfied = req.Satisfied(account)
+		}
+		if isSatisfied && combo.Condition != "" {
+			isSatisfied = set.conditions[combo.Condition].Matches(account)
+		}
 		if isSatisfied {
-			satisfied = append(satisfied, combo)
+			satisfied = append(satisfied, expandAlternatives(combo, account, set.Characters)...)
 		}
 	}
 	return satisfied
 }
 
-// findBestComboSelection 使用回溯算法寻找最优组合的附加价值
+// expandAlternatives 列出组合在账号上满足替代要求组与属性要求的所有选法
+// 同一角色在一种选法中只会被使用一次
+func expandAlternatives(combo ComboRule, account eval.Assets, characters map[string]CharacterInfo) []ComboRule {
+	type pickGroup struct {
+		met      []RequiredChar
+		minMatch int
+	}
+	var groups []pickGroup
+	for _, group := range combo.RequiredGroups {
+		var met []RequiredChar
+		for _, opt := range group.Options {
+			if opt.Satisfied(account) {
+				met = append(met, opt)
+			}
+		}
+		groups = append(groups, pickGroup{met: met, minMatch: group.MinMatch})
+	}
+	for _, attr := range combo.RequiredAttributes {
+		groups = append(groups, pickGroup{met: attr.candidates(account, characters), minMatch: attr.Count})
+	}
+
+	choices := [][]RequiredChar{nil}
+	for _, group := range groups {
+		picks := chooseRequirements(group.met, group.minMatch)
+		var next [][]RequiredChar
+		for _, prefix := range choices {
+			for _, pick := range picks {
+				merged := append(append([]RequiredChar{}, prefix...), pick...)
+				if !hasDuplicateChars(combo.RequiredChars, merged) {
+					next = append(next, merged)
+				}
+			}
+		}
+		choices = next
+	}
+
+	resolved := make([]ComboRule, 0, len(choices))
+	for _, choice := range choices {
+		c := combo
+		c.RequiredChars = append(append([]RequiredChar{}, combo.RequiredChars...), choice...)
+		if len(groups) > 0 {
+			c.MatchedAlternatives = choice
+		}
+		resolved = append(resolved, c)
+	}
+	return resolved
+}
+
+// hasDuplicateChars 判断两组要求合并后是否重复使用同一角色
+func hasDuplicateChars(fixed, chosen []RequiredChar) bool {
+	seen := make(map[string]bool, len(fixed)+len(chosen))
+	for _, req := range fixed {
+		seen[req.Name] = true
+	}
+	for _, req := range chosen {
+		if seen[req.Name] {
+			return true
+		}
+		seen[req.Name] = true
+	}
+	return false
+}
+
+// chooseRequirements 返回从 options 中选出 n 个的所有组合
+func chooseRequirements(options []RequiredChar, n int) [][]RequiredChar {
+	if n <= 0 {
+		return [][]RequiredChar{nil}
+	}
+	if len(options) < n {
+		return nil
+	}
+	var result [][]RequiredChar
+	for _, rest := range chooseRequirements(options[1:], n-1) {
+		result = append(result, append([]RequiredChar{options[0]}, rest...))
+	}
+	return append(result, chooseRequirements(options[1:], n)...)
+}
+
+// weaponKey 返回武器在已使用集合中的键，避免与角色名冲突
+func weaponKey(name string) string {
+	return "武器:" + name
+}
+
+// comboKey 返回组合在已使用集合中的键
+func comboKey(name string) string {
+	return "组合:" + name
+}
+
+// shareableKey 表示角色已被允许共享的组合使用，sharedKey 表示角色已被共享过一次
+func shareableKey(name string) string {
+	return "可共享:" + name
+}
+
+func sharedKey(name string) string {
+	return "已共享:" + name
+}
+
+// comboValueEpsilon 是比较组合方案附加价值时容许的误差
+const comboValueEpsilon = 1e-9
+
+// findBestComboSelection 使用回溯算法找出附加价值最高的组合方案，多个方案价值相同时按 comboSelectionLess 取舍
+// 角色默认只能被一个组合使用，双方都声明可共享的角色可再被一个组合使用，两个组合中的一方按折扣计价
 func findBestComboSelection(availableCombos []ComboRule, usedChars map[string]bool) (float64, []ComboRule) {
+	return searchComboSelections(availableCombos, usedChars, nil)
+}
+
+// comboSelectionLess 在附加价值相同的组合方案间稳定取舍: 占用角色少的方案优先，其次比较 comboSelectionKey
+func comboSelectionLess(a, b []ComboRule) bool {
+	if na, nb := len(comboSelectionChars(a)), len(comboSelectionChars(b)); na != nb {
+		return na < nb
+	}
+	return comboSelectionKey(a) < comboSelectionKey(b)
+}
+
+// comboSelectionChars 返回组合方案占用的角色
+func comboSelectionChars(selection []ComboRule) map[string]bool {
+	chars := make(map[string]bool)
+	for _, combo := range selection {
+		for _, req := range combo.RequiredChars {
+			chars[req.Name] = true
+		}
+		for _, req := range combo.MatchedAlternatives {
+			chars[req.Name] = true
+		}
+	}
+	return chars
+}
+
+// comboSelectionKey 以与顺序无关的方式描述组合方案
+// 每个组合以其要求的角色名排序后描述，角色相同时再以组合名区分
+func comboSelectionKey(selection []ComboRule) string {
+	keys := make([]string, 0, len(selection))
+	for _, combo := range selection {
+		names := make([]string, 0, len(combo.RequiredChars)+len(combo.MatchedAlternatives))
+		for _, req := range combo.RequiredChars {
+			names = append(names, req.Name)
+		}
+		for _, req := range combo.MatchedAlternatives {
+			names = append(names, req.Name)
+		}
+		sort.Strings(names)
+		keys = append(keys, strings.Join(names, "+")+"/"+combo.Name)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, "|")
+}
+
+// searchComboSelections 依次决定是否选用 availableCombos 中的组合，selected 为已选用的组合
+func searchComboSelections(availableCombos []ComboRule, usedChars map[string]bool, selected []ComboRule) (float64, []ComboRule) {
 	if len(availableCombos) == 0 {
-		return 0, nil
+		value := 0.0
+		for _, combo := range selected {
+			value += combo.EffectiveValue()
+		}
+		// 方案按组合被考虑的逆序列出
+		selection := slices.Clone(selected)
+		slices.Reverse(selection)
+		return value, selection
 	}
 	// Case 1: Skip the current combo
-	valueA, selectionA := findBestComboSelection(availableCombos[1:], usedChars)
+	bestValue, bestSelection := searchComboSelections(availableCombos[1:], usedChars, selected)
+	consider := func(value float64, selection []ComboRule) {
+		if value > bestValue+comboValueEpsilon ||
+			value >= bestValue-comboValueEpsilon && comboSelectionLess(selection, bestSelection) {
+			bestValue, bestSelection = value, selection
+		}
+	}
 
 	// Case 2: Try to select the current combo
 	currentCombo := availableCombos[0]
+	currentCombo.SharedChars = nil
 	canSelect := true
+	var shared []string
 	for _, req := range currentCombo.RequiredChars {
-		if usedChars[req.Name] {
+		if !usedChars[req.Name] {
+			continue
+		}
+		if !currentCombo.canShare(req.Name) || !usedChars[shareableKey(req.Name)] || usedChars[sharedKey(req.Name)] {
+			canSelect = false
+			break
+		}
+		shared = append(shared, req.Name)
+	}
+	// 同一组合的不同选法只能选用其一
+	if usedChars[comboKey(currentCombo.Name)] {
+		canSelect = false
+	}
+	// 同一把武器只能被一个组合使用
+	for _, req := range currentCombo.RequiredWeapons {
+		if usedChars[weaponKey(req.Name)] {
 			canSelect = false
 			break
 		}
 	}
+	if !canSelect {
+		return bestValue, bestSelection
+	}
 
-	if canSelect {
-		newUsedChars := make(map[string]bool)
-		for k, v := range usedChars {
-			newUsedChars[k] = v
+	newUsedChars := make(map[string]bool)
+	for k, v := range usedChars {
+		newUsedChars[k] = v
+	}
+	for _, req := range currentCombo.RequiredChars {
+		if newUsedChars[req.Name] {
+			newUsedChars[sharedKey(req.Name)] = true
+			continue
 		}
-		for _, req := range currentCombo.RequiredChars {
-			newUsedChars[req.Name] = true
+		newUsedChars[req.Name] = true
+		if currentCombo.canShare(req.Name) {
+			newUsedChars[shareableKey(req.Name)] = true
 		}
-		valueB, selectionB := findBestComboSelection(availableCombos[1:], newUsedChars)
-		valueB += currentCombo.Value
-		selectionB = append(selectionB, currentCombo)
+	}
+	for _, req := range currentCombo.RequiredWeapons {
+		newUsedChars[weaponKey(req.Name)] = true
+	}
+	newUsedChars[comboKey(currentCombo.Name)] = true
 
-		if valueB > valueA {
-			return valueB, selectionB
+	if len(shared) == 0 {
+		consider(searchComboSelections(availableCombos[1:], newUsedChars, append(slices.Clone(selected), currentCombo)))
+		return bestValue, bestSelection
+	}
+	// 共享的角色由当前组合按折扣计价，或由先选用该角色的组合按折扣计价，两种情况分别搜索
+	payer := currentCombo
+	payer.SharedChars = shared
+	consider(searchComboSelections(availableCombos[1:], newUsedChars, append(slices.Clone(selected), payer)))
+
+	partners := slices.Clone(selected)
+	for _, name := range shared {
+		for i := range partners {
+			if slices.ContainsFunc(partners[i].RequiredChars, func(req RequiredChar) bool { return req.Name == name }) {
+				partners[i].SharedChars = append(slices.Clone(partners[i].SharedChars), name)
+				break
+			}
 		}
 	}
+	consider(searchComboSelections(availableCombos[1:], newUsedChars, append(partners, currentCombo)))
+	return bestValue, bestSelection
+}
 
-	return valueA, selectionA
+// totalFates 计算账号的总抽数
+func totalFates(account eval.Assets) int {
+	return account.JiuChanZhiYuan + (account.YuanShi / 160)
 }
 
 // calculateResourceValue 计算资源价值
-func calculateResourceValue(account eval.Assets) (float64, string) {
-	totalFates := account.JiuChanZhiYuan + (account.YuanShi / 160)
+// 蒙特卡洛模式按估值时间 at 对模拟结果估值
+func calculateResourceValue(account eval.Assets, set *ruleSet, at time.Time, sources RuleSources) (float64, string) {
+	switch set.ResourceValueMode {
+	case ResourceValueByExpectation:
+		return calculateExpectedResourceValue(account, set)
+	case ResourceValueByMonteCarlo:
+		return calculateMonteCarloResourceValue(account, set, at)
+	}
+	return calculateTierResourceValue(account, set, sources)
+}
+
+// calculateTierResourceValue 按总抽数分档计算资源价值
+func calculateTierResourceValue(account eval.Assets, set *ruleSet, sources RuleSources) (float64, string) {
+	totalFates := totalFates(account)
 	var sb strings.Builder
 	fmt.Fprintf(&sb, "账号总资源: %d 原石 + %d 纠缠之源 = %d 总抽数\n", account.YuanShi, account.JiuChanZhiYuan, totalFates)
 
+	if set.ResourceValueTierMode == TierInterpolate {
+		// 以 (0抽, 0) 为起点插值，消除最低档位处的跳变
+		points := []tierPoint{{X: 0, Y: 0}}
+		for _, tier := range set.ResourceValueTiers {
+			points = append(points, tierPoint{X: float64(tier.MinFates), Y: tier.Price})
+		}
+		price, lo, hi := interpolateTiers(points, float64(totalFates))
+		value := float64(totalFates) * price
+		fmt.Fprintf(&sb, "  - 插值单价 %.4f (%s)\n", price, describeNeighbours(lo, hi, "抽"))
+		fmt.Fprintf(&sb, "  - %d 抽: %d * %.4f = %.2f%s\n", totalFates, totalFates, price, value,
+			sources.cite(neighbourResourceTiers(set, lo, hi)...))
+		fmt.Fprintf(&sb, "资源总价值: %.2f\n", value)
+		return value, sb.String()
+	}
+
 	if totalFates < 200 {
 		sb.WriteString("总抽数低于200，不计价。\n")
 		return 0, sb.String()
 	}
 
 	value := 0.0
-	for _, tier := range rules.ResourceValueTiers {
+	for _, tier := range set.ResourceValueTiers {
 		if totalFates >= tier.MinFates {
 			value = float64(totalFates) * tier.Price
-			fmt.Fprintf(&sb, "  - %d 抽: %d * %.2f = %.2f\n",
-				totalFates, totalFates, tier.Price, value)
+			fmt.Fprintf(&sb, "  - %d 抽: %d * %.2f = %.2f%s\n",
+				totalFates, totalFates, tier.Price, value, sources.cite(tier.ref()))
 			break
 		}
 	}
@@ -492,15 +982,66 @@ func calculateResourceValue(account eval.Assets) (float64, string) {
 	return value, sb.String()
 }
 
+// calculateExpectedResourceValue 结合卡池状态，按期望获得的五星计算资源价值
+func calculateExpectedResourceValue(account eval.Assets, set *ruleSet) (float64, string) {
+	cfg := set.ExpectedResourceValue
+	totalFates := totalFates(account)
+	weaponPulls := int(float64(totalFates) * cfg.WeaponShare)
+	characterPulls := totalFates - weaponPulls
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "账号总资源: %d 原石 + %d 纠缠之源 = %d 总抽数\n", account.YuanShi, account.JiuChanZhiYuan, totalFates)
+
+	charStart := bannerState{Pity: account.CharacterPity, Guaranteed: account.CharacterGuaranteed}
+	expectedChars := cfg.CharacterBanner.ExpectedTargets(charStart, characterPulls)
+	charValue := expectedChars * cfg.CharacterPrice
+	fmt.Fprintf(&sb, "  - 角色池: 已垫 %d 抽%s, 投入 %d 抽, 期望UP角色 %.2f 个 * %.2f = %.2f\n",
+		account.CharacterPity, guaranteeNote(account.CharacterGuaranteed), characterPulls, expectedChars, cfg.CharacterPrice, charValue)
+
+	weaponValue := 0.0
+	if weaponPulls > 0 {
+		weaponStart := bannerState{Pity: account.WeaponPity, Guaranteed: account.WeaponGuaranteed, Counter: account.FatePoints}
+		expectedWeapons := cfg.WeaponBanner.ExpectedTargets(weaponStart, weaponPulls)
+		weaponValue = expectedWeapons * cfg.WeaponPrice
+		fmt.Fprintf(&sb, "  - 武器池: 已垫 %d 抽%s, 命定值 %d, 投入 %d 抽, 期望定轨武器 %.2f 把 * %.2f = %.2f\n",
+			account.WeaponPity, guaranteeNote(account.WeaponGuaranteed), account.FatePoints, weaponPulls, expectedWeapons, cfg.WeaponPrice, weaponValue)
+	}
+
+	tierValue, _ := calculateTierResourceValue(account, set, nil)
+	fmt.Fprintf(&sb, "  (参考) 按总抽数分档计价: %.2f\n", tierValue)
+
+	value := charValue + weaponValue
+	fmt.Fprintf(&sb, "资源总价值: %.2f\n", value)
+	return value, sb.String()
+}
+
+func guaranteeNote(guaranteed bool) string {
+	if guaranteed {
+		return " (大保底)"
+	}
+	return ""
+}
+
 // applyCharacterCountMultiplier 应用角色数量乘数
-func applyCharacterCountMultiplier(applicableValue float64, charCount int) (float64, string) {
-	for _, tier := range rules.CharCountMultiplierTiers {
+func applyCharacterCountMultiplier(applicableValue float64, charCount int, set *ruleSet, sources RuleSources) (float64, string) {
+	if set.CharCountMultiplierMode == TierInterpolate && len(set.CharCountMultiplierTiers) > 0 {
+		points := make([]tierPoint, 0, len(set.CharCountMultiplierTiers))
+		for _, tier := range set.CharCountMultiplierTiers {
+			points = append(points, tierPoint{X: float64(tier.MinCount), Y: tier.Factor})
+		}
+		factor, lo, hi := interpolateTiers(points, float64(charCount))
+		finalValue := applicableValue * factor
+		return finalValue, fmt.Sprintf("角色计数 %d，插值乘数 %.4f (%s):\n  %.2f * %.4f = %.2f%s\n", charCount, factor, describeNeighbours(lo, hi, "个"),
+			applicableValue, factor, finalValue, sources.cite(neighbourCharCountTiers(set, lo, hi)...))
+	}
+	for _, tier := range set.CharCountMultiplierTiers {
 		if charCount >= tier.MinCount && charCount <= tier.MaxCount {
 			finalValue := applicableValue * tier.Factor
-			return finalValue, fmt.Sprintf("账号有 %d 个五星角色，对适用部分应用 %.0f%% 的乘数:\n  %.2f * %.2f = %.2f\n", charCount, tier.Factor*100, applicableValue, tier.Factor, finalValue)
+			return finalValue, fmt.Sprintf("角色计数 %d，对适用部分应用 %.0f%% 的乘数:\n  %.2f * %.2f = %.2f%s\n", charCount, tier.Factor*100,
+				applicableValue, tier.Factor, finalValue, sources.cite(tier.ref()))
 		}
 	}
-	return applicableValue, fmt.Sprintf("账号有 %d 个五星角色，未找到对应的乘数规则，价值不变。\n", charCount)
+	return applicableValue, fmt.Sprintf("角色计数 %d，未找到对应的乘数规则，价值不变。\n", charCount)
 }
 
 // hasMaxConstCombo 判断组合列表中是否包含要求6命的组合
@@ -515,91 +1056,6 @@ func hasMaxConstCombo(bestRules []ComboRule) bool {
 	return false
 }
 
-// applySpecialRules 应用特殊规则增益
-func applySpecialRules(account eval.Assets, bestRules []ComboRule) (float64, string) {
-	var totalBonus float64
-	var sb strings.Builder
-
-	for _, combo := range bestRules {
-		isMaxConstellationCombo := false
-		for _, req := range combo.RequiredChars {
-			if req.MinConst == 6 {
-				isMaxConstellationCombo = true
-				break
-			}
-		}
-
-		if isMaxConstellationCombo {
-			// Rule: C2-C5 bonus per combo
-			specialCharsFoundInThisCombo := 0
-			for _, specialChar := range rules.SpecialC2C5Chars {
-				for _, req := range combo.RequiredChars {
-					if req.Name == specialChar {
-						if c, inAccount := account.Characters[specialChar]; inAccount && c >= 2 && c <= 5 {
-							specialCharsFoundInThisCombo++
-						}
-						break
-					}
-				}
-			}
-			if specialCharsFoundInThisCombo >= 3 {
-				totalBonus += 400
-				fmt.Fprintf(&sb, "  - 组合 [%.30s...] 包含3种特定2-5命角色，附加价值 +400\n", combo.Name)
-			} else if specialCharsFoundInThisCombo == 2 {
-				totalBonus += 200
-				fmt.Fprintf(&sb, "  - 组合 [%.30s...] 包含2种特定2-5命角色，附加价值 +200\n", combo.Name)
-			}
-		}
-	}
-
-	if hasMaxConstCombo(bestRules) {
-		// 收集命中了月国满命溢价组合的角色
-		yueguoChars := make(map[string]bool)
-		for _, combo := range bestRules {
-			// 月国组合的特征：包含奈芙尔/菲林斯/哥伦比娅/兹白/伊涅芙/菈乌玛等月国角色的满命组合
-			isYueguoCombo := false
-			for _, req := range combo.RequiredChars {
-				if req.MinConst == 6 {
-					switch req.Name {
-					case "奈芙尔", "菲林斯", "哥伦比娅", "兹白", "伊涅芙", "菈乌玛", "莉奈娅":
-						isYueguoCombo = true
-					}
-				}
-			}
-			if isYueguoCombo {
-				for _, req := range combo.RequiredChars {
-					yueguoChars[req.Name] = true
-				}
-			}
-		}
-
-		// 第一梯队: +300，但已命中月国满命溢价组合的角色不再+300
-		for _, hotChar := range rules.HotC6CharsT1 {
-			if constellation, ok := account.Characters[hotChar]; ok && constellation == 6 {
-				if yueguoChars[hotChar] {
-					fmt.Fprintf(&sb, "  - 热门6命角色 [%s] 已命中月国满命溢价组合，不再额外+300\n", hotChar)
-				} else {
-					totalBonus += 300
-					fmt.Fprintf(&sb, "  - 命中热门6命角色 [%s]，附加价值 +300\n", hotChar)
-				}
-			}
-		}
-		// 第二梯队: +200
-		for _, hotChar := range rules.HotC6CharsT2 {
-			if constellation, ok := account.Characters[hotChar]; ok && constellation == 6 {
-				totalBonus += 200
-				fmt.Fprintf(&sb, "  - 命中热门6命角色 [%s]，附加价值 +200\n", hotChar)
-			}
-		}
-	}
-
-	if sb.Len() == 0 {
-		return 0, "未触发任何特殊角色规则。\n"
-	}
-
-	return totalBonus, sb.String()
-}
-
 func main() {
 	// 示例账号，用于演示C6豁免规则和HTML输出
 	exampleAccount := eval.Assets{
@@ -2,6 +2,7 @@ package newrule
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/sdojjy/genshin-value-rule/pkg/eval"
@@ -87,3 +88,299 @@ func TestCalculateValuation_Example(t *testing.T) {
 		t.Errorf("Expected > 2800, got %.2f", result.FinalTotal)
 	}
 }
+
+// newRuleWith 编译规则副本 r，需要改动规则的测试以此估值，不修改全局规则
+func newRuleWith(t *testing.T, r ValuationRules) *NewRule {
+	t.Helper()
+	set, err := compileRuleSet(r, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &NewRule{rules: set}
+}
+
+func TestCountCharacters_Policy(t *testing.T) {
+	account := eval.Assets{
+		Characters: map[string]int{
+			"玛薇卡":  6, // 限定，价格表内
+			"神里绫华": 0, // 限定，不在价格表内
+			"刻晴":   1, // 常驻
+			"香菱":   6, // 四星
+			"不存在":  0, // 未知
+		},
+	}
+
+	cases := []struct {
+		policy CharCountPolicy
+		want   int
+	}{
+		{CountPricedFiveStars, 1},
+		{CountLimitedFiveStars, 2},
+		{CountAllFiveStars, 3},
+		{CountAllCharacters, 4},
+		{CountLegacy, 5},
+		{CharCountPolicy{Name: "按命座", IncludeLimited: true, IncludeStandard: true, CountConstellations: true}, 10},
+		{CharCountPolicy{Name: "价格表内与常驻", IncludePriced: true, IncludeStandard: true}, 2},
+	}
+	for _, c := range cases {
+		r := rules
+		r.CharCountPolicy = c.policy
+		if got, breakdown := countCharacters(account, newRuleWith(t, r).rules); got != c.want {
+			t.Errorf("policy %s: got %d, want %d\n%s", c.policy.Name, got, c.want, breakdown)
+		}
+	}
+}
+
+type flatBonusRule struct{ cfg SpecialRuleConfig }
+
+func (r flatBonusRule) Name() string { return r.cfg.Name }
+
+func (r flatBonusRule) Apply(account eval.Assets, bestRules []ComboRule) (float64, []string) {
+	return r.cfg.Bonus, []string{"  - " + r.cfg.Name}
+}
+
+func TestApplySpecialRules_Registered(t *testing.T) {
+	RegisterSpecialRule("flat", func(cfg SpecialRuleConfig, _ ValuationRules) (SpecialRule, error) { return flatBonusRule{cfg}, nil })
+	r := rules
+	r.SpecialRules = []SpecialRuleConfig{{Type: "flat", Name: "活动加价", Bonus: 50}}
+	bonus, breakdown := applySpecialRules(eval.Assets{}, nil, newRuleWith(t, r).rules)
+	if bonus != 50 {
+		t.Errorf("Expected bonus 50, got %.2f\n%s", bonus, breakdown)
+	}
+	if !strings.Contains(breakdown, "活动加价") {
+		t.Errorf("Expected breakdown to mention the rule, got:\n%s", breakdown)
+	}
+
+	// 未注册的类型在加载规则时报错，而不是估值时跳过
+	r.SpecialRules = append(r.SpecialRules, SpecialRuleConfig{Type: "missing", Name: "未注册"})
+	if _, err := compileRuleSet(r, "test"); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("Expected unknown special rule type to fail compilation, got %v", err)
+	}
+}
+
+func TestValidateRules_Builtin(t *testing.T) {
+	for _, err := range ValidateRules(loadValuationRules()) {
+		t.Error(err)
+	}
+}
+
+func TestCharacterClass_PricedCharacters(t *testing.T) {
+	set := New().rules
+	for name := range rules.Characters {
+		if class := characterClass(name, set); class != "限定五星" && class != "常驻五星" {
+			t.Errorf("priced character %s is classified as %s", name, class)
+		}
+	}
+}
+
+func TestCharacterInfo_Attributes(t *testing.T) {
+	// 确实不具备的属性，角色 -> 属性名
+	exempt := map[string]string{"丝柯克": "Region"}
+	for name, info := range rules.Characters {
+		for attr, value := range map[string]string{"Region": info.Region, "Element": info.Element, "WeaponType": info.WeaponType, "Version": info.Version} {
+			if value == "" && exempt[name] != attr {
+				t.Errorf("%s has no %s", name, attr)
+			}
+		}
+	}
+}
+
+func TestCalculateValuation_CustomPipeline(t *testing.T) {
+	account := eval.Assets{
+		Characters: map[string]int{"玛薇卡": 6, "茜特菈莉": 6},
+		Weapons:    map[string]int{"焚曜千阳": 5},
+	}
+	full := New().CalculateValuation(account).FinalTotal
+
+	r := rules
+	r.Pipeline = []StageConfig{
+		{Type: "base_value"},
+		{Type: "fee", Rate: 0.1},
+		{Type: "cap", Amount: 500},
+		{Type: "total"},
+	}
+	result := newRuleWith(t, r).CalculateValuation(account)
+	if result.FinalTotal != 500 {
+		t.Errorf("Expected capped total 500 (uncapped %.2f), got %.2f\n%s", full, result.FinalTotal, result.Breakdown)
+	}
+	if strings.Contains(result.Breakdown, "溢价组合") {
+		t.Errorf("Expected combo stage to be dropped, got:\n%s", result.Breakdown)
+	}
+}
+
+func TestFindSatisfiedCombos_Alternatives(t *testing.T) {
+	r := rules
+	r.Combos = []ComboRule{{
+		Name:          "6丝柯克+任意两个6命水系",
+		Value:         300,
+		RequiredChars: []RequiredChar{{Name: "丝柯克", MinConst: 6, MaxConst: 6}},
+		RequiredGroups: []RequirementGroup{{MinMatch: 2, Options: []RequiredChar{
+			{Name: "那维莱特", MinConst: 6, MaxConst: 6},
+			{Name: "芙宁娜", MinConst: 6, MaxConst: 6},
+			{Name: "夜兰", MinConst: 6, MaxConst: 6},
+		}}},
+	}}
+
+	set := newRuleWith(t, r).rules
+
+	account := eval.Assets{Characters: map[string]int{"丝柯克": 6, "那维莱特": 6, "芙宁娜": 6, "夜兰": 2}}
+	satisfied := findSatisfiedCombos(account, set)
+	if len(satisfied) != 1 {
+		t.Fatalf("Expected 1 resolved combo, got %d", len(satisfied))
+	}
+	if got := satisfied[0].MatchedAlternatives; len(got) != 2 || got[0].Name != "那维莱特" || got[1].Name != "芙宁娜" {
+		t.Errorf("Unexpected alternatives: %v", got)
+	}
+	if len(satisfied[0].RequiredChars) != 3 {
+		t.Errorf("Expected chosen alternatives to be merged into RequiredChars, got %v", satisfied[0].RequiredChars)
+	}
+
+	account.Characters["夜兰"] = 6
+	if got := len(findSatisfiedCombos(account, set)); got != 3 {
+		t.Errorf("Expected 3 resolved combos when all options are met, got %d", got)
+	}
+}
+
+func TestCalculateValuation_WeaponCombo(t *testing.T) {
+	r := rules
+	r.Combos = []ComboRule{{
+		Name:              "6玛薇卡+精5焚曜千阳",
+		Value:             200,
+		RequiredChars:     []RequiredChar{{Name: "玛薇卡", MinConst: 6, MaxConst: 6}},
+		RequiredWeapons:   []RequiredWeapon{{Name: "焚曜千阳", MinRefine: 5, MaxRefine: 5}},
+		WeaponMultipliers: map[string]float64{"焚曜千阳": 3},
+	}}
+	n := newRuleWith(t, r)
+
+	withWeapon := eval.Assets{
+		Characters: map[string]int{"玛薇卡": 6},
+		Weapons:    map[string]int{"焚曜千阳": 5},
+	}
+	result := n.CalculateValuation(withWeapon)
+	// 角色 500 + 武器 250*3 + 组合 200
+	if result.FinalTotal != 1450 {
+		t.Errorf("Expected 1450, got %.2f\n%s", result.FinalTotal, result.Breakdown)
+	}
+
+	withWeapon.Weapons["焚曜千阳"] = 4
+	if satisfied := findSatisfiedCombos(withWeapon, n.rules); len(satisfied) != 0 {
+		t.Errorf("Expected weapon requirement to block the combo, got %v", satisfied)
+	}
+}
+
+func TestFindSatisfiedCombos_Attributes(t *testing.T) {
+	r := rules
+	r.Combos = []ComboRule{{
+		Name:               "6玛薇卡+任意两个6命纳塔角色",
+		Value:              300,
+		RequiredChars:      []RequiredChar{{Name: "玛薇卡", MinConst: 6, MaxConst: 6}},
+		RequiredAttributes: []AttributeRequirement{{Region: "纳塔", MinConst: 6, MaxConst: 6, Count: 2}},
+	}}
+	set := newRuleWith(t, r).rules
+
+	account := eval.Assets{Characters: map[string]int{"玛薇卡": 6, "茜特菈莉": 6, "希诺宁": 6, "基尼奇": 6}}
+	satisfied := findSatisfiedCombos(account, set)
+	if len(satisfied) != 3 {
+		t.Fatalf("Expected 3 resolved combos, got %d", len(satisfied))
+	}
+	for _, combo := range satisfied {
+		for _, req := range combo.MatchedAlternatives {
+			if req.Name == "玛薇卡" {
+				t.Errorf("Fixed requirement must not be reused by attribute requirement: %v", combo.MatchedAlternatives)
+			}
+		}
+	}
+
+	bonus, best := findBestComboSelection(satisfied, make(map[string]bool))
+	if len(best) != 1 || bonus != 300 {
+		t.Errorf("Expected the combo to be selected once, got %d combos with bonus %.0f", len(best), bonus)
+	}
+
+	delete(account.Characters, "基尼奇")
+	account.Characters["希诺宁"] = 5
+	if got := len(findSatisfiedCombos(account, set)); got != 0 {
+		t.Errorf("Expected no combo when only one attribute match remains, got %d", got)
+	}
+
+	if got := (AttributeRequirement{Region: "纳塔", MinConst: 6, MaxConst: 6, Count: 3}).String(); got != "3个6命纳塔角色" {
+		t.Errorf("Unexpected description: %s", got)
+	}
+}
+
+func TestFindBestComboSelection_SharedCharacters(t *testing.T) {
+	combos := []ComboRule{
+		{Name: "6玛薇卡+茜特菈莉", Value: 400, RequiredChars: []RequiredChar{{Name: "玛薇卡", MinConst: 6, MaxConst: 6}, {Name: "茜特菈莉", MaxConst: 6}},
+			ShareableChars: []string{"茜特菈莉"}, SharingDiscount: 0.5},
+		{Name: "6恰斯卡+茜特菈莉", Value: 300, RequiredChars: []RequiredChar{{Name: "恰斯卡", MinConst: 6, MaxConst: 6}, {Name: "茜特菈莉", MaxConst: 6}},
+			ShareableChars: []string{"茜特菈莉"}, SharingDiscount: 0.5},
+		{Name: "6丝柯克+茜特菈莉", Value: 200, RequiredChars: []RequiredChar{{Name: "丝柯克", MinConst: 6, MaxConst: 6}, {Name: "茜特菈莉", MaxConst: 6}},
+			ShareableChars: []string{"茜特菈莉"}, SharingDiscount: 0.5},
+	}
+
+	bonus, best := findBestComboSelection(combos, make(map[string]bool))
+	if bonus != 550 || len(best) != 2 {
+		t.Fatalf("Expected the support to be shared by exactly two combos for 550, got %.0f with %d combos", bonus, len(best))
+	}
+	for _, combo := range best {
+		if combo.Name == "6恰斯卡+茜特菈莉" && (len(combo.SharedChars) != 1 || combo.EffectiveValue() != 150) {
+			t.Errorf("Expected the lower-value combo to pay the discount, got %v (%.0f)", combo.SharedChars, combo.EffectiveValue())
+		}
+	}
+
+	// 只有一方声明可共享时不能共享
+	combos[0].ShareableChars = nil
+	if bonus, _ := findBestComboSelection(combos, make(map[string]bool)); bonus != 400 {
+		t.Errorf("Expected no sharing when the first combo does not allow it, got %.0f", bonus)
+	}
+}
+
+func TestFindBestComboSelection_AsymmetricDiscounts(t *testing.T) {
+	combos := []ComboRule{
+		{Name: "6玛薇卡+茜特菈莉", Value: 400, RequiredChars: []RequiredChar{{Name: "玛薇卡", MinConst: 6, MaxConst: 6}, {Name: "茜特菈莉", MaxConst: 6}},
+			ShareableChars: []string{"茜特菈莉"}},
+		{Name: "6恰斯卡+茜特菈莉", Value: 300, RequiredChars: []RequiredChar{{Name: "恰斯卡", MinConst: 6, MaxConst: 6}, {Name: "茜特菈莉", MaxConst: 6}},
+			ShareableChars: []string{"茜特菈莉"}, SharingDiscount: 0.9},
+	}
+
+	// 由折扣为 0 的组合计价共享角色，两组合价值全额计入
+	for _, order := range [][]ComboRule{combos, {combos[1], combos[0]}} {
+		bonus, best := findBestComboSelection(order, make(map[string]bool))
+		if bonus != 700 || len(best) != 2 {
+			t.Fatalf("Expected both combos for 700, got %.0f with %d combos", bonus, len(best))
+		}
+		for _, combo := range best {
+			wantShared := combo.Name == "6玛薇卡+茜特菈莉"
+			if (len(combo.SharedChars) == 1) != wantShared || combo.EffectiveValue() != combo.Value {
+				t.Errorf("%s: SharedChars %v, effective %.0f", combo.Name, combo.SharedChars, combo.EffectiveValue())
+			}
+		}
+	}
+}
+
+func TestEvaluate_Sources(t *testing.T) {
+	account := eval.Assets{
+		Characters: map[string]int{"玛薇卡": 6, "茜特菈莉": 6},
+		Weapons:    map[string]int{"焚曜千阳": 5},
+		YuanShi:    160 * 550,
+	}
+	report := New().Evaluate(account)
+	for _, line := range []string{
+		"角色 [玛薇卡 6命]: 500.00 ← 角色:玛薇卡\n",
+		"← 武器:焚曜千阳\n",
+		"6玛薇卡+6茜特菈莉 (附加 300.00) ← 组合:6玛薇卡+6茜特菈莉\n",
+		"← 资源档位:500\n",
+	} {
+		if !strings.Contains(report.Breakdown, line) {
+			t.Errorf("Expected breakdown to contain %q, got:\n%s", line, report.Breakdown)
+		}
+	}
+	want := Provenance{Source: ruleDocument, Section: "角色价格表 [cite: 22, 228]", Previous: "6命 550"}
+	if got := report.Sources["角色:玛薇卡"]; got != want {
+		t.Errorf("Sources[角色:玛薇卡] = %+v, want %+v", got, want)
+	}
+	for _, id := range []string{"武器:焚曜千阳", "组合:6玛薇卡+6茜特菈莉", "角色数量档位:0-10", "资源档位:500"} {
+		if _, ok := report.Sources[id]; !ok {
+			t.Errorf("Expected Sources to contain %s, got %v", id, report.Sources)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package newrule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+// ValuationState 是估值流水线各阶段之间传递的状态
+type ValuationState struct {
+	Account eval.Assets
+
+	BestCombos []ComboRule
+	ComboBonus float64
+
+	ApplicableValue         float64 // 适用乘数的基础价值
+	ExemptValue             float64 // 豁免乘数的基础价值
+	AdjustedApplicableValue float64 // 应用角色数量乘数后的适用价值
+	BaseValue               float64 // 总基础价值
+
+	ResourceValue float64
+	SpecialBonus  float64
+	Adjustments   []Adjustment // 费用、封顶、折扣等调整
+
+	Rules   *ruleSet    // 本次估值所用的规则
+	Sources RuleSources // 明细中各金额所依据的规则条目
+	At      time.Time   // 估值时间，决定生效的促销
+
+	Total float64
+	step  int
+}
+
+// Adjustment 是对估值的一项调整
+type Adjustment struct {
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount"`
+	Kind   string  `json:"kind,omitempty"` // 调整来源，促销产生的调整为 AdjustmentPromo
+}
+
+// RunningTotal 返回截至当前阶段的合计
+func (s *ValuationState) RunningTotal() float64 {
+	total := s.BaseValue + s.ComboBonus + s.ResourceValue + s.SpecialBonus
+	for _, adj := range s.Adjustments {
+		total += adj.Amount
+	}
+	return total
+}
+
+// Stage 是估值流水线中的一个阶段，读写估值状态并输出自己的明细
+type Stage interface {
+	Title() string
+	Run(state *ValuationState, sb *strings.Builder)
+}
+
+// StageConfig 是流水线阶段的数据配置，Type 决定由哪个已注册的实现解释
+type StageConfig struct {
+	Type  string `json:"type"`
+	Title string `json:"title,omitempty"` // 为空时使用阶段的默认标题
+
+	Rate   float64 `json:"rate,omitempty"`   // fee / risk_discount: 按当前合计的比例扣减
+	Amount float64 `json:"amount,omitempty"` // fee: 固定扣减; cap: 估值上限
+}
+
+// StageFactory 根据配置构造流水线阶段
+type StageFactory func(cfg StageConfig) (Stage, error)
+
+var stageFactories = map[string]StageFactory{}
+
+// RegisterStage 注册一种流水线阶段类型
+func RegisterStage(stageType string, factory StageFactory) {
+	stageFactories[stageType] = factory
+}
+
+func init() {
+	RegisterStage("combos", simpleStage("计算最优溢价组合附加价值", runComboStage))
+	RegisterStage("base_value", simpleStage("计算并区分角色与武器的基础价值", runBaseValueStage))
+	RegisterStage("char_count_multiplier", simpleStage("对适用部分应用角色数量乘数", runMultiplierStage))
+	RegisterStage("subtotal", simpleStage("计算总基础价值", runSubtotalStage))
+	RegisterStage("resources", simpleStage("计算资源价值", runResourceStage))
+	RegisterStage("special_rules", simpleStage("应用特殊规则附加增益", runSpecialRuleStage))
+	RegisterStage("total", simpleStage("最终合计", runTotalStage))
+	RegisterStage("fee", simpleStage("扣除交易费用", runFeeStage))
+	RegisterStage("risk_discount", simpleStage("风险折扣", runRiskDiscountStage))
+	RegisterStage("cap", simpleStage("估值封顶", runCapStage))
+	RegisterStage("promo", simpleStage("应用限时促销调整", runPromoStage))
+}
+
+// DefaultPipeline 是默认的估值流程
+var DefaultPipeline = []StageConfig{
+	{Type: "combos"},
+	{Type: "base_value"},
+	{Type: "char_count_multiplier"},
+	{Type: "subtotal"},
+	{Type: "resources"},
+	{Type: "special_rules"},
+	{Type: "promo"},
+	{Type: "total"},
+}
+
+// funcStage 用函数实现的阶段
+type funcStage struct {
+	cfg   StageConfig
+	title string
+	run   func(cfg StageConfig, title string, state *ValuationState, sb *strings.Builder)
+}
+
+func (s *funcStage) Title() string { return s.title }
+
+func (s *funcStage) Run(state *ValuationState, sb *strings.Builder) {
+	s.run(s.cfg, s.title, state, sb)
+}
+
+func simpleStage(defaultTitle string, run func(cfg StageConfig, title string, state *ValuationState, sb *strings.Builder)) StageFactory {
+	return func(cfg StageConfig) (Stage, error) {
+		title := defaultTitle
+		if cfg.Title != "" {
+			title = cfg.Title
+		}
+		return &funcStage{cfg: cfg, title: title, run: run}, nil
+	}
+}
+
+// buildStage 按配置构造流水线阶段
+func buildStage(cfg StageConfig) (Stage, error) {
+	factory, ok := stageFactories[cfg.Type]
+	if !ok {
+		return nil, fmt.Errorf("未知的估值阶段类型 %q", cfg.Type)
+	}
+	return factory(cfg)
+}
+
+// runPipeline 按规则集 set 中的估值流程依次执行各阶段，at 为估值时间
+func runPipeline(account eval.Assets, set *ruleSet, at time.Time) (*ValuationState, string) {
+	state := &ValuationState{Account: account, Rules: set, Sources: RuleSources{}, At: at}
+	var sb strings.Builder
+	hasTotal := false
+	for i, stage := range set.stages {
+		state.step++
+		stage.Run(state, &sb)
+		if set.Pipeline[i].Type == "total" {
+			hasTotal = true
+		}
+	}
+	if !hasTotal {
+		state.Total = state.RunningTotal()
+	}
+	return state, sb.String()
+}
+
+// writeStep 以统一的格式输出一个步骤
+func writeStep(sb *strings.Builder, state *ValuationState, title, body string) {
+	fmt.Fprintf(sb, "<div class='step'><h3>步骤%s: %s</h3><pre>", chineseNumber(state.step), title)
+	sb.WriteString(body)
+	sb.WriteString("</pre></div>")
+}
+
+func runComboStage(cfg StageConfig, title string, state *ValuationState, sb *strings.Builder) {
+	var body strings.Builder
+	satisfiedCombos := findSatisfiedCombos(state.Account, state.Rules)
+	state.ComboBonus, state.BestCombos = findBestComboSelection(satisfiedCombos, make(map[string]bool))
+	if len(state.BestCombos) > 0 {
+		fmt.Fprintf(&body, "命中以下最优组合方案，获得附加价值: %.2f\n", state.ComboBonus)
+		for _, combo := range state.BestCombos {
+			fmt.Fprintf(&body, "  - [%s] %s (附加 %.2f)", combo.Group, combo.Name, combo.EffectiveValue())
+			cite := state.Sources.cite(combo.ref())
+			if len(combo.MatchedAlternatives) > 0 {
+				names := make([]string, 0, len(combo.MatchedAlternatives))
+				for _, req := range combo.MatchedAlternatives {
+					names = append(names, req.String())
+				}
+				fmt.Fprintf(&body, " (选用: %s)", strings.Join(names, ", "))
+			}
+			if len(combo.SharedChars) > 0 {
+				fmt.Fprintf(&body, " (共享角色: %s，原价 %.2f 折扣 %.0f%%)", strings.Join(combo.SharedChars, ", "), combo.Value, combo.SharingDiscount*100)
+			}
+			body.WriteString(cite + "\n")
+		}
+	} else {
+		body.WriteString("未命中任何溢价组合。\n")
+	}
+	writeStep(sb, state, title, body.String())
+}
+
+func runBaseValueStage(cfg StageConfig, title string, state *ValuationState, sb *strings.Builder) {
+	var body strings.Builder
+	applicableValue, exemptValue, baseValueBreakdown := calculateBaseValue(state.Account, state.BestCombos, state.Rules, state.Sources)
+	state.ApplicableValue, state.ExemptValue = applicableValue, exemptValue
+	state.AdjustedApplicableValue = applicableValue
+	state.BaseValue = applicableValue + exemptValue
+	body.WriteString(baseValueBreakdown)
+	fmt.Fprintf(&body, "\n&gt;&gt; 适用乘数的基础价值: %.2f\n", applicableValue)
+	fmt.Fprintf(&body, "&gt;&gt; 豁免乘数的基础价值: %.2f\n", exemptValue)
+	writeStep(sb, state, title, body.String())
+}
+
+func runMultiplierStage(cfg StageConfig, title string, state *ValuationState, sb *strings.Builder) {
+	var body strings.Builder
+	charCount, countBreakdown := countCharacters(state.Account, state.Rules)
+	body.WriteString(countBreakdown)
+	adjusted, multiplierBreakdown := applyCharacterCountMultiplier(state.ApplicableValue, charCount, state.Rules, state.Sources)
+	body.WriteString(multiplierBreakdown)
+	state.AdjustedApplicableValue = adjusted
+	state.BaseValue = adjusted + state.ExemptValue
+	writeStep(sb, state, title, body.String())
+}
+
+func runSubtotalStage(cfg StageConfig, title string, state *ValuationState, sb *strings.Builder) {
+	fmt.Fprintf(sb, "<div class='subtotal'><p>总基础价值 = 调整后适用价值 (%.2f) + 豁免价值 (%.2f) = <strong>%.2f</strong></p></div>", state.AdjustedApplicableValue, state.ExemptValue, state.BaseValue)
+}
+
+func runResourceStage(cfg StageConfig, title string, state *ValuationState, sb *strings.Builder) {
+	value, breakdown := calculateResourceValue(state.Account, state.Rules, state.At, state.Sources)
+	state.ResourceValue = value
+	writeStep(sb, state, title, breakdown)
+}
+
+func runSpecialRuleStage(cfg StageConfig, title string, state *ValuationState, sb *strings.Builder) {
+	bonus, breakdown := applySpecialRules(state.Account, state.BestCombos, state.Rules)
+	state.SpecialBonus = bonus
+	writeStep(sb, state, title, breakdown)
+}
+
+func runTotalStage(cfg StageConfig, title string, state *ValuationState, sb *strings.Builder) {
+	state.Total = state.RunningTotal()
+	fmt.Fprintf(sb, "<div class='final-total'><h3>%s</h3>", title)
+	fmt.Fprintf(sb, "<p>总基础价值    : %.2f</p>", state.BaseValue)
+	fmt.Fprintf(sb, "<p>组合附加价值  : %.2f</p>", state.ComboBonus)
+	fmt.Fprintf(sb, "<p>资源价值      : %.2f</p>", state.ResourceValue)
+	fmt.Fprintf(sb, "<p>特殊规则增益  : %.2f</p>", state.SpecialBonus)
+	for _, adj := range state.Adjustments {
+		fmt.Fprintf(sb, "<p>%s  : %.2f</p>", adj.Name, adj.Amount)
+	}
+	fmt.Fprintf(sb, "<hr><p><strong>账号总估值: %.2f</strong></p>", state.Total)
+	fmt.Fprintf(sb, "</div>")
+}
+
+func runFeeStage(cfg StageConfig, title string, state *ValuationState, sb *strings.Builder) {
+	running := state.RunningTotal()
+	fee := running*cfg.Rate + cfg.Amount
+	state.Adjustments = append(state.Adjustments, Adjustment{Name: title, Amount: -fee})
+	writeStep(sb, state, title, fmt.Sprintf("当前合计 %.2f，费率 %.2f%% + 固定 %.2f，扣除 %.2f\n", running, cfg.Rate*100, cfg.Amount, fee))
+}
+
+func runRiskDiscountStage(cfg StageConfig, title string, state *ValuationState, sb *strings.Builder) {
+	running := state.RunningTotal()
+	discount := running * cfg.Rate
+	state.Adjustments = append(state.Adjustments, Adjustment{Name: title, Amount: -discount})
+	writeStep(sb, state, title, fmt.Sprintf("当前合计 %.2f，折扣 %.2f%%，扣除 %.2f\n", running, cfg.Rate*100, discount))
+}
+
+func runCapStage(cfg StageConfig, title string, state *ValuationState, sb *strings.Builder) {
+	running := state.RunningTotal()
+	if running <= cfg.Amount {
+		writeStep(sb, state, title, fmt.Sprintf("当前合计 %.2f 未超过上限 %.2f\n", running, cfg.Amount))
+		return
+	}
+	state.Adjustments = append(state.Adjustments, Adjustment{Name: title, Amount: cfg.Amount - running})
+	writeStep(sb, state, title, fmt.Sprintf("当前合计 %.2f 超过上限 %.2f，按上限计价\n", running, cfg.Amount))
+}
+
+var chineseDigits = []string{"零", "一", "二", "三", "四", "五", "六", "七", "八", "九"}
+
+// chineseNumber 将 1-99 的数字转换为中文数字
+func chineseNumber(n int) string {
+	switch {
+	case n < 10:
+		return chineseDigits[n]
+	case n == 10:
+		return "十"
+	case n < 20:
+		return "十" + chineseDigits[n%10]
+	case n < 100 && n%10 == 0:
+		return chineseDigits[n/10] + "十"
+	case n < 100:
+		return chineseDigits[n/10] + "十" + chineseDigits[n%10]
+	}
+	return fmt.Sprint(n)
+}
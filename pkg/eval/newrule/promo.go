@@ -0,0 +1,140 @@
+package newrule
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// 促销调整的适用范围
+const (
+	PromoCharacter = "character" // 角色价格，取基础价值中计入的价格 (按命座取价，缺专武时含8折)
+	PromoWeapon    = "weapon"    // 武器价格，按账号中的精炼取价
+	PromoCombo     = "combo"     // 最优方案中命中的组合价值
+)
+
+// AdjustmentPromo 标记促销产生的调整
+const AdjustmentPromo = "promo"
+
+// Promotion 是叠加在基础规则之上的限时价格调整，如卡池复刻期间临时上调或下调部分角色价格
+// 调整以单独的明细项计入合计，不改动价格表，也不参与角色数量乘数等折算
+// 促销可随规则版本一起登记 (ValuationRules.Promotions)，也可通过 RuleRegistry.AddPromotions 叠加在任意已登记的版本之上
+type Promotion struct {
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"`        // 开始时间 (含)
+	End   time.Time `json:"end,omitzero"` // 结束时间 (不含)，零值表示不设截止
+
+	Scope string   `json:"scope"`           // PromoCharacter、PromoWeapon 或 PromoCombo
+	Names []string `json:"names,omitempty"` // 适用的角色、武器或组合名称，为空时适用于该范围内全部条目
+
+	Rate   float64 `json:"rate,omitempty"`   // 按条目价格比例调整，0.2 为上调 20%，-0.1 为下调 10%
+	Amount float64 `json:"amount,omitempty"` // 每个条目的固定调整金额
+
+	Provenance Provenance `json:"provenance,omitzero"`
+}
+
+// Active 判断促销在时间 t 是否生效
+func (p Promotion) Active(t time.Time) bool {
+	return !t.Before(p.Start) && (p.End.IsZero() || t.Before(p.End))
+}
+
+// RuleID 返回促销条目的 ID
+func (p Promotion) RuleID() string { return p.ref().ID }
+
+func (p Promotion) ref() ruleRef { return ruleRef{"促销:" + p.Name, p.Provenance} }
+
+func (p Promotion) applies(name string) bool {
+	return len(p.Names) == 0 || slices.Contains(p.Names, name)
+}
+
+// adjust 返回按价格 price 计算的调整金额
+func (p Promotion) adjust(price float64) float64 {
+	return price*p.Rate + p.Amount
+}
+
+// validatePromotion 检查促销的时间、范围与调整方式，适用的条目须出现在 rulesets 中的任一规则集中
+func validatePromotion(p Promotion, rulesets ...ValuationRules) []error {
+	var errs []error
+	if p.Name == "" {
+		errs = append(errs, fmt.Errorf("促销缺少名称"))
+	}
+	if !p.End.IsZero() && !p.End.After(p.Start) {
+		errs = append(errs, fmt.Errorf("促销 %q 的结束时间不晚于开始时间", p.Name))
+	}
+	if p.Rate == 0 && p.Amount == 0 {
+		errs = append(errs, fmt.Errorf("促销 %q 未设置调整比例或金额", p.Name))
+	}
+	if p.Rate < -1 {
+		errs = append(errs, fmt.Errorf("促销 %q 的调整比例 %.2f 低于 -100%%", p.Name, p.Rate))
+	}
+	var known func(r ValuationRules, name string) bool
+	switch p.Scope {
+	case PromoCharacter:
+		known = func(r ValuationRules, name string) bool { _, ok := r.Characters[name]; return ok }
+	case PromoWeapon:
+		known = func(r ValuationRules, name string) bool { _, ok := r.Weapons[name]; return ok }
+	case PromoCombo:
+		known = func(r ValuationRules, name string) bool {
+			return slices.ContainsFunc(r.Combos, func(c ComboRule) bool { return c.Name == name })
+		}
+	default:
+		return append(errs, fmt.Errorf("促销 %q 的适用范围 %q 未知", p.Name, p.Scope))
+	}
+	for _, name := range p.Names {
+		if !slices.ContainsFunc(rulesets, func(r ValuationRules) bool { return known(r, name) }) {
+			errs = append(errs, fmt.Errorf("促销 %q 适用的 %s 不在规则中", p.Name, name))
+		}
+	}
+	return errs
+}
+
+// hasPromoStage 判断估值流程中是否有应用促销的阶段
+func hasPromoStage(pipeline []StageConfig) bool {
+	return slices.ContainsFunc(pipeline, func(cfg StageConfig) bool { return cfg.Type == "promo" })
+}
+
+// runPromoStage 按估值时间生效的促销逐条目计入调整，没有生效的促销时不输出步骤
+func runPromoStage(cfg StageConfig, title string, state *ValuationState, sb *strings.Builder) {
+	var body strings.Builder
+	for _, promo := range state.Rules.Promotions {
+		if !promo.Active(state.At) {
+			continue
+		}
+		cite := state.Sources.cite(promo.ref())
+		add := func(item string, price float64) {
+			amount := promo.adjust(price)
+			state.Adjustments = append(state.Adjustments, Adjustment{Name: promo.Name + " " + item, Amount: amount, Kind: AdjustmentPromo})
+			fmt.Fprintf(&body, "  - [%s] %s: 价格 %.2f，调整 %+.2f%s\n", promo.Name, item, price, amount, cite)
+		}
+		switch promo.Scope {
+		case PromoCharacter:
+			for _, name := range slices.Sorted(maps.Keys(state.Account.Characters)) {
+				c := state.Account.Characters[name]
+				if info, ok := state.Rules.Characters[name]; ok && promo.applies(name) && c >= 0 && c <= 6 {
+					price, reason := characterBookedValue(state.Account, info, c)
+					add(fmt.Sprintf("角色 [%s %d命]%s", name, c, reason), price)
+				}
+			}
+		case PromoWeapon:
+			for _, name := range slices.Sorted(maps.Keys(state.Account.Weapons)) {
+				refine := state.Account.Weapons[name]
+				if info, ok := state.Rules.Weapons[name]; ok && promo.applies(name) && refine >= 1 && refine <= 5 {
+					add(fmt.Sprintf("武器 [%s 精%d]", name, refine), info.Prices[refine-1])
+				}
+			}
+		case PromoCombo:
+			for _, combo := range state.BestCombos {
+				if promo.applies(combo.Name) {
+					add(fmt.Sprintf("组合 [%s]", combo.Name), combo.EffectiveValue())
+				}
+			}
+		}
+	}
+	if body.Len() == 0 {
+		return
+	}
+	fmt.Fprintf(&body, "\n&gt;&gt; 估值时间 %s\n", state.At.Format(time.DateTime))
+	writeStep(sb, state, title, body.String())
+}
@@ -0,0 +1,182 @@
+package newrule
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+func TestPromotion_Active(t *testing.T) {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	p := Promotion{Start: start, End: start.AddDate(0, 0, 21)}
+	for _, c := range []struct {
+		at   time.Time
+		want bool
+	}{{start.Add(-time.Second), false}, {start, true}, {start.AddDate(0, 0, 20), true}, {p.End, false}} {
+		if got := p.Active(c.at); got != c.want {
+			t.Errorf("Active(%s) = %v, want %v", c.at, got, c.want)
+		}
+	}
+	if open := (Promotion{Start: start}); !open.Active(start.AddDate(10, 0, 0)) {
+		t.Error("promotion without end should stay active")
+	}
+}
+
+func TestEvaluateWith_Promotions(t *testing.T) {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	r := rules
+	r.Promotions = []Promotion{
+		{Name: "玛薇卡复刻", Start: start, End: start.AddDate(0, 0, 21), Scope: PromoCharacter, Names: []string{"玛薇卡"}, Rate: -0.1,
+			Provenance: Provenance{Source: "运营公告"}},
+		{Name: "武器池加价", Start: start, Scope: PromoWeapon, Amount: 50},
+		{Name: "组合加价", Start: start, Scope: PromoCombo, Names: []string{"6玛薇卡+6茜特菈莉"}, Rate: 0.5},
+		{Name: "已结束", Start: start.AddDate(-1, 0, 0), End: start.AddDate(0, -1, 0), Scope: PromoCharacter, Amount: 1000},
+	}
+	// 促销与所在的规则版本一起登记
+	registry := NewRuleRegistry()
+	if err := registry.Register(RuleSetVersion{Version: "2025-10", Rules: r}); err != nil {
+		t.Fatal(err)
+	}
+
+	account := eval.Assets{
+		Characters: map[string]int{"玛薇卡": 6, "茜特菈莉": 6},
+		Weapons:    map[string]int{"焚曜千阳": 1, "未知武器": 5},
+	}
+	n := New()
+	base, err := n.EvaluateWith(account, WithRegistry(registry), AsOf(start.Add(-time.Hour)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(base.Adjustments) != 0 || strings.Contains(base.Breakdown, "促销") {
+		t.Errorf("no promotion should apply before the start: %+v", base.Adjustments)
+	}
+
+	report, err := n.EvaluateWith(account, WithRegistry(registry), AsOf(start.AddDate(0, 0, 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Adjustment{
+		{Name: "玛薇卡复刻 角色 [玛薇卡 6命]", Amount: -0.1 * rules.Characters["玛薇卡"].Prices[6], Kind: AdjustmentPromo},
+		{Name: "武器池加价 武器 [焚曜千阳 精1]", Amount: 50, Kind: AdjustmentPromo},
+		{Name: "组合加价 组合 [6玛薇卡+6茜特菈莉]", Amount: 150, Kind: AdjustmentPromo},
+	}
+	if len(report.Adjustments) != len(want) {
+		t.Fatalf("Adjustments = %+v, want %+v", report.Adjustments, want)
+	}
+	var sum float64
+	for i, adj := range report.Adjustments {
+		if adj != want[i] {
+			t.Errorf("Adjustments[%d] = %+v, want %+v", i, adj, want[i])
+		}
+		sum += adj.Amount
+	}
+	if diff := report.FinalTotal - base.FinalTotal; diff != sum {
+		t.Errorf("FinalTotal difference = %.2f, want %.2f", diff, sum)
+	}
+	if !strings.Contains(report.Breakdown, "应用限时促销调整") || !strings.Contains(report.Breakdown, "← 促销:玛薇卡复刻") {
+		t.Errorf("breakdown should itemise promotions:\n%s", report.Breakdown)
+	}
+	if got := report.Sources["促销:玛薇卡复刻"]; got.Source != "运营公告" {
+		t.Errorf("Sources[促销:玛薇卡复刻] = %+v", got)
+	}
+}
+
+func TestValidateRules_Promotions(t *testing.T) {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	r := rules
+	r.Promotions = []Promotion{
+		{Name: "a", Start: start, End: start, Scope: PromoCharacter, Amount: 1},
+		{Name: "b", Start: start, Scope: PromoWeapon},
+		{Name: "c", Start: start, Scope: "region", Rate: 0.1},
+		{Name: "d", Start: start, Scope: PromoCombo, Names: []string{"不存在的组合"}, Rate: -2},
+	}
+	var got []string
+	for _, err := range ValidateRules(r) {
+		got = append(got, err.Error())
+	}
+	want := []string{
+		`促销 "a" 的结束时间不晚于开始时间`,
+		`促销 "b" 未设置调整比例或金额`,
+		`促销 "c" 的适用范围 "region" 未知`,
+		`促销 "d" 的调整比例 -2.00 低于 -100%`,
+		`促销 "d" 适用的 不存在的组合 不在规则中`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("errors =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// 估值流程中没有 promo 阶段时促销不会生效
+	r.Promotions = []Promotion{{Name: "e", Start: start, Scope: PromoWeapon, Amount: 50}}
+	r.Pipeline = []StageConfig{{Type: "combos"}, {Type: "total"}}
+	if errs := ValidateRules(r); len(errs) != 1 || !strings.Contains(errs[0].Error(), "promo 阶段") {
+		t.Errorf("Expected missing promo stage to be rejected, got %v", errs)
+	}
+}
+
+func TestPromotion_CharacterBookedValue(t *testing.T) {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	r := rules
+	r.Promotions = []Promotion{{Name: "玛薇卡复刻", Start: start, Scope: PromoCharacter, Names: []string{"玛薇卡"}, Rate: -0.1}}
+	set, err := compileRuleSet(r, "促销")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 未持有专武时角色按8折计入基础价值，促销按计入的价格调整
+	report := (&NewRule{rules: set}).EvaluateAt(eval.Assets{Characters: map[string]int{"玛薇卡": 6}}, start)
+	want := -0.1 * 0.8 * rules.Characters["玛薇卡"].Prices[6]
+	if len(report.Adjustments) != 1 || math.Abs(report.Adjustments[0].Amount-want) > 1e-9 {
+		t.Errorf("Adjustments = %+v, want amount %.2f", report.Adjustments, want)
+	}
+}
+
+func TestRuleRegistry_AddPromotions(t *testing.T) {
+	start := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	registry := NewRuleRegistry()
+	if err := registry.Register(RuleSetVersion{Version: "2025-09", Rules: rules}); err != nil {
+		t.Fatal(err)
+	}
+	// 促销不属于任何版本，不必为此再登记一版规则
+	if err := registry.Register(RuleSetVersion{Version: "2025-10", EffectiveFrom: start, Rules: rules}); err != nil {
+		t.Fatal(err)
+	}
+	noPromo := rules
+	noPromo.Pipeline = []StageConfig{{Type: "combos"}, {Type: "base_value"}, {Type: "subtotal"}, {Type: "total"}}
+	if err := registry.Register(RuleSetVersion{Version: "无促销阶段", EffectiveFrom: start.AddDate(1, 0, 0), Rules: noPromo}); err != nil {
+		t.Fatal(err)
+	}
+
+	promo := Promotion{Name: "武器池加价", Start: start, End: start.AddDate(0, 0, 21), Scope: PromoWeapon, Names: []string{"焚曜千阳"}, Amount: 50}
+	if err := registry.AddPromotions(promo); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.AddPromotions(promo); err == nil {
+		t.Error("Expected duplicate promotion to be rejected")
+	}
+	if err := registry.AddPromotions(Promotion{Name: "未知", Start: start, Scope: PromoWeapon, Names: []string{"不存在的武器"}, Amount: 1}); err == nil {
+		t.Error("Expected promotion on an unknown weapon to be rejected")
+	}
+
+	account := eval.Assets{Characters: map[string]int{"玛薇卡": 6}, Weapons: map[string]int{"焚曜千阳": 1}}
+	n := New()
+	at := start.AddDate(0, 0, 1)
+	for _, version := range []string{"2025-09", "2025-10"} {
+		report, err := n.EvaluateWith(account, WithRegistry(registry), WithRuleVersion(version), AsOf(at))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := Adjustment{Name: "武器池加价 武器 [焚曜千阳 精1]", Amount: 50, Kind: AdjustmentPromo}
+		if len(report.Adjustments) != 1 || report.Adjustments[0] != want {
+			t.Errorf("%s: Adjustments = %+v, want %+v", version, report.Adjustments, want)
+		}
+	}
+	if _, err := n.EvaluateWith(account, WithRegistry(registry), WithRuleVersion("无促销阶段"), AsOf(at)); err == nil {
+		t.Error("Expected an active promotion on a pipeline without promo stage to be rejected")
+	}
+	// 促销结束后可按没有 promo 阶段的版本估值
+	if _, err := n.EvaluateWith(account, WithRegistry(registry), WithRuleVersion("无促销阶段"), AsOf(promo.End)); err != nil {
+		t.Errorf("EvaluateWith after the promotion ended: %v", err)
+	}
+}
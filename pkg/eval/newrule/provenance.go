@@ -0,0 +1,89 @@
+package newrule
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ruleDocument 是内置规则所依据的文档
+const ruleDocument = "估值规则 Word 文档"
+
+// Provenance 记录规则条目的出处，估值有争议时据此追溯到原始文档
+type Provenance struct {
+	Source        string `json:"source,omitempty"`        // 来源文档
+	Section       string `json:"section,omitempty"`       // 文档中的章节或引用位置
+	EffectiveDate string `json:"effectiveDate,omitempty"` // 生效日期 (YYYY-MM-DD)
+	Previous      string `json:"previous,omitempty"`      // 调整前的取值，如 "6命 550"
+}
+
+func (p Provenance) String() string {
+	var parts []string
+	for _, s := range []string{p.Source, p.Section} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if p.EffectiveDate != "" {
+		parts = append(parts, p.EffectiveDate+" 生效")
+	}
+	if p.Previous != "" {
+		parts = append(parts, "调整前 "+p.Previous)
+	}
+	if len(parts) == 0 {
+		return "出处未记录"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ruleRef 是估值明细引用的一个规则条目
+type ruleRef struct {
+	ID         string
+	Provenance Provenance
+}
+
+// 规则条目的 ID 由条目类型与名称 (档位为起点) 组成，如 "角色:玛薇卡"、"资源档位:500"
+// 同名组合共用一个 ID，明细中的组合价值可区分具体条目
+
+// RuleID 返回角色价格条目的 ID
+func (c CharacterInfo) RuleID() string { return c.ref().ID }
+
+// RuleID 返回武器价格条目的 ID
+func (w WeaponInfo) RuleID() string { return w.ref().ID }
+
+// RuleID 返回组合条目的 ID
+func (combo ComboRule) RuleID() string { return combo.ref().ID }
+
+// RuleID 返回角色数量乘数档位的 ID
+func (t CharCountTier) RuleID() string { return t.ref().ID }
+
+// RuleID 返回资源单价档位的 ID
+func (t ResourceTier) RuleID() string { return t.ref().ID }
+
+func (c CharacterInfo) ref() ruleRef { return ruleRef{"角色:" + c.Name, c.Provenance} }
+func (w WeaponInfo) ref() ruleRef    { return ruleRef{"武器:" + w.Name, w.Provenance} }
+func (combo ComboRule) ref() ruleRef { return ruleRef{"组合:" + combo.Name, combo.Provenance} }
+func (t CharCountTier) ref() ruleRef {
+	return ruleRef{fmt.Sprintf("角色数量档位:%d-%d", t.MinCount, t.MaxCount), t.Provenance}
+}
+func (t ResourceTier) ref() ruleRef {
+	return ruleRef{fmt.Sprintf("资源档位:%d", t.MinFates), t.Provenance}
+}
+
+// RuleSources 记录一次估值中产生金额的规则条目 (ID -> 出处)
+type RuleSources map[string]Provenance
+
+// cite 记录规则条目，返回附在明细行末的引用，如 " ← 武器:焚曜千阳, 组合:6玛薇卡+6茜特菈莉"
+// s 为 nil 时只生成引用，没有条目时返回空
+func (s RuleSources) cite(refs ...ruleRef) string {
+	if len(refs) == 0 {
+		return ""
+	}
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		ids = append(ids, ref.ID)
+		if s != nil {
+			s[ref.ID] = ref.Provenance
+		}
+	}
+	return " ← " + strings.Join(ids, ", ")
+}
@@ -0,0 +1,99 @@
+package newrule
+
+import (
+	"bufio"
+	"encoding/json"
+	"math"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+// loadBaselineAccounts 读取基线估值用例
+func loadBaselineAccounts(t *testing.T) []baselineCase {
+	t.Helper()
+	f, err := os.Open("testdata/baseline_valuations.jsonl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var cases []baselineCase
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for line := 1; scanner.Scan(); line++ {
+		var c baselineCase
+		if err := json.Unmarshal(scanner.Bytes(), &c); err != nil {
+			t.Fatalf("line %d: %v", line, err)
+		}
+		cases = append(cases, c)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return cases
+}
+
+type baselineCase struct {
+	eval.Assets
+	Total float64 `json:"total"`
+}
+
+// TestCalculateValuation_Baseline 以基线版本对随机账号的完整估值为基准，防止组合排序等改动意外改变报价
+// 第 606、607 行含不在价格表内的限定五星角色，基线将其计入角色数量
+func TestCalculateValuation_Baseline(t *testing.T) {
+	n := New()
+	for i, c := range loadBaselineAccounts(t) {
+		if got := n.CalculateValuation(c.Assets).FinalTotal; math.Abs(got-c.Total) > 1e-6 {
+			t.Errorf("line %d: FinalTotal = %.2f, want %.2f", i+1, got, c.Total)
+		}
+	}
+}
+
+// TestCalculateValuation_ComboOrder 组合的排列顺序不影响估值
+func TestCalculateValuation_ComboOrder(t *testing.T) {
+	cases := loadBaselineAccounts(t)
+	sorted := New()
+	r := rules
+	r.Combos = slices.Clone(rules.Combos)
+	slices.Reverse(r.Combos)
+	reversed := newRuleWith(t, r)
+	for i, c := range cases {
+		if got, want := reversed.CalculateValuation(c.Assets).FinalTotal, sorted.CalculateValuation(c.Assets).FinalTotal; got != want {
+			t.Errorf("line %d: FinalTotal with reversed combos = %.2f, want %.2f", i+1, got, want)
+		}
+	}
+}
+
+// TestCalculateValuation_AlternativesMatchSeparateCombos 替代要求组与逐条列出各选法的组合估值相同
+func TestCalculateValuation_AlternativesMatchSeparateCombos(t *testing.T) {
+	cases := loadBaselineAccounts(t)
+	merged := New()
+
+	// 按基线的写法把 "满足其一" 的要求组拆回各自独立的组合
+	r := rules
+	r.Combos = nil
+	for _, combo := range rules.Combos {
+		if len(combo.RequiredGroups) != 1 || combo.RequiredGroups[0].MinMatch != 1 {
+			r.Combos = append(r.Combos, combo)
+			continue
+		}
+		for _, opt := range combo.RequiredGroups[0].Options {
+			split := combo
+			split.Name = combo.Name + "/" + opt.Name
+			split.RequiredChars = append(slices.Clone(combo.RequiredChars), opt)
+			split.RequiredGroups = nil
+			r.Combos = append(r.Combos, split)
+		}
+	}
+	SortCombos(r.Combos)
+	separate := newRuleWith(t, r)
+
+	for i, c := range cases {
+		if got, want := merged.CalculateValuation(c.Assets).FinalTotal, separate.CalculateValuation(c.Assets).FinalTotal; got != want {
+			t.Errorf("line %d: FinalTotal with requirement groups = %.2f, separate combos = %.2f", i+1, got, want)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package newrule
+
+import (
+	"time"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+// ValuationReport 是结构化的估值结果，各项数值与明细中的步骤一一对应
+type ValuationReport struct {
+	FinalTotal float64 `json:"finalTotal"`
+
+	Combos     []ComboResult `json:"combos"`
+	ComboBonus float64       `json:"comboBonus"`
+
+	ApplicableValue         float64 `json:"applicableValue"`         // 适用乘数的基础价值
+	ExemptValue             float64 `json:"exemptValue"`             // 豁免乘数的基础价值
+	AdjustedApplicableValue float64 `json:"adjustedApplicableValue"` // 应用角色数量乘数后的适用价值
+	BaseValue               float64 `json:"baseValue"`               // 总基础价值
+
+	ResourceValue float64      `json:"resourceValue"`
+	SpecialBonus  float64      `json:"specialBonus"`
+	Adjustments   []Adjustment `json:"adjustments"`
+
+	Breakdown string `json:"breakdown"` // 与 CalculateValuation 相同的 HTML 明细，各金额行末以 "← ID" 注明所依据的规则条目
+
+	Sources RuleSources `json:"sources,omitempty"` // 明细中引用的规则条目 ID -> 出处
+
+	RuleVersion string `json:"ruleVersion"` // 所用规则的版本号
+}
+
+// ComboResult 是最优方案中的一个组合
+type ComboResult struct {
+	Name                string   `json:"name"`
+	Group               string   `json:"group"`
+	Value               float64  `json:"value"`
+	EffectiveValue      float64  `json:"effectiveValue"`                // 共享角色折扣后计入的价值
+	MatchedAlternatives []string `json:"matchedAlternatives,omitempty"` // 替代要求组或属性要求中选用的角色
+	SharedChars         []string `json:"sharedChars,omitempty"`
+}
+
+// Evaluate 按规则集中的估值流程估值，返回结构化的结果
+// 估值时间为当前时间: 规则含促销时结果随时间变化，需要可复现的结果时使用 EvaluateAt
+func (n *NewRule) Evaluate(account eval.Assets) ValuationReport {
+	return n.evaluate(account, time.Now())
+}
+
+// EvaluateAt 与 Evaluate 相同，但按指定的估值时间 at 判断促销是否生效
+func (n *NewRule) EvaluateAt(account eval.Assets, at time.Time) ValuationReport {
+	return n.evaluate(account, at)
+}
+
+// evaluate 按 n 的规则与估值时间 at 估值
+func (n *NewRule) evaluate(account eval.Assets, at time.Time) ValuationReport {
+	state, breakdown := runPipeline(account, n.rules, at)
+	report := ValuationReport{
+		FinalTotal:              state.Total,
+		Combos:                  make([]ComboResult, 0, len(state.BestCombos)),
+		ComboBonus:              state.ComboBonus,
+		ApplicableValue:         state.ApplicableValue,
+		ExemptValue:             state.ExemptValue,
+		AdjustedApplicableValue: state.AdjustedApplicableValue,
+		BaseValue:               state.BaseValue,
+		ResourceValue:           state.ResourceValue,
+		SpecialBonus:            state.SpecialBonus,
+		Adjustments:             append([]Adjustment{}, state.Adjustments...),
+		Breakdown:               breakdown,
+		RuleVersion:             n.rules.version,
+	}
+	if len(state.Sources) > 0 {
+		report.Sources = state.Sources
+	}
+	for _, combo := range state.BestCombos {
+		result := ComboResult{
+			Name:           combo.Name,
+			Group:          combo.Group,
+			Value:          combo.Value,
+			EffectiveValue: combo.EffectiveValue(),
+			SharedChars:    combo.SharedChars,
+		}
+		for _, req := range combo.MatchedAlternatives {
+			result.MatchedAlternatives = append(result.MatchedAlternatives, req.String())
+		}
+		report.Combos = append(report.Combos, result)
+	}
+	return report
+}
+
+// Rules 返回估值所用的规则
+func (n *NewRule) Rules() ValuationRules {
+	return n.rules.ValuationRules
+}
@@ -0,0 +1,220 @@
+package newrule
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+// SpecialRule 是一条可插拔的特殊规则，返回附加价值及明细行
+type SpecialRule interface {
+	Name() string
+	Apply(account eval.Assets, bestRules []ComboRule) (float64, []string)
+}
+
+// SpecialRuleConfig 是特殊规则的数据配置，Type 决定由哪个已注册的实现解释
+type SpecialRuleConfig struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+
+	Characters []string `json:"characters,omitempty"` // 规则涉及的角色
+	MinConst   int      `json:"minConst,omitempty"`   // 角色命座下限
+	MaxConst   int      `json:"maxConst,omitempty"`   // 角色命座上限
+
+	// combo_count: 最优方案中的满命组合内命中角色数量 -> 附加价值，取不超过命中数量的最大档位
+	CountBonuses map[int]float64 `json:"countBonuses,omitempty"`
+
+	// hot_character: 每个命中角色的附加价值，以及命中这些地区满命组合时不再附加
+	Bonus         float64  `json:"bonus,omitempty"`
+	ExemptRegions []string `json:"exemptRegions,omitempty"`
+
+	// 仅在最优方案含满命组合时生效
+	RequireMaxConstCombo bool `json:"requireMaxConstCombo,omitempty"`
+
+	// expr: "条件 => 动作" 规则语句，见 expr.go
+	Expr string `json:"expr,omitempty"`
+}
+
+// SpecialRuleFactory 根据配置构造特殊规则，r 为规则所在的规则集
+type SpecialRuleFactory func(cfg SpecialRuleConfig, r ValuationRules) (SpecialRule, error)
+
+var specialRuleFactories = map[string]SpecialRuleFactory{}
+
+// RegisterSpecialRule 注册一种特殊规则类型
+func RegisterSpecialRule(ruleType string, factory SpecialRuleFactory) {
+	specialRuleFactories[ruleType] = factory
+}
+
+func init() {
+	RegisterSpecialRule("combo_count", newComboCountRule)
+	RegisterSpecialRule("hot_character", newHotCharacterRule)
+	RegisterSpecialRule("expr", newExprSpecialRule)
+}
+
+// buildSpecialRule 按配置构造特殊规则
+func buildSpecialRule(cfg SpecialRuleConfig, r ValuationRules) (SpecialRule, error) {
+	factory, ok := specialRuleFactories[cfg.Type]
+	if !ok {
+		return nil, fmt.Errorf("未知的特殊规则类型 %q", cfg.Type)
+	}
+	return factory(cfg, r)
+}
+
+// comboCountRule 对最优方案中的每个满命组合，按组合内处于指定命座区间的特定角色数量附加价值
+type comboCountRule struct {
+	cfg        SpecialRuleConfig
+	thresholds []int
+}
+
+func newComboCountRule(cfg SpecialRuleConfig, _ ValuationRules) (SpecialRule, error) {
+	if len(cfg.CountBonuses) == 0 {
+		return nil, fmt.Errorf("特殊规则 %q 未配置数量档位", cfg.Name)
+	}
+	r := &comboCountRule{cfg: cfg}
+	for n := range cfg.CountBonuses {
+		r.thresholds = append(r.thresholds, n)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(r.thresholds)))
+	return r, nil
+}
+
+func (r *comboCountRule) Name() string { return r.cfg.Name }
+
+func (r *comboCountRule) Apply(account eval.Assets, bestRules []ComboRule) (float64, []string) {
+	var total float64
+	var lines []string
+	for _, combo := range bestRules {
+		if !hasMaxConstCombo([]ComboRule{combo}) {
+			continue
+		}
+		found := 0
+		for _, name := range r.cfg.Characters {
+			for _, req := range combo.RequiredChars {
+				if req.Name == name {
+					if c, inAccount := account.Characters[name]; inAccount && c >= r.cfg.MinConst && c <= r.cfg.MaxConst {
+						found++
+					}
+					break
+				}
+			}
+		}
+		for _, n := range r.thresholds {
+			if found >= n {
+				bonus := r.cfg.CountBonuses[n]
+				total += bonus
+				lines = append(lines, fmt.Sprintf("  - 组合 [%.30s...] 包含%d种特定%d-%d命角色，附加价值 +%.0f", combo.Name, n, r.cfg.MinConst, r.cfg.MaxConst, bonus))
+				break
+			}
+		}
+	}
+	return total, lines
+}
+
+// hotCharacterRule 对处于指定命座区间的热门角色附加价值，命中豁免地区满命组合的角色除外
+type hotCharacterRule struct {
+	cfg        SpecialRuleConfig
+	characters map[string]CharacterInfo // 用于判断组合角色所属地区
+}
+
+func newHotCharacterRule(cfg SpecialRuleConfig, r ValuationRules) (SpecialRule, error) {
+	return &hotCharacterRule{cfg: cfg, characters: r.Characters}, nil
+}
+
+func (r *hotCharacterRule) Name() string { return r.cfg.Name }
+
+func (r *hotCharacterRule) Apply(account eval.Assets, bestRules []ComboRule) (float64, []string) {
+	if r.cfg.RequireMaxConstCombo && !hasMaxConstCombo(bestRules) {
+		return 0, nil
+	}
+
+	// 收集命中了豁免地区满命溢价组合的角色
+	exemptChars := make(map[string]string)
+	for _, combo := range bestRules {
+		// 豁免地区组合的特征：组合内有该地区角色的6命要求
+		region := maxConstRegion(combo, r.cfg.ExemptRegions, r.characters)
+		if region == "" {
+			continue
+		}
+		for _, req := range combo.RequiredChars {
+			exemptChars[req.Name] = region
+		}
+	}
+
+	var total float64
+	var lines []string
+	for _, hotChar := range r.cfg.Characters {
+		constellation, ok := account.Characters[hotChar]
+		if !ok || constellation < r.cfg.MinConst || constellation > r.cfg.MaxConst {
+			continue
+		}
+		if region, exempt := exemptChars[hotChar]; exempt {
+			lines = append(lines, fmt.Sprintf("  - 热门%d命角色 [%s] 已命中%s满命溢价组合，不再额外+%.0f", constellation, hotChar, region, r.cfg.Bonus))
+			continue
+		}
+		total += r.cfg.Bonus
+		lines = append(lines, fmt.Sprintf("  - 命中热门%d命角色 [%s]，附加价值 +%.0f", constellation, hotChar, r.cfg.Bonus))
+	}
+	return total, lines
+}
+
+// exprSpecialRule 由规则语句描述的特殊规则
+// 附加价值动作在此生效，武器乘数动作在计算基础价值时生效
+type exprSpecialRule struct {
+	rule *ExprRule
+}
+
+func newExprSpecialRule(cfg SpecialRuleConfig, r ValuationRules) (SpecialRule, error) {
+	rule, err := CompileRule(cfg.Name, cfg.Expr, r)
+	if err != nil {
+		return nil, err
+	}
+	return &exprSpecialRule{rule: rule}, nil
+}
+
+func (r *exprSpecialRule) Name() string { return r.rule.Name }
+
+func (r *exprSpecialRule) Apply(account eval.Assets, bestRules []ComboRule) (float64, []string) {
+	if r.rule.Action != ActionBonus || !r.rule.Matches(account) {
+		return 0, nil
+	}
+	return r.rule.Amount, []string{fmt.Sprintf("  - 规则 [%s] 条件成立，附加价值 %+.0f", r.rule.Name, r.rule.Amount)}
+}
+
+// maxConstRegion 返回组合中要求6命的角色所属的给定地区之一，未命中时返回空
+func maxConstRegion(combo ComboRule, regions []string, characters map[string]CharacterInfo) string {
+	for _, req := range combo.RequiredChars {
+		if req.MinConst != 6 {
+			continue
+		}
+		region := characters[req.Name].Region
+		for _, r := range regions {
+			if region != "" && region == r {
+				return region
+			}
+		}
+	}
+	return ""
+}
+
+// applySpecialRules 按规则集中配置的顺序应用特殊规则增益
+func applySpecialRules(account eval.Assets, bestRules []ComboRule, set *ruleSet) (float64, string) {
+	var totalBonus float64
+	var sb strings.Builder
+
+	for _, rule := range set.specials {
+		bonus, lines := rule.Apply(account, bestRules)
+		totalBonus += bonus
+		for _, line := range lines {
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+	}
+
+	if sb.Len() == 0 {
+		return 0, "未触发任何特殊角色规则。\n"
+	}
+
+	return totalBonus, sb.String()
+}
@@ -0,0 +1,93 @@
+package newrule
+
+import (
+	"fmt"
+	"sort"
+)
+
+// TierMode 分档表的取值方式
+type TierMode int
+
+const (
+	// TierStep 按所在档位取值 (默认)
+	TierStep TierMode = iota
+	// TierInterpolate 在相邻档位的起点之间线性插值，消除档位边界的跳变
+	TierInterpolate
+)
+
+// CharCountTier 角色数量乘数档位
+type CharCountTier struct {
+	MinCount int     `json:"minCount"`
+	MaxCount int     `json:"maxCount"`
+	Factor   float64 `json:"factor"`
+
+	Provenance Provenance `json:"provenance,omitzero"`
+}
+
+// ResourceTier 资源单价档位
+type ResourceTier struct {
+	MinFates int     `json:"minFates"`
+	Price    float64 `json:"price"`
+
+	Provenance Provenance `json:"provenance,omitzero"`
+}
+
+// tierPoint 是插值使用的锚点，X 为档位起点，Y 为该档位的取值
+type tierPoint struct {
+	X float64
+	Y float64
+}
+
+// interpolateTiers 在锚点之间线性插值，超出锚点范围时取端点值
+// 返回插值结果以及推导该结果所用的左右两个锚点
+func interpolateTiers(points []tierPoint, x float64) (float64, tierPoint, tierPoint) {
+	sorted := make([]tierPoint, len(points))
+	copy(sorted, points)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].X < sorted[j].X })
+
+	if len(sorted) == 0 {
+		return 0, tierPoint{}, tierPoint{}
+	}
+	if x <= sorted[0].X {
+		return sorted[0].Y, sorted[0], sorted[0]
+	}
+	for i := 1; i < len(sorted); i++ {
+		lo, hi := sorted[i-1], sorted[i]
+		if x < hi.X {
+			y := lo.Y + (hi.Y-lo.Y)*(x-lo.X)/(hi.X-lo.X)
+			return y, lo, hi
+		}
+	}
+	last := sorted[len(sorted)-1]
+	return last.Y, last, last
+}
+
+// describeNeighbours 描述插值所用的相邻档位
+func describeNeighbours(lo, hi tierPoint, unit string) string {
+	if lo == hi {
+		return fmt.Sprintf("取端点档位 %.0f%s: %.2f", lo.X, unit, lo.Y)
+	}
+	return fmt.Sprintf("介于 %.0f%s: %.2f 与 %.0f%s: %.2f 之间", lo.X, unit, lo.Y, hi.X, unit, hi.Y)
+}
+
+// neighbourCharCountTiers 返回插值所用锚点对应的角色数量档位
+func neighbourCharCountTiers(set *ruleSet, lo, hi tierPoint) []ruleRef {
+	var refs []ruleRef
+	for _, tier := range set.CharCountMultiplierTiers {
+		if x := float64(tier.MinCount); x == lo.X || (x == hi.X && hi != lo) {
+			refs = append(refs, tier.ref())
+		}
+	}
+	return refs
+}
+
+// neighbourResourceTiers 返回插值所用锚点对应的资源档位，(0抽, 0) 起点不是档位
+func neighbourResourceTiers(set *ruleSet, lo, hi tierPoint) []ruleRef {
+	var refs []ruleRef
+	for _, tier := range set.ResourceValueTiers {
+		if x := float64(tier.MinFates); x == lo.X || (x == hi.X && hi != lo) {
+			refs = append(refs, tier.ref())
+		}
+	}
+	return refs
+}
@@ -0,0 +1,27 @@
+package newrule
+
+import (
+	"math"
+	"testing"
+)
+
+func TestInterpolateTiers(t *testing.T) {
+	points := []tierPoint{{X: 40, Y: 1.2}, {X: 21, Y: 1.0}, {X: 46, Y: 1.4}}
+	cases := []struct {
+		x    float64
+		want float64
+	}{
+		{10, 1.0},
+		{21, 1.0},
+		{39, 1.0 + 0.2*18/19},
+		{40, 1.2},
+		{43, 1.3},
+		{100, 1.4},
+	}
+	for _, c := range cases {
+		got, _, _ := interpolateTiers(points, c.x)
+		if math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("interpolateTiers(%v) = %.4f, want %.4f", c.x, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package newrule
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+// BuiltinVersion 是内置规则的版本号
+const BuiltinVersion = "内置"
+
+// RuleSetVersion 是自某一时间起生效的一版估值规则
+type RuleSetVersion struct {
+	Version       string         `json:"version"`                // 版本号，如 "2025-10"
+	GameVersion   string         `json:"gameVersion,omitempty"`  // 对应的游戏版本，如 "6.1"
+	EffectiveFrom time.Time      `json:"effectiveFrom,omitzero"` // 生效时间，零值表示早于所有时间
+	Rules         ValuationRules `json:"rules"`
+
+	compiled *ruleSet // 登记时编译的规则
+}
+
+// RuleRegistry 登记多版估值规则，按时间、版本号或游戏版本选取
+type RuleRegistry struct {
+	mu         sync.RWMutex
+	versions   []RuleSetVersion // 按生效时间升序
+	promotions []Promotion      // 叠加在各版本之上的促销
+}
+
+// NewRuleRegistry 创建空的规则登记表
+func NewRuleRegistry() *RuleRegistry {
+	return &RuleRegistry{}
+}
+
+// DefaultRegistry 是默认的规则登记表，初始只含内置规则 (版本 BuiltinVersion，始终生效)
+var DefaultRegistry = NewRuleRegistry()
+
+// 内置规则在 init 中登记: 阶段与特殊规则在 pipeline.go、special.go 的 init 中注册，先于本文件执行
+func init() {
+	if err := DefaultRegistry.Register(RuleSetVersion{Version: BuiltinVersion, Rules: rules}); err != nil {
+		panic(err)
+	}
+}
+
+// Register 登记一版规则，版本号与生效时间都不能与已登记的重复
+// 规则需通过 ValidateRules 校验，登记时深拷贝后编译其中的表达式、特殊规则与估值阶段，之后修改传入的规则不影响已登记的版本
+func (r *RuleRegistry) Register(v RuleSetVersion) error {
+	if v.Version == "" {
+		return errors.New("规则版本缺少版本号")
+	}
+	v.Rules = cloneRules(v.Rules)
+	compiled, err := compileRuleSet(v.Rules, v.Version)
+	if err != nil {
+		return err
+	}
+	v.compiled = compiled
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.versions {
+		if existing.Version == v.Version {
+			return fmt.Errorf("规则版本 %s 已登记", v.Version)
+		}
+		if existing.EffectiveFrom.Equal(v.EffectiveFrom) {
+			return fmt.Errorf("规则版本 %s 与 %s 的生效时间相同", v.Version, existing.Version)
+		}
+	}
+	i := sort.Search(len(r.versions), func(i int) bool { return r.versions[i].EffectiveFrom.After(v.EffectiveFrom) })
+	r.versions = append(r.versions, RuleSetVersion{})
+	copy(r.versions[i+1:], r.versions[i:])
+	r.versions[i] = v
+	return nil
+}
+
+// cloneRules 深拷贝规则中的价格表、组合、促销等引用类型的字段
+func cloneRules(r ValuationRules) ValuationRules {
+	r.Characters = maps.Clone(r.Characters)
+	r.Weapons = maps.Clone(r.Weapons)
+	r.Combos = slices.Clone(r.Combos)
+	for i := range r.Combos {
+		c := &r.Combos[i]
+		c.RequiredChars = slices.Clone(c.RequiredChars)
+		c.RequiredGroups = slices.Clone(c.RequiredGroups)
+		for j := range c.RequiredGroups {
+			c.RequiredGroups[j].Options = slices.Clone(c.RequiredGroups[j].Options)
+		}
+		c.RequiredAttributes = slices.Clone(c.RequiredAttributes)
+		c.RequiredWeapons = slices.Clone(c.RequiredWeapons)
+		c.WeaponMultipliers = maps.Clone(c.WeaponMultipliers)
+		c.ShareableChars = slices.Clone(c.ShareableChars)
+		c.MatchedAlternatives = slices.Clone(c.MatchedAlternatives)
+		c.SharedChars = slices.Clone(c.SharedChars)
+	}
+	r.CharCountMultiplierTiers = slices.Clone(r.CharCountMultiplierTiers)
+	r.ResourceValueTiers = slices.Clone(r.ResourceValueTiers)
+	r.HotC6CharsT1 = slices.Clone(r.HotC6CharsT1)
+	r.HotC6CharsT2 = slices.Clone(r.HotC6CharsT2)
+	r.SpecialC2C5Chars = slices.Clone(r.SpecialC2C5Chars)
+	r.HotC6T1ExemptRegions = slices.Clone(r.HotC6T1ExemptRegions)
+	r.SpecialRules = slices.Clone(r.SpecialRules)
+	for i := range r.SpecialRules {
+		cfg := &r.SpecialRules[i]
+		cfg.Characters = slices.Clone(cfg.Characters)
+		cfg.CountBonuses = maps.Clone(cfg.CountBonuses)
+		cfg.ExemptRegions = slices.Clone(cfg.ExemptRegions)
+	}
+	r.Pipeline = slices.Clone(r.Pipeline)
+	r.Promotions = slices.Clone(r.Promotions)
+	for i := range r.Promotions {
+		r.Promotions[i].Names = slices.Clone(r.Promotions[i].Names)
+	}
+	r.LimitedFiveStarChars = slices.Clone(r.LimitedFiveStarChars)
+	r.StandardFiveStarChars = slices.Clone(r.StandardFiveStarChars)
+	r.FourStarChars = slices.Clone(r.FourStarChars)
+	return r
+}
+
+// At 返回时间 t 时生效的规则，即生效时间不晚于 t 的最新一版
+func (r *RuleRegistry) At(t time.Time) (RuleSetVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := sort.Search(len(r.versions), func(i int) bool { return r.versions[i].EffectiveFrom.After(t) })
+	if i == 0 {
+		return RuleSetVersion{}, fmt.Errorf("%s 时没有生效的规则版本", t.Format(time.DateOnly))
+	}
+	return r.versions[i-1], nil
+}
+
+// Lookup 按版本号查找规则，找不到时按游戏版本查找该游戏版本下最新的一版
+func (r *RuleRegistry) Lookup(version string) (RuleSetVersion, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, v := range r.versions {
+		if v.Version == version {
+			return v, nil
+		}
+	}
+	for i := len(r.versions) - 1; i >= 0; i-- {
+		if r.versions[i].GameVersion == version {
+			return r.versions[i], nil
+		}
+	}
+	return RuleSetVersion{}, fmt.Errorf("未登记规则版本 %s", version)
+}
+
+// AddPromotions 登记独立于规则版本的促销，按 AsOf 或 WithRuleVersion 选取版本估值时叠加在所选版本之上
+// 促销名称不能与已登记的重复，适用的条目须出现在任一已登记的版本中
+func (r *RuleRegistry) AddPromotions(promos ...Promotion) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rulesets := make([]ValuationRules, 0, len(r.versions))
+	for _, v := range r.versions {
+		rulesets = append(rulesets, v.Rules)
+	}
+	var errs []error
+	for i, p := range promos {
+		errs = append(errs, validatePromotion(p, rulesets...)...)
+		if slices.ContainsFunc(r.promotions, func(q Promotion) bool { return q.Name == p.Name }) ||
+			slices.ContainsFunc(promos[:i], func(q Promotion) bool { return q.Name == p.Name }) {
+			errs = append(errs, fmt.Errorf("促销 %q 已登记", p.Name))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	r.promotions = append(r.promotions, promos...)
+	return nil
+}
+
+// Promotions 返回通过 AddPromotions 登记的促销
+func (r *RuleRegistry) Promotions() []Promotion {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.promotions)
+}
+
+// withPromotions 返回叠加了登记表中促销的规则，有促销在时间 at 生效而版本的估值流程中没有 promo 阶段时报错
+func (r *RuleRegistry) withPromotions(v RuleSetVersion, at time.Time) (*ruleSet, error) {
+	promos := r.Promotions()
+	if len(promos) == 0 {
+		return v.compiled, nil
+	}
+	if !hasPromoStage(v.compiled.Pipeline) && slices.ContainsFunc(promos, func(p Promotion) bool { return p.Active(at) }) {
+		return nil, fmt.Errorf("规则版本 %s 的估值流程中没有 promo 阶段，无法应用促销", v.Version)
+	}
+	layered := *v.compiled
+	layered.Promotions = slices.Concat(v.compiled.Promotions, promos)
+	return &layered, nil
+}
+
+// Versions 按生效时间升序返回全部已登记的版本
+func (r *RuleRegistry) Versions() []RuleSetVersion {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]RuleSetVersion{}, r.versions...)
+}
+
+// ruleSet 是一次估值所用的规则、版本号及编译结果，随估值状态传递，估值期间只读
+type ruleSet struct {
+	ValuationRules
+	version string
+
+	conditions map[string]*ExprRule // 组合条件，按条件表达式索引
+	specials   []SpecialRule        // 按配置顺序构造的特殊规则
+	stages     []Stage              // 估值流程各阶段
+}
+
+// compileRuleSet 校验规则并编译其中的组合条件、特殊规则与估值阶段，规则有误时返回全部校验错误
+func compileRuleSet(r ValuationRules, version string) (*ruleSet, error) {
+	if errs := ValidateRules(r); len(errs) > 0 {
+		return nil, fmt.Errorf("规则版本 %s 校验失败: %w", version, errors.Join(errs...))
+	}
+	set := &ruleSet{ValuationRules: r, version: version, conditions: make(map[string]*ExprRule)}
+	for _, combo := range r.Combos {
+		if combo.Condition == "" {
+			continue
+		}
+		cond, err := CompileCondition(combo.Name, combo.Condition, r)
+		if err != nil {
+			return nil, err
+		}
+		set.conditions[combo.Condition] = cond
+	}
+	for _, cfg := range r.SpecialRules {
+		rule, err := buildSpecialRule(cfg, r)
+		if err != nil {
+			return nil, err
+		}
+		set.specials = append(set.specials, rule)
+	}
+	for _, cfg := range r.Pipeline {
+		stage, err := buildStage(cfg)
+		if err != nil {
+			return nil, err
+		}
+		set.stages = append(set.stages, stage)
+	}
+	return set, nil
+}
+
+// EvaluateOption 指定单次估值所用的规则版本
+type EvaluateOption func(*evaluateConfig)
+
+type evaluateConfig struct {
+	registry *RuleRegistry
+	asOf     *time.Time
+	version  string
+}
+
+// AsOf 按时间 t 时生效的规则与促销估值
+func AsOf(t time.Time) EvaluateOption {
+	return func(c *evaluateConfig) { c.asOf = &t }
+}
+
+// WithRuleVersion 按指定的版本号或游戏版本估值，优先于 AsOf
+func WithRuleVersion(version string) EvaluateOption {
+	return func(c *evaluateConfig) { c.version = version }
+}
+
+// WithRegistry 从指定的登记表中选取规则，默认为 DefaultRegistry
+func WithRegistry(r *RuleRegistry) EvaluateOption {
+	return func(c *evaluateConfig) { c.registry = r }
+}
+
+// EvaluateWith 按选项选取的规则版本估值，结果中的 RuleVersion 记录所用版本
+// 估值时间取 AsOf 指定的时间，否则为当前时间，生效的促销随之变化；
+// 不指定 AsOf 或 WithRuleVersion 时与 Evaluate 相同，使用 n 的规则按当前时间估值
+func (n *NewRule) EvaluateWith(account eval.Assets, opts ...EvaluateOption) (ValuationReport, error) {
+	cfg := evaluateConfig{registry: DefaultRegistry}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.version == "" && cfg.asOf == nil {
+		return n.Evaluate(account), nil
+	}
+	at := time.Now()
+	if cfg.asOf != nil {
+		at = *cfg.asOf
+	}
+
+	var selected RuleSetVersion
+	var err error
+	if cfg.version != "" {
+		selected, err = cfg.registry.Lookup(cfg.version)
+	} else {
+		selected, err = cfg.registry.At(*cfg.asOf)
+	}
+	if err != nil {
+		return ValuationReport{}, err
+	}
+
+	set, err := cfg.registry.withPromotions(selected, at)
+	if err != nil {
+		return ValuationReport{}, err
+	}
+	versioned := &NewRule{rules: set}
+	return versioned.evaluate(account, at), nil
+}
@@ -0,0 +1,162 @@
+package newrule
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+// repricedRules 返回将玛薇卡6命价格改为 price 的规则副本
+func repricedRules(price float64) ValuationRules {
+	r := rules
+	r.Characters = maps.Clone(rules.Characters)
+	info := r.Characters["玛薇卡"]
+	info.Prices[6] = price
+	r.Characters["玛薇卡"] = info
+	return r
+}
+
+func TestRuleRegistry(t *testing.T) {
+	oct := time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	dec := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	registry := NewRuleRegistry()
+	// 登记顺序与生效时间无关
+	for _, v := range []RuleSetVersion{
+		{Version: "2025-12", GameVersion: "6.2", EffectiveFrom: dec, Rules: repricedRules(700)},
+		{Version: "2025-10", GameVersion: "6.1", EffectiveFrom: oct, Rules: repricedRules(600)},
+	} {
+		if err := registry.Register(v); err != nil {
+			t.Fatalf("Register(%s): %v", v.Version, err)
+		}
+	}
+
+	if _, err := registry.At(oct.Add(-time.Hour)); err == nil {
+		t.Error("expected error before the first version")
+	}
+	for _, c := range []struct {
+		at   time.Time
+		want string
+	}{{oct, "2025-10"}, {dec.Add(-time.Second), "2025-10"}, {dec, "2025-12"}, {dec.AddDate(1, 0, 0), "2025-12"}} {
+		if v, err := registry.At(c.at); err != nil || v.Version != c.want {
+			t.Errorf("At(%s) = %q, %v, want %q", c.at, v.Version, err, c.want)
+		}
+	}
+	if v, err := registry.Lookup("6.2"); err != nil || v.Version != "2025-12" {
+		t.Errorf("Lookup(6.2) = %q, %v", v.Version, err)
+	}
+	if _, err := registry.Lookup("5.0"); err == nil {
+		t.Error("expected error for unknown version")
+	}
+
+	for _, c := range []struct {
+		v    RuleSetVersion
+		want string
+	}{
+		{RuleSetVersion{Rules: rules}, "缺少版本号"},
+		{RuleSetVersion{Version: "2025-10", Rules: rules}, "已登记"},
+		{RuleSetVersion{Version: "x", EffectiveFrom: oct, Rules: rules}, "生效时间相同"},
+		{RuleSetVersion{Version: "y", Rules: ValuationRules{Pipeline: []StageConfig{{Type: "unknown"}}}}, "校验失败"},
+	} {
+		if err := registry.Register(c.v); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("Register(%q) error = %v, want %q", c.v.Version, err, c.want)
+		}
+	}
+	if got := len(registry.Versions()); got != 2 {
+		t.Errorf("len(Versions()) = %d, want 2", got)
+	}
+}
+
+func TestRuleRegistry_RegisterCopiesRules(t *testing.T) {
+	r := repricedRules(600)
+	r.Combos = slices.Clone(rules.Combos)
+	r.Combos[0].RequiredChars = slices.Clone(rules.Combos[0].RequiredChars)
+	want := r.Combos[0]
+	wantMinConst := want.RequiredChars[0].MinConst
+	registry := NewRuleRegistry()
+	if err := registry.Register(RuleSetVersion{Version: "2025-10", Rules: r}); err != nil {
+		t.Fatal(err)
+	}
+	// 登记后修改传入的规则不影响已登记的版本
+	info := r.Characters["玛薇卡"]
+	info.Prices[6] = 9999
+	r.Characters["玛薇卡"] = info
+	r.Combos[0].Value = 9999
+	r.Combos[0].RequiredChars[0].MinConst = 0
+
+	v, err := registry.Lookup("2025-10")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := v.Rules.Characters["玛薇卡"].Prices[6]; got != 600 {
+		t.Errorf("registered price = %.2f, want 600", got)
+	}
+	if got := v.Rules.Combos[0]; got.Value != want.Value || got.RequiredChars[0].MinConst != wantMinConst {
+		t.Errorf("registered combo changed: %+v", got)
+	}
+}
+
+func TestEvaluateWith_AsOf(t *testing.T) {
+	registry := NewRuleRegistry()
+	registry.Register(RuleSetVersion{Version: BuiltinVersion, Rules: rules})
+	registry.Register(RuleSetVersion{Version: "2025-10", GameVersion: "6.1",
+		EffectiveFrom: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), Rules: repricedRules(600)})
+
+	account := eval.Assets{Characters: map[string]int{"玛薇卡": 6}}
+	n := New()
+	before, err := n.EvaluateWith(account, WithRegistry(registry), AsOf(time.Date(2025, 9, 30, 0, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := n.EvaluateWith(account, WithRegistry(registry), AsOf(time.Date(2025, 10, 2, 0, 0, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.RuleVersion != BuiltinVersion || after.RuleVersion != "2025-10" {
+		t.Errorf("RuleVersion = %q / %q", before.RuleVersion, after.RuleVersion)
+	}
+	// 无专武的6命角色按8折计入
+	if diff := after.BaseValue - before.BaseValue; diff != (600-rules.Characters["玛薇卡"].Prices[6])*0.8 {
+		t.Errorf("base value difference = %.2f", diff)
+	}
+	if byGame, err := n.EvaluateWith(account, WithRegistry(registry), WithRuleVersion("6.1")); err != nil || byGame.FinalTotal != after.FinalTotal {
+		t.Errorf("WithRuleVersion(6.1) = %.2f, %v, want %.2f", byGame.FinalTotal, err, after.FinalTotal)
+	}
+
+	// 按版本估值不影响使用内置规则的估值
+	if current := n.Evaluate(account); current.RuleVersion != BuiltinVersion || current.FinalTotal != before.FinalTotal {
+		t.Errorf("Evaluate after EvaluateWith = %q %.2f", current.RuleVersion, current.FinalTotal)
+	}
+	if got := n.CalculateValuation(account).RuleVersion; got != BuiltinVersion {
+		t.Errorf("CalculateValuation RuleVersion = %q", got)
+	}
+}
+
+func TestEvaluateWith_Concurrent(t *testing.T) {
+	registry := NewRuleRegistry()
+	registry.Register(RuleSetVersion{Version: BuiltinVersion, Rules: rules})
+	registry.Register(RuleSetVersion{Version: "2025-10", EffectiveFrom: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), Rules: repricedRules(600)})
+
+	account := eval.Assets{Characters: map[string]int{"玛薇卡": 6, "茜特菈莉": 6}}
+	n := New()
+	want := n.Evaluate(account).FinalTotal
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := n.EvaluateWith(account, WithRegistry(registry), AsOf(time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC))); err != nil {
+				t.Error(err)
+			}
+			if got := n.Evaluate(account).FinalTotal; got != want {
+				t.Errorf("Evaluate = %.2f, want %.2f", got, want)
+			}
+			n.QueryCombos(ComboQuery{Characters: []string{"玛薇卡"}})
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,101 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+// goodDatabase 是 GOOD (Genshin Open Object Description) 格式中估值用到的部分
+type goodDatabase struct {
+	Format     string          `json:"format"`
+	Version    int             `json:"version"`
+	Source     string          `json:"source"`
+	Characters []goodCharacter `json:"characters"`
+	Weapons    []goodWeapon    `json:"weapons"`
+	Materials  map[string]int  `json:"materials"`
+}
+
+type goodCharacter struct {
+	Key           string `json:"key"`
+	Constellation int    `json:"constellation"`
+}
+
+type goodWeapon struct {
+	Key        string `json:"key"`
+	Refinement int    `json:"refinement"`
+	Location   string `json:"location"`
+}
+
+// GOOD 格式中计入资源的材料键
+const (
+	goodIntertwinedFate = "IntertwinedFate"
+	goodPrimogem        = "Primogem"
+)
+
+// ImportGOOD 将 GOOD 格式的 JSON 导出转换为账号资产
+// 旅行者不参与估值，直接忽略；价格表之外的常见武器跳过并计入 Report.Unpriced，只有无法识别的键计入未映射条目；
+// 同名武器有多把时取最高精炼；材料中只读取纠缠之缘与原石，其余材料不参与估值也不计入未映射条目
+func ImportGOOD(data []byte) (eval.Assets, *Report, error) {
+	var db goodDatabase
+	if err := json.Unmarshal(data, &db); err != nil {
+		return eval.Assets{}, nil, fmt.Errorf("解析 GOOD 数据失败: %w", err)
+	}
+	if db.Format != "GOOD" {
+		return eval.Assets{}, nil, fmt.Errorf("不是 GOOD 格式的数据 (format=%q)", db.Format)
+	}
+
+	report := &Report{Source: fmt.Sprintf("GOOD v%d", db.Version)}
+	if db.Source != "" {
+		report.Source += " (" + db.Source + ")"
+	}
+	account := eval.Assets{
+		Characters: make(map[string]int),
+		Weapons:    make(map[string]int),
+	}
+
+	for _, ch := range db.Characters {
+		if strings.HasPrefix(ch.Key, "Traveler") {
+			continue
+		}
+		name, ok := CharacterNames[ch.Key]
+		if !ok {
+			report.addUnmapped("角色", ch.Key)
+			continue
+		}
+		if ch.Constellation < 0 || ch.Constellation > 6 {
+			report.warnf("角色 %s 的命座 %d 超出 0-6，已按边界截断", name, ch.Constellation)
+			ch.Constellation = min(max(ch.Constellation, 0), 6)
+		}
+		account.Characters[name] = ch.Constellation
+	}
+
+	for _, w := range db.Weapons {
+		name, ok := WeaponNames[w.Key]
+		if !ok {
+			if unpricedWeaponKeys[w.Key] {
+				report.Unpriced++
+			} else {
+				report.addUnmapped("武器", w.Key)
+			}
+			continue
+		}
+		if w.Refinement < 1 || w.Refinement > 5 {
+			report.warnf("武器 %s 的精炼 %d 超出 1-5，已按边界截断", name, w.Refinement)
+			w.Refinement = min(max(w.Refinement, 1), 5)
+		}
+		if prev, dup := account.Weapons[name]; dup {
+			report.warnf("武器 %s 有多把，取最高精炼", name)
+			w.Refinement = max(prev, w.Refinement)
+		}
+		account.Weapons[name] = w.Refinement
+	}
+
+	account.JiuChanZhiYuan = db.Materials[goodIntertwinedFate]
+	account.YuanShi = db.Materials[goodPrimogem]
+
+	report.sortUnmapped()
+	return account, report, nil
+}
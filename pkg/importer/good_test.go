@@ -0,0 +1,90 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleGOOD = `{
+  "format": "GOOD",
+  "version": 2,
+  "source": "Inventory_Kamera",
+  "characters": [
+    {"key": "Mavuika", "level": 90, "constellation": 6, "ascension": 6},
+    {"key": "Citlali", "level": 90, "constellation": 2, "ascension": 6},
+    {"key": "Bennett", "level": 80, "constellation": 6, "ascension": 6},
+    {"key": "TravelerAnemo", "level": 90, "constellation": 6, "ascension": 6},
+    {"key": "SomeNewCharacter", "level": 1, "constellation": 0, "ascension": 0}
+  ],
+  "weapons": [
+    {"key": "AThousandBlazingSuns", "level": 90, "ascension": 6, "refinement": 1, "location": "Mavuika", "lock": true},
+    {"key": "AThousandBlazingSuns", "level": 1, "ascension": 0, "refinement": 2, "location": "", "lock": false},
+    {"key": "FavoniusSword", "level": 90, "ascension": 6, "refinement": 5, "location": "Bennett", "lock": true},
+    {"key": "FavoniusSword", "level": 1, "ascension": 0, "refinement": 1, "location": "", "lock": false},
+    {"key": "SomeNewWeapon", "level": 1, "ascension": 0, "refinement": 1, "location": "", "lock": false}
+  ],
+  "materials": {"IntertwinedFate": 120, "Primogem": 32000, "MoraIntro": 5}
+}`
+
+func TestImportGOOD(t *testing.T) {
+	account, report, err := ImportGOOD([]byte(sampleGOOD))
+	if err != nil {
+		t.Fatalf("ImportGOOD: %v", err)
+	}
+
+	wantChars := map[string]int{"玛薇卡": 6, "茜特菈莉": 2, "班尼特": 6}
+	if len(account.Characters) != len(wantChars) {
+		t.Errorf("Characters = %v, want %v", account.Characters, wantChars)
+	}
+	for name, c := range wantChars {
+		if got, ok := account.Characters[name]; !ok || got != c {
+			t.Errorf("Characters[%s] = %d (%v), want %d", name, got, ok, c)
+		}
+	}
+	if got := account.Weapons["焚曜千阳"]; got != 2 {
+		t.Errorf("Weapons[焚曜千阳] = %d, want 2 (取最高精炼)", got)
+	}
+	if account.JiuChanZhiYuan != 120 || account.YuanShi != 32000 {
+		t.Errorf("JiuChanZhiYuan = %d, YuanShi = %d, want 120, 32000", account.JiuChanZhiYuan, account.YuanShi)
+	}
+
+	want := []UnmappedEntry{
+		{Kind: "武器", Key: "SomeNewWeapon", Count: 1},
+		{Kind: "角色", Key: "SomeNewCharacter", Count: 1},
+	}
+	if len(report.Unmapped) != len(want) {
+		t.Fatalf("Unmapped = %v, want %v", report.Unmapped, want)
+	}
+	for i := range want {
+		if report.Unmapped[i] != want[i] {
+			t.Errorf("Unmapped[%d] = %v, want %v", i, report.Unmapped[i], want[i])
+		}
+	}
+	// 西风剑在价格表之外，跳过但不算作未映射
+	if report.Unpriced != 2 {
+		t.Errorf("Unpriced = %d, want 2", report.Unpriced)
+	}
+	if len(report.Warnings) != 1 || !strings.Contains(report.Warnings[0], "焚曜千阳") {
+		t.Errorf("Warnings = %v, want 1 warning about 焚曜千阳", report.Warnings)
+	}
+	if s := report.String(); !strings.Contains(s, "Inventory_Kamera") || !strings.Contains(s, "SomeNewWeapon") || strings.Contains(s, "FavoniusSword") {
+		t.Errorf("report.String() = %q", s)
+	}
+}
+
+func TestImportGOOD_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"非 JSON", "not json"},
+		{"格式不符", `{"format": "EXCEL", "version": 1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ImportGOOD([]byte(tt.data)); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
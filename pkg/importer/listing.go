@@ -0,0 +1,569 @@
+package importer
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+	"github.com/sdojjy/genshin-value-rule/pkg/eval/newrule"
+)
+
+// 文本条目的解读类别
+const (
+	ListingCharacter = "角色"
+	ListingWeapon    = "武器"
+	ListingPrimogem  = "原石"
+	ListingFate      = "纠缠之缘"
+	ListingIgnored   = "忽略"
+)
+
+// ListingAliases 是卖号文案中常见的角色、武器简称
+var ListingAliases = map[string]string{
+	// 角色
+	"万叶": "枫原万叶",
+	"雷神": "雷电将军",
+	"雷电": "雷电将军",
+	"草神": "纳西妲",
+	"水神": "芙宁娜",
+	"芙芙": "芙宁娜",
+	"火神": "玛薇卡",
+	"风神": "温迪",
+	"岩神": "钟离",
+	"帝君": "钟离",
+	"心海": "珊瑚宫心海",
+	"绫人": "神里绫人",
+	"一斗": "荒泷一斗",
+	"八重": "八重神子",
+	"神子": "八重神子",
+	"散兵": "流浪者",
+	"公子": "达达利亚",
+	"海森": "艾尔海森",
+	"水龙": "那维莱特",
+	"那维": "那维莱特",
+	"仆人": "阿蕾奇诺",
+	"莱欧": "莱欧斯利",
+	"平藏": "鹿野院平藏",
+	"瑞希": "梦见月瑞希",
+
+	// 武器
+	"焚曜":  "焚曜千阳",
+	"护摩":  "护摩之杖",
+	"和璞":  "和璞鸢",
+	"薙草":  "薙草之稻光",
+	"草薙":  "薙草之稻光",
+	"神乐":  "神乐之真意",
+	"苍古":  "苍古自由之誓",
+	"终末":  "终末嗟叹之诗",
+	"阿莫斯": "阿莫斯之弓",
+	"飞雷":  "飞雷之弦振",
+	"冬极":  "冬极白星",
+	"松籁":  "松籁响起之时",
+	"赤角":  "赤角石溃杵",
+	"波乱":  "波乱月白经津",
+	"圣显":  "圣显之钥",
+	"裁叶":  "裁叶萃光",
+	"千夜":  "千夜浮梦",
+	"图莱":  "图莱杜拉的回忆",
+	"赤沙":  "赤沙之杖",
+	"静水":  "静水流涌之辉",
+	"万世":  "万世流涌大典",
+	"赤月":  "赤月之形",
+	"白雨":  "白雨心弦",
+	"有乐":  "有乐御簾切",
+	"冲浪":  "冲浪时光",
+	"柔灯":  "柔灯挽歌",
+	"岩峰":  "岩峰巡歌",
+	"山王":  "山王长牙",
+	"星鹫":  "星鹫赤羽",
+	"祭星":  "祭星者之望",
+	"溢彩":  "溢彩心念",
+	"香韵":  "香韵奏者",
+	"碧落":  "碧落之珑",
+	"金流":  "金流监督",
+	"贯虹":  "贯虹之槊",
+	"四风":  "四风原典",
+	"不灭":  "不灭月华",
+}
+
+// 资源关键字
+var listingResources = map[string]string{
+	"原石":   ListingPrimogem,
+	"纠缠之缘": ListingFate,
+	"纠缠":   ListingFate,
+	"粉球":   ListingFate,
+}
+
+// listingWord 是文本中可识别的一个词: 角色/武器的全名或简称，或资源关键字
+type listingWord struct {
+	word string
+	name string
+	kind string
+}
+
+// listingVocabulary 按词长降序排列，匹配时优先最长的词
+var listingVocabulary = buildListingVocabulary()
+
+func buildListingVocabulary() []listingWord {
+	kinds := make(map[string]string)
+	var words []listingWord
+	for _, name := range CharacterNames {
+		kinds[name] = ListingCharacter
+		words = append(words, listingWord{word: name, name: name, kind: ListingCharacter})
+	}
+	for _, name := range WeaponNames {
+		kinds[name] = ListingWeapon
+		words = append(words, listingWord{word: name, name: name, kind: ListingWeapon})
+	}
+	for alias, name := range ListingAliases {
+		if kind, ok := kinds[name]; ok {
+			words = append(words, listingWord{word: alias, name: name, kind: kind})
+		}
+	}
+	for word, kind := range listingResources {
+		words = append(words, listingWord{word: word, name: kind, kind: kind})
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if len(words[i].word) != len(words[j].word) {
+			return len(words[i].word) > len(words[j].word)
+		}
+		return words[i].word < words[j].word
+	})
+	return words
+}
+
+const (
+	listingLevel  = `(\d+|[零〇一二两三四五六])`
+	listingAmount = `(\d+(?:\.\d+)?(?:w|万|k|千)?|[零〇一二两三四五六七八九十百千万]+)`
+)
+
+// 各类词前后的修饰，如 "6玛薇卡"、"6+1玛薇卡" 或 "玛薇卡6+1" (6命及精1专武)、"玛薇卡满命"、"精5焚曜"、"原石3.2w"
+// 等级按整数匹配再检查范围，"10命" 不会被读作 "0命"
+var (
+	charPrefix     = regexp.MustCompile(`^(?:c?` + listingLevel + `命?|满命)(?:\+` + listingLevel + `)?$`)
+	charSuffix     = regexp.MustCompile(`^(?:` + listingLevel + `命|c` + listingLevel + `|满命)`)
+	charSuffixPlus = regexp.MustCompile(`^(?:c?` + listingLevel + `|满命)\+` + listingLevel)
+	weaponPrefix   = regexp.MustCompile(`^(?:(?:精|r)` + listingLevel + `|` + listingLevel + `精|满精)$`)
+	weaponSuffix   = regexp.MustCompile(`^(?:精` + listingLevel + `|r` + listingLevel + `|满精)`)
+	resourcePrefix = regexp.MustCompile(`^` + listingAmount + `(?:个|颗)?$`)
+	resourceSuffix = regexp.MustCompile(`^[:：=]? ?` + listingAmount + `(?:个|颗)?`)
+)
+
+// 分隔符
+var listingSeparators = regexp.MustCompile(`[\s,，、;；|/+。]+`)
+
+// "6+1玛薇卡"、"玛薇卡6+1" 中 "+" 两侧的命座与专武精炼，此处的 "+" 不作分隔
+// 写在角色名之后时 "+" 后须是单独的精炼数字，"纠缠8+6茜特菈莉" 仍按 "+" 分隔
+var (
+	listingConstBeforePlus       = regexp.MustCompile(`^(?:c?[\d零〇一二两三四五六七八九]命?|满命)$`)
+	listingRefineAfterPlus       = regexp.MustCompile(`^[\d一二三四五六七八九][^\d.]`)
+	listingSuffixConstBeforePlus = regexp.MustCompile(`(?:[^\d.c]c?[\d零〇一二两三四五六]|满命)$`)
+	listingRefineAlone           = regexp.MustCompile(`^[\d一二三四五六七八九](?:[\s,，、;；|/+。]|$)`)
+)
+
+// ListingItem 是文本中一个片段的解读结果
+type ListingItem struct {
+	Text  string // 原文片段
+	Kind  string // ListingCharacter 等
+	Name  string // 解读出的角色/武器名
+	Value int    // 命座、精炼或资源数量
+	Note  string
+}
+
+func (it ListingItem) String() string {
+	var desc string
+	switch it.Kind {
+	case ListingCharacter:
+		desc = fmt.Sprintf("角色 %s %d命", it.Name, it.Value)
+	case ListingWeapon:
+		desc = fmt.Sprintf("武器 %s 精%d", it.Name, it.Value)
+	case ListingPrimogem, ListingFate:
+		desc = fmt.Sprintf("%s %d", it.Kind, it.Value)
+	default:
+		desc = "忽略"
+	}
+	if it.Note != "" {
+		desc += " (" + it.Note + ")"
+	}
+	return fmt.Sprintf("%q → %s", it.Text, desc)
+}
+
+// ListingExplanation 说明文本中每个片段如何被解读
+type ListingExplanation struct {
+	Items    []ListingItem
+	Warnings []string
+}
+
+// Ignored 返回未能解读的片段
+func (e *ListingExplanation) Ignored() []string {
+	var ignored []string
+	for _, it := range e.Items {
+		if it.Kind == ListingIgnored {
+			ignored = append(ignored, it.Text)
+		}
+	}
+	return ignored
+}
+
+func (e *ListingExplanation) String() string {
+	var sb strings.Builder
+	for _, it := range e.Items {
+		sb.WriteString(it.String())
+		sb.WriteString("\n")
+	}
+	if ignored := e.Ignored(); len(ignored) > 0 {
+		fmt.Fprintf(&sb, "已忽略: %s\n", strings.Join(ignored, " "))
+	}
+	for _, w := range e.Warnings {
+		fmt.Fprintf(&sb, "提示: %s\n", w)
+	}
+	return sb.String()
+}
+
+// ParseListing 将卖号文案解析为账号资产，如 "6玛薇卡 精5焚曜 2茜特菈莉 0希诺宁 原石3.2w 纠缠120"
+// 支持阿拉伯数字与中文数字、"满命"/"满精"、"c6"/"r5"、"6+1" (6命及精1专武) 写法以及 "w"/"万" 单位，
+// 资源数量可与关键字以空格分开，如 "原石 32000"；
+// 未写命座的角色按0命、未写精炼的武器按精1计算，同一角色或武器出现多次时取最高值
+func ParseListing(text string) (eval.Assets, *ListingExplanation) {
+	account := eval.Assets{
+		Characters: make(map[string]int),
+		Weapons:    make(map[string]int),
+	}
+	explain := &ListingExplanation{}
+
+	tokens := splitListing(normalizeListing(text))
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		// 单独的资源关键字与其后单独的数量连读，如 "原石 32000"
+		if _, ok := listingResources[strings.TrimRight(token, ":=")]; ok && i+1 < len(tokens) && resourcePrefix.MatchString(tokens[i+1]) {
+			token += " " + tokens[i+1]
+			i++
+		}
+		for _, it := range parseListingToken(token) {
+			explain.Items = append(explain.Items, it)
+			var target map[string]int
+			switch it.Kind {
+			case ListingCharacter:
+				target = account.Characters
+			case ListingWeapon:
+				target = account.Weapons
+			case ListingPrimogem:
+				if account.YuanShi != 0 {
+					explain.Warnings = append(explain.Warnings, "原石出现多次，取最大值")
+				}
+				account.YuanShi = max(account.YuanShi, it.Value)
+			case ListingFate:
+				if account.JiuChanZhiYuan != 0 {
+					explain.Warnings = append(explain.Warnings, "纠缠之缘出现多次，取最大值")
+				}
+				account.JiuChanZhiYuan = max(account.JiuChanZhiYuan, it.Value)
+			}
+			if target == nil {
+				continue
+			}
+			if prev, dup := target[it.Name]; dup {
+				explain.Warnings = append(explain.Warnings, fmt.Sprintf("%s %s 出现多次，取最高值", it.Kind, it.Name))
+				it.Value = max(prev, it.Value)
+			}
+			target[it.Name] = it.Value
+		}
+	}
+	return account, explain
+}
+
+// splitListing 按分隔符切分文本，"6+1玛薇卡"、"玛薇卡6+1" 中的 "+" 不作分隔
+func splitListing(text string) []string {
+	var tokens []string
+	start := 0
+	for _, loc := range listingSeparators.FindAllStringIndex(text, -1) {
+		if text[loc[0]:loc[1]] == "+" {
+			before, after := text[start:loc[0]], text[loc[1]:]
+			if listingConstBeforePlus.MatchString(before) && listingRefineAfterPlus.MatchString(after) ||
+				listingSuffixConstBeforePlus.MatchString(before) && listingRefineAlone.MatchString(after) {
+				continue
+			}
+		}
+		if loc[0] > start {
+			tokens = append(tokens, text[start:loc[0]])
+		}
+		start = loc[1]
+	}
+	if start < len(text) {
+		tokens = append(tokens, text[start:])
+	}
+	return tokens
+}
+
+// normalizeListing 将全角字符转为半角并统一小写
+func normalizeListing(text string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '！' && r <= '～':
+			r -= '！' - '!'
+		case r == '　':
+			r = ' '
+		}
+		if r >= 'A' && r <= 'Z' {
+			r += 'a' - 'A'
+		}
+		return r
+	}, text)
+}
+
+type listingMatch struct {
+	start, end int
+	word       listingWord
+}
+
+// findListingWords 自左向右在片段中查找可识别的词，同一位置优先最长的词
+func findListingWords(token string) []listingMatch {
+	var matches []listingMatch
+	for i := 0; i < len(token); {
+		found := false
+		for _, w := range listingVocabulary {
+			if strings.HasPrefix(token[i:], w.word) {
+				matches = append(matches, listingMatch{start: i, end: i + len(w.word), word: w})
+				i += len(w.word)
+				found = true
+				break
+			}
+		}
+		if !found {
+			_, size := utf8.DecodeRuneInString(token[i:])
+			i += size
+		}
+	}
+	return matches
+}
+
+// parseListingToken 解读一个以分隔符分开的片段，片段中可以连写多个词，如 "6玛薇卡精5焚曜"
+// 词前的修饰取能解读的最长尾部，其余文字作为未识别片段忽略
+func parseListingToken(token string) []ListingItem {
+	matches := findListingWords(token)
+	if len(matches) == 0 {
+		return []ListingItem{{Text: token, Kind: ListingIgnored, Note: "未识别"}}
+	}
+
+	var items []ListingItem
+	pos := 0
+	for k, m := range matches {
+		next := len(token)
+		if k+1 < len(matches) {
+			next = matches[k+1].start
+		}
+		prefix, after := token[pos:m.start], token[m.end:next]
+
+		interpreted := false
+		for j := 0; j <= len(prefix); j++ {
+			if j < len(prefix) && !utf8.RuneStart(prefix[j]) {
+				continue
+			}
+			// 不从数字或 "N+M" 中间截取修饰，"10命" 不能读作 "0命"，"7+1" 不能读作 "1"
+			if j > 0 && j < len(prefix) && isDigit(prefix[j]) && (isDigit(prefix[j-1]) || prefix[j-1] == '+') {
+				continue
+			}
+			interpretedItems, suffixLen, ok := interpretListingWord(m.word, prefix[j:], after)
+			if !ok {
+				continue
+			}
+			if j > 0 {
+				items = append(items, ListingItem{Text: prefix[:j], Kind: ListingIgnored, Note: "未识别"})
+			}
+			for _, item := range interpretedItems {
+				item.Text = prefix[j:] + m.word.word + after[:suffixLen]
+				items = append(items, item)
+			}
+			pos = m.end + suffixLen
+			interpreted = true
+			break
+		}
+		if !interpreted {
+			// 只有资源关键字会解读失败: 前后都没有数量
+			items = append(items, ListingItem{Text: prefix + m.word.word, Kind: ListingIgnored, Note: "缺少数量"})
+			pos = m.end
+		}
+	}
+	if pos < len(token) {
+		items = append(items, ListingItem{Text: token[pos:], Kind: ListingIgnored, Note: "未识别"})
+	}
+	return items
+}
+
+func isDigit(b byte) bool { return b >= '0' && b <= '9' }
+
+// interpretListingWord 结合词前的修饰 prefix 与词后的文字 after 解读一个词
+// 返回解读结果及 after 中被用作修饰的长度；prefix 必须整体可解读
+// "6+1玛薇卡"、"玛薇卡6+1" 解读为角色及其专武两项
+func interpretListingWord(w listingWord, prefix, after string) ([]ListingItem, int, bool) {
+	item := ListingItem{Kind: w.kind, Name: w.name}
+	switch w.kind {
+	case ListingCharacter, ListingWeapon:
+		if w.word != w.name {
+			item.Note = "简称 " + w.word
+		}
+		pre, suf, full, lo, hi, unit := charPrefix, charSuffix, "满命", 0, 6, "命座"
+		if w.kind == ListingWeapon {
+			pre, suf, full, lo, hi, unit = weaponPrefix, weaponSuffix, "满精", 1, 5, "精炼"
+		}
+		level, n, refine := -1, 0, ""
+		if w.kind == ListingCharacter {
+			if sub := charSuffixPlus.FindStringSubmatch(after); sub != nil {
+				r := sub[len(sub)-1]
+				v, ok := parseListingLevel(sub[:len(sub)-1], full, lo, hi)
+				if _, valid := parseListingLevel([]string{r, r}, "满精", 1, 5); ok && valid {
+					level, n, refine = v, len(sub[0]), r
+				}
+			}
+		}
+		if sub := suf.FindStringSubmatch(after); sub != nil && level < 0 {
+			if v, ok := parseListingLevel(sub, full, lo, hi); ok {
+				level, n = v, len(sub[0])
+			}
+		}
+		if prefix != "" {
+			sub := pre.FindStringSubmatch(prefix)
+			if sub == nil {
+				return nil, 0, false
+			}
+			if w.kind == ListingCharacter {
+				var prefixRefine string
+				sub, prefixRefine = sub[:len(sub)-1], sub[len(sub)-1]
+				if refine == "" {
+					refine = prefixRefine
+				}
+			}
+			v, ok := parseListingLevel(sub, full, lo, hi)
+			if !ok {
+				return nil, 0, false
+			}
+			if level < 0 {
+				level = v
+			}
+		}
+		var signature []ListingItem
+		if refine != "" {
+			r, ok := parseListingLevel([]string{refine, refine}, "满精", 1, 5)
+			if !ok {
+				return nil, 0, false
+			}
+			if weapon := signatureWeapon(w.name); weapon != "" {
+				signature = append(signature, ListingItem{Kind: ListingWeapon, Name: weapon, Value: r, Note: w.name + "专武"})
+			} else {
+				item.Note = strings.TrimPrefix(item.Note+", 没有专武，忽略精炼", ", ")
+			}
+		}
+		if level < 0 {
+			level = lo
+			item.Note = strings.TrimPrefix(item.Note+", 未写"+unit, ", ")
+		}
+		item.Value = level
+		return append([]ListingItem{item}, signature...), n, true
+	default:
+		if prefix != "" {
+			sub := resourcePrefix.FindStringSubmatch(prefix)
+			if sub == nil {
+				return nil, 0, false
+			}
+			v, ok := parseListingAmount(sub[1])
+			item.Value = v
+			return []ListingItem{item}, 0, ok
+		}
+		if sub := resourceSuffix.FindStringSubmatch(after); sub != nil {
+			if v, ok := parseListingAmount(sub[1]); ok {
+				item.Value = v
+				return []ListingItem{item}, len(sub[0]), true
+			}
+		}
+		return nil, 0, false
+	}
+}
+
+// signatureWeapon 返回内置规则中角色的专武，没有时返回空
+func signatureWeapon(name string) string {
+	v, err := newrule.DefaultRegistry.Lookup(newrule.BuiltinVersion)
+	if err != nil {
+		return ""
+	}
+	return v.Rules.Characters[name].SpecializedWeapon
+}
+
+// parseListingLevel 从修饰的匹配结果中取出命座或精炼等级
+func parseListingLevel(sub []string, full string, lo, hi int) (int, bool) {
+	if strings.Contains(sub[0], full) {
+		return hi, true
+	}
+	for _, s := range sub[1:] {
+		if s == "" {
+			continue
+		}
+		v, ok := parseChineseNumber(s)
+		if !ok {
+			v, _ = strconv.Atoi(s)
+		}
+		return v, v >= lo && v <= hi
+	}
+	return 0, false
+}
+
+// parseListingAmount 解析资源数量，如 "32000"、"3.2w"、"1.5万"、"两万"
+func parseListingAmount(s string) (int, bool) {
+	if v, ok := parseChineseNumber(s); ok {
+		return v, true
+	}
+	scale := 1.0
+	switch {
+	case strings.HasSuffix(s, "w"):
+		s, scale = strings.TrimSuffix(s, "w"), 10000
+	case strings.HasSuffix(s, "万"):
+		s, scale = strings.TrimSuffix(s, "万"), 10000
+	case strings.HasSuffix(s, "k"):
+		s, scale = strings.TrimSuffix(s, "k"), 1000
+	case strings.HasSuffix(s, "千"):
+		s, scale = strings.TrimSuffix(s, "千"), 1000
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return int(math.Round(v * scale)), true
+}
+
+var chineseDigits = map[rune]int{'零': 0, '〇': 0, '一': 1, '二': 2, '两': 2, '三': 3, '四': 4, '五': 5, '六': 6, '七': 7, '八': 8, '九': 9}
+var chineseUnits = map[rune]int{'十': 10, '百': 100, '千': 1000}
+
+// parseChineseNumber 解析中文数字，如 "六"、"一百二十"、"三万二千"
+// 紧跟单位的末位数字按下一级单位计，如 "三万二" 为 32000、"一百二" 为 120，"三万零二" 仍为 30002
+func parseChineseNumber(s string) (int, bool) {
+	if s == "" {
+		return 0, false
+	}
+	total, section, num, scale := 0, 0, 0, 1
+	for _, r := range s {
+		if d, ok := chineseDigits[r]; ok {
+			num = d
+			if d == 0 {
+				scale = 1
+			}
+			continue
+		}
+		if u, ok := chineseUnits[r]; ok {
+			if num == 0 && u == 10 {
+				num = 1
+			}
+			section += num * u
+			num, scale = 0, max(u/10, 1)
+			continue
+		}
+		if r == '万' {
+			total += (section + num) * 10000
+			section, num, scale = 0, 0, 1000
+			continue
+		}
+		return 0, false
+	}
+	return total + section + num*scale, true
+}
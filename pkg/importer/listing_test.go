@@ -0,0 +1,123 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseListing(t *testing.T) {
+	account, explain := ParseListing("6玛薇卡 精5焚曜 2茜特菈莉 0希诺宁 原石3.2w 纠缠120")
+
+	wantChars := map[string]int{"玛薇卡": 6, "茜特菈莉": 2, "希诺宁": 0}
+	if !reflect.DeepEqual(account.Characters, wantChars) {
+		t.Errorf("Characters = %v, want %v", account.Characters, wantChars)
+	}
+	wantWeapons := map[string]int{"焚曜千阳": 5}
+	if !reflect.DeepEqual(account.Weapons, wantWeapons) {
+		t.Errorf("Weapons = %v, want %v", account.Weapons, wantWeapons)
+	}
+	if account.YuanShi != 32000 || account.JiuChanZhiYuan != 120 {
+		t.Errorf("YuanShi = %d, JiuChanZhiYuan = %d, want 32000, 120", account.YuanShi, account.JiuChanZhiYuan)
+	}
+	if len(explain.Items) != 6 || len(explain.Ignored()) != 0 {
+		t.Errorf("explain =\n%s", explain)
+	}
+	if s := explain.String(); !strings.Contains(s, `"精5焚曜" → 武器 焚曜千阳 精5 (简称 焚曜)`) {
+		t.Errorf("explain.String() =\n%s", s)
+	}
+}
+
+func TestParseListing_Forms(t *testing.T) {
+	tests := []struct {
+		text    string
+		chars   map[string]int
+		weapons map[string]int
+		yuanShi int
+		fate    int
+	}{
+		{text: "满命玛薇卡，焚曜满精", chars: map[string]int{"玛薇卡": 6}, weapons: map[string]int{"焚曜千阳": 5}},
+		{text: "六命万叶、二命茜特菈莉", chars: map[string]int{"枫原万叶": 6, "茜特菈莉": 2}},
+		{text: "玛薇卡2命+苍古r1", chars: map[string]int{"玛薇卡": 2}, weapons: map[string]int{"苍古自由之誓": 1}},
+		{text: "C6玛薇卡精5焚曜", chars: map[string]int{"玛薇卡": 6}, weapons: map[string]int{"焚曜千阳": 5}},
+		{text: "纳西妲 护摩", chars: map[string]int{"纳西妲": 0}, weapons: map[string]int{"护摩之杖": 1}},
+		{text: "原石：1.5万 粉球八十", yuanShi: 15000, fate: 80},
+		{text: "２万原石　１２０纠缠", yuanShi: 20000, fate: 120},
+		{text: "原石 32000 纠缠 120", yuanShi: 32000, fate: 120},
+		{text: "原石： 三万二", yuanShi: 32000},
+		{text: "6+1玛薇卡 2+1茜特菈莉", chars: map[string]int{"玛薇卡": 6, "茜特菈莉": 2}, weapons: map[string]int{"焚曜千阳": 1, "祭星者之望": 1}},
+		{text: "玛薇卡6+1 茜特菈莉满命+5", chars: map[string]int{"玛薇卡": 6, "茜特菈莉": 6}, weapons: map[string]int{"焚曜千阳": 1, "祭星者之望": 5}},
+		{text: "火神c6+1，纠缠8+6茜特菈莉", chars: map[string]int{"玛薇卡": 6, "茜特菈莉": 6}, weapons: map[string]int{"焚曜千阳": 1}, fate: 8},
+		{text: "满命+5火神，纠缠120+6茜特菈莉", chars: map[string]int{"玛薇卡": 6, "茜特菈莉": 6}, weapons: map[string]int{"焚曜千阳": 5}, fate: 120},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			account, explain := ParseListing(tt.text)
+			if tt.chars == nil {
+				tt.chars = map[string]int{}
+			}
+			if tt.weapons == nil {
+				tt.weapons = map[string]int{}
+			}
+			if !reflect.DeepEqual(account.Characters, tt.chars) || !reflect.DeepEqual(account.Weapons, tt.weapons) ||
+				account.YuanShi != tt.yuanShi || account.JiuChanZhiYuan != tt.fate {
+				t.Errorf("got %+v\n%s", account, explain)
+			}
+			if ignored := explain.Ignored(); len(ignored) != 0 {
+				t.Errorf("Ignored = %v", ignored)
+			}
+		})
+	}
+}
+
+func TestParseListing_Ignored(t *testing.T) {
+	account, explain := ParseListing("自抽号 6玛薇卡 7命希诺宁 原石 神里绫华")
+	if account.Characters["玛薇卡"] != 6 {
+		t.Errorf("Characters = %v", account.Characters)
+	}
+	// "7命" 超出命座范围，希诺宁按未写命座处理
+	if c, ok := account.Characters["希诺宁"]; !ok || c != 0 {
+		t.Errorf("Characters[希诺宁] = %d (%v), want 0", c, ok)
+	}
+	want := []string{"自抽号", "7命", "原石", "神里绫华"}
+	if got := explain.Ignored(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Ignored = %v, want %v", got, want)
+	}
+}
+
+func TestParseListing_OutOfRange(t *testing.T) {
+	account, explain := ParseListing("10命玛薇卡 精15焚曜 7+1茜特菈莉 6+6希诺宁 基尼奇6+6")
+	want := map[string]int{"玛薇卡": 0, "茜特菈莉": 0, "希诺宁": 0, "基尼奇": 0}
+	if !reflect.DeepEqual(account.Characters, want) || len(account.Weapons) != 1 || account.Weapons["焚曜千阳"] != 1 {
+		t.Errorf("got %+v\n%s", account, explain)
+	}
+	wantIgnored := []string{"10命", "精15", "7+1", "6+6", "6+6"}
+	if got := explain.Ignored(); !reflect.DeepEqual(got, wantIgnored) {
+		t.Errorf("Ignored = %v, want %v", got, wantIgnored)
+	}
+}
+
+func TestParseChineseNumber(t *testing.T) {
+	tests := map[string]int{"六": 6, "十": 10, "十二": 12, "八十": 80, "一百二十": 120, "三万二千": 32000, "两万": 20000,
+		"三万二": 32000, "一千五": 1500, "一百二": 120, "三万零二": 30002, "十万": 100000}
+	for s, want := range tests {
+		if got, ok := parseChineseNumber(s); !ok || got != want {
+			t.Errorf("parseChineseNumber(%q) = %d, %v, want %d", s, got, ok, want)
+		}
+	}
+}
+
+func TestListingAliases(t *testing.T) {
+	known := make(map[string]bool)
+	for _, name := range CharacterNames {
+		known[name] = true
+	}
+	for _, name := range WeaponNames {
+		known[name] = true
+	}
+	for alias, name := range ListingAliases {
+		if !known[name] {
+			t.Errorf("ListingAliases[%q] = %q 不是已知的角色或武器", alias, name)
+		}
+	}
+}
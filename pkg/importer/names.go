@@ -0,0 +1,363 @@
+package importer
+
+// CharacterNames 将社区工具使用的英文角色键映射为估值规则中的中文角色名
+// 键为 GOOD 格式的写法 (英文名去掉空格与标点，如 "HuTao"、"KaedeharaKazuha")
+var CharacterNames = mergeNames(limitedFiveStarNames, standardFiveStarNames, fourStarNames)
+
+// 限定五星
+var limitedFiveStarNames = map[string]string{
+	"Durin":             "杜林",
+	"Ineffa":            "伊涅芙",
+	"Skirk":             "丝柯克",
+	"Escoffier":         "爱可菲",
+	"Varesa":            "瓦雷莎",
+	"Citlali":           "茜特菈莉",
+	"Mavuika":           "玛薇卡",
+	"Chasca":            "恰斯卡",
+	"Xilonen":           "希诺宁",
+	"Kinich":            "基尼奇",
+	"Mualani":           "玛拉妮",
+	"Emilie":            "艾梅莉埃",
+	"Clorinde":          "克洛琳德",
+	"Arlecchino":        "阿蕾奇诺",
+	"Sigewinne":         "希格雯",
+	"Chiori":            "千织",
+	"Xianyun":           "闲云",
+	"Navia":             "娜维娅",
+	"Furina":            "芙宁娜",
+	"Neuvillette":       "那维莱特",
+	"Wriothesley":       "莱欧斯利",
+	"Lyney":             "林尼",
+	"Baizhu":            "白术",
+	"Alhaitham":         "艾尔海森",
+	"Wanderer":          "流浪者",
+	"Nahida":            "纳西妲",
+	"Cyno":              "赛诺",
+	"Nilou":             "妮露",
+	"KamisatoAyato":     "神里绫人",
+	"Shenhe":            "申鹤",
+	"Yelan":             "夜兰",
+	"YaeMiko":           "八重神子",
+	"AratakiItto":       "荒泷一斗",
+	"SangonomiyaKokomi": "珊瑚宫心海",
+	"RaidenShogun":      "雷电将军",
+	"Eula":              "优菈",
+	"Yoimiya":           "宵宫",
+	"KaedeharaKazuha":   "枫原万叶",
+	"HuTao":             "胡桃",
+	"Ganyu":             "甘雨",
+	"Tartaglia":         "达达利亚",
+	"Zhongli":           "钟离",
+	"Xiao":              "魈",
+	"Klee":              "可莉",
+	"Venti":             "温迪",
+	"Lauma":             "菈乌玛",
+	"Flins":             "菲林斯",
+	"Nefer":             "奈芙尔",
+	"Columbina":         "哥伦比娅",
+	"Zibai":             "兹白",
+	"Varka":             "法尔伽",
+	"Linnea":            "莉奈娅",
+}
+
+// 常驻五星
+var standardFiveStarNames = map[string]string{
+	"Jean":             "琴",
+	"Diluc":            "迪卢克",
+	"Mona":             "莫娜",
+	"Qiqi":             "七七",
+	"Keqing":           "刻晴",
+	"Tighnari":         "提纳里",
+	"Dehya":            "迪希雅",
+	"YumemizukiMizuki": "梦见月瑞希",
+}
+
+// 四星
+var fourStarNames = map[string]string{
+	"Xiangling":       "香菱",
+	"Xingqiu":         "行秋",
+	"Bennett":         "班尼特",
+	"Barbara":         "芭芭拉",
+	"Beidou":          "北斗",
+	"Ningguang":       "凝光",
+	"Fischl":          "菲谢尔",
+	"Sucrose":         "砂糖",
+	"Chongyun":        "重云",
+	"Noelle":          "诺艾尔",
+	"Razor":           "雷泽",
+	"Amber":           "安柏",
+	"Kaeya":           "凯亚",
+	"Lisa":            "丽莎",
+	"Diona":           "迪奥娜",
+	"Xinyan":          "辛焱",
+	"Rosaria":         "罗莎莉亚",
+	"Yanfei":          "烟绯",
+	"Sayu":            "早柚",
+	"KujouSara":       "九条裟罗",
+	"Thoma":           "托马",
+	"Gorou":           "五郎",
+	"YunJin":          "云堇",
+	"KukiShinobu":     "久岐忍",
+	"ShikanoinHeizou": "鹿野院平藏",
+	"Collei":          "柯莱",
+	"Dori":            "多莉",
+	"Candace":         "坎蒂丝",
+	"Layla":           "莱依拉",
+	"Faruzan":         "珐露珊",
+	"Yaoyao":          "瑶瑶",
+	"Mika":            "米卡",
+	"Kaveh":           "卡维",
+	"Kirara":          "绮良良",
+	"Lynette":         "琳妮特",
+	"Freminet":        "菲米尼",
+	"Charlotte":       "夏洛蒂",
+	"Chevreuse":       "夏沃蕾",
+	"Gaming":          "嘉明",
+	"Sethos":          "赛索斯",
+	"Kachina":         "卡齐娜",
+	"Ororon":          "欧洛伦",
+	"Iansan":          "伊安珊",
+	"LanYan":          "蓝砚",
+	"Ifa":             "伊法",
+}
+
+// mergeNames 合并多张名称表
+func mergeNames(tables ...map[string]string) map[string]string {
+	merged := make(map[string]string)
+	for _, t := range tables {
+		for k, v := range t {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+// WeaponNames 将社区工具使用的英文武器键映射为估值规则中的中文武器名
+var WeaponNames = map[string]string{
+	"AthameArtis":                  "黑蚀",
+	"FracturedHalo":                "支离轮光",
+	"Azurelight":                   "苍耀",
+	"SymphonistOfScents":           "香韵奏者",
+	"VividNotions":                 "溢彩心念",
+	"StarcallersWatch":             "祭星者之望",
+	"AThousandBlazingSuns":         "焚曜千阳",
+	"AstralVulturesCrimsonPlumage": "星鹫赤羽",
+	"PeakPatrolSong":               "岩峰巡歌",
+	"FangOfTheMountainKing":        "山王长牙",
+	"SurfsUp":                      "冲浪时光",
+	"LumidouceElegy":               "柔灯挽歌",
+	"Absolution":                   "赦罪",
+	"CrimsonMoonsSemblance":        "赤月之形",
+	"SilvershowerHeartstrings":     "白雨心弦",
+	"UrakuMisugiri":                "有乐御簾切",
+	"CranesEchoingCall":            "鹤鸣余音",
+	"Verdict":                      "裁断",
+	"SplendorOfTranquilWaters":     "静水流涌之辉",
+	"TomeOfTheEternalFlow":         "万世流涌大典",
+	"CashflowSupervision":          "金流监督",
+	"TheFirstGreatMagic":           "最初的大魔术",
+	"JadefallsSplendor":            "碧落之珑",
+	"LightOfFoliarIncision":        "裁叶萃光",
+	"TulaytullahsRemembrance":      "图莱杜拉的回忆",
+	"AThousandFloatingDreams":      "千夜浮梦",
+	"StaffOfTheScarletSands":       "赤沙之杖",
+	"KeyOfKhajNisut":               "圣显之钥",
+	"HaranGeppakuFutsu":            "波乱月白经津",
+	"CalamityQueller":              "息灾",
+	"AquaSimulacra":                "若水",
+	"KagurasVerity":                "神乐之真意",
+	"RedhornStonethresher":         "赤角石溃杵",
+	"EverlastingMoonglow":          "不灭月华",
+	"EngulfingLightning":           "薙草之稻光",
+	"SongOfBrokenPines":            "松籁响起之时",
+	"ThunderingPulse":              "飞雷之弦振",
+	"FreedomSworn":                 "苍古自由之誓",
+	"StaffOfHoma":                  "护摩之杖",
+	"AmosBow":                      "阿莫斯之弓",
+	"PolarStar":                    "冬极白星",
+	"VortexVanquisher":             "贯虹之槊",
+	"PrimordialJadeWingedSpear":    "和璞鸢",
+	"LostPrayerToTheSacredWinds":   "四风原典",
+	"ElegyForTheEnd":               "终末嗟叹之诗",
+	"NightweaversLookingGlass":     "纺夜天镜",
+	"BloodsoakedRuins":             "血染荒城",
+	"ReliquaryOfTruth":             "真语秘匣",
+	"NocturnesCurtainCall":         "帷间夜曲",
+	"LightbearingMoonshard":        "朏魄含光",
+	"GestOfTheMightyWolf":          "狼的武功歌",
+	"FrostboundOathGoldenBough":    "霜结的誓金枝",
+}
+
+// unpricedWeaponKeys 是不在估值规则价格表中的武器 (常驻五星、四星及以下) 的 GOOD 键
+// 这些武器不参与估值，导入时跳过并计数，不计入未映射条目
+var unpricedWeaponKeys = map[string]bool{
+	// 单手剑
+	"AquilaFavonia":         true,
+	"SkywardBlade":          true,
+	"SummitShaper":          true,
+	"PrimordialJadeCutter":  true,
+	"MistsplitterReforged":  true,
+	"TheFlute":              true,
+	"TheBlackSword":         true,
+	"TheAlleyFlash":         true,
+	"SwordOfDescension":     true,
+	"SacrificialSword":      true,
+	"FavoniusSword":         true,
+	"PrototypeRancour":      true,
+	"IronSting":             true,
+	"BlackcliffLongsword":   true,
+	"RoyalLongsword":        true,
+	"LionsRoar":             true,
+	"AmenomaKageuchi":       true,
+	"CinnabarSpindle":       true,
+	"FesteringDesire":       true,
+	"KagotsurubeIsshin":     true,
+	"SapwoodBlade":          true,
+	"XiphosMoonlight":       true,
+	"ToukabouShigure":       true,
+	"WolfFang":              true,
+	"FinaleOfTheDeep":       true,
+	"FleuveCendreFerryman":  true,
+	"TheDockhandsAssistant": true,
+	"SwordOfNarzissenkreuz": true,
+	"SturdyBone":            true,
+	"FluteOfEzpitzal":       true,
+	"CalamityOfEshu":        true,
+	"CoolSteel":             true,
+	"HarbingerOfDawn":       true,
+	"TravelersHandySword":   true,
+	"DarkIronSword":         true,
+	"FilletBlade":           true,
+	"SkyriderSword":         true,
+	"DullBlade":             true,
+	"SilverSword":           true,
+	// 双手剑
+	"WolfsGravestone":                 true,
+	"SkywardPride":                    true,
+	"TheUnforged":                     true,
+	"BeaconOfTheReedSea":              true,
+	"TheBell":                         true,
+	"FavoniusGreatsword":              true,
+	"SacrificialGreatsword":           true,
+	"Rainslasher":                     true,
+	"PrototypeArchaic":                true,
+	"Whiteblind":                      true,
+	"SerpentSpine":                    true,
+	"BlackcliffSlasher":               true,
+	"RoyalGreatsword":                 true,
+	"LithicBlade":                     true,
+	"SnowTombedStarsilver":            true,
+	"LuxuriousSeaLord":                true,
+	"Akuoumaru":                       true,
+	"KatsuragikiriNagamasa":           true,
+	"MakhairaAquamarine":              true,
+	"ForestRegalia":                   true,
+	"MailedFlower":                    true,
+	"TalkingStick":                    true,
+	"TidalShadow":                     true,
+	"UltimateOverlordsMegaMagicSword": true,
+	"PortablePowerSaw":                true,
+	"FruitfulHook":                    true,
+	"EarthShaker":                     true,
+	"FerrousShadow":                   true,
+	"BloodtaintedGreatsword":          true,
+	"WhiteIronGreatsword":             true,
+	"DebateClub":                      true,
+	"SkyriderGreatsword":              true,
+	"WasterGreatsword":                true,
+	"OldMercsPal":                     true,
+	// 长柄武器
+	"SkywardSpine":          true,
+	"DragonsBane":           true,
+	"FavoniusLance":         true,
+	"Deathmatch":            true,
+	"BlackcliffPole":        true,
+	"CrescentPike":          true,
+	"PrototypeStarglitter":  true,
+	"LithicSpear":           true,
+	"RoyalSpear":            true,
+	"DragonspineSpear":      true,
+	"KitainCrossSpear":      true,
+	"TheCatch":              true,
+	"WavebreakersFin":       true,
+	"Moonpiercer":           true,
+	"MissiveWindspear":      true,
+	"BalladOfTheFjords":     true,
+	"RightfulReward":        true,
+	"ProspectorsDrill":      true,
+	"MountainBracingBolt":   true,
+	"FootprintOfTheRainbow": true,
+	"TamayurateiNoOhanashi": true,
+	"WhiteTassel":           true,
+	"Halberd":               true,
+	"BlackTassel":           true,
+	"BeginnersProtector":    true,
+	"IronPoint":             true,
+	// 法器
+	"SkywardAtlas":                  true,
+	"MemoryOfDust":                  true,
+	"TheWidsith":                    true,
+	"SacrificialFragments":          true,
+	"FavoniusCodex":                 true,
+	"Frostbearer":                   true,
+	"SolarPearl":                    true,
+	"PrototypeAmber":                true,
+	"MappaMare":                     true,
+	"BlackcliffAgate":               true,
+	"RoyalGrimoire":                 true,
+	"EyeOfPerception":               true,
+	"WineAndSong":                   true,
+	"DodocoTales":                   true,
+	"HakushinRing":                  true,
+	"OathswornEye":                  true,
+	"FruitOfFulfillment":            true,
+	"WanderingEvenstar":             true,
+	"FlowingPurity":                 true,
+	"SacrificialJade":               true,
+	"BalladOfTheBoundlessBlue":      true,
+	"AshGravenDrinkingHorn":         true,
+	"RingOfYaxche":                  true,
+	"WaveridingWhirl":               true,
+	"MagicGuide":                    true,
+	"ThrillingTalesOfDragonSlayers": true,
+	"OtherworldlyStory":             true,
+	"EmeraldOrb":                    true,
+	"TwinNephrite":                  true,
+	"ApprenticesNotes":              true,
+	"PocketGrimoire":                true,
+	// 弓
+	"SkywardHarp":            true,
+	"HuntersPath":            true,
+	"TheStringless":          true,
+	"FavoniusWarbow":         true,
+	"SacrificialBow":         true,
+	"Rust":                   true,
+	"PrototypeCrescent":      true,
+	"CompoundBow":            true,
+	"BlackcliffWarbow":       true,
+	"RoyalBow":               true,
+	"AlleyHunter":            true,
+	"TheViridescentHunt":     true,
+	"WindblumeOde":           true,
+	"MitternachtsWaltz":      true,
+	"Hamayumi":               true,
+	"Predator":               true,
+	"MouunsMoon":             true,
+	"FadingTwilight":         true,
+	"KingsSquire":            true,
+	"EndOfTheLine":           true,
+	"IbisPiercer":            true,
+	"ScionOfTheBlazingSun":   true,
+	"SongOfStillness":        true,
+	"RangeGauge":             true,
+	"Cloudforged":            true,
+	"FlowerWreathedFeathers": true,
+	"ChainBreaker":           true,
+	"Slingshot":              true,
+	"RavenBow":               true,
+	"SharpshootersOath":      true,
+	"RecurveBow":             true,
+	"Messenger":              true,
+	"HuntersBow":             true,
+	"SeasonedHuntersBow":     true,
+}
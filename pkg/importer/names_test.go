@@ -0,0 +1,49 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval/newrule"
+)
+
+// builtinRules 返回内置估值规则
+func builtinRules(t *testing.T) newrule.ValuationRules {
+	t.Helper()
+	v, err := newrule.DefaultRegistry.Lookup(newrule.BuiltinVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v.Rules
+}
+
+func TestNameTables_CoverRules(t *testing.T) {
+	r := builtinRules(t)
+	for name := range r.Characters {
+		if !mapsValue(CharacterNames, name) {
+			t.Errorf("角色 %s 不在 CharacterNames 中", name)
+		}
+	}
+	for name := range r.Weapons {
+		if !mapsValue(WeaponNames, name) {
+			t.Errorf("武器 %s 不在 WeaponNames 中", name)
+		}
+	}
+}
+
+// mapsValue 判断名称表中是否有映射到 name 的键
+func mapsValue(names map[string]string, name string) bool {
+	for _, v := range names {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestUnpricedWeaponKeys_DisjointFromWeaponNames(t *testing.T) {
+	for key := range unpricedWeaponKeys {
+		if _, ok := WeaponNames[key]; ok {
+			t.Errorf("武器 %s 在价格表中，不应列为不参与估值", key)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Package importer 将各种外部账号数据转换为估值使用的 eval.Assets
+package importer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UnmappedEntry 是导入时无法映射为估值规则名称的条目
+type UnmappedEntry struct {
+	Kind  string // "角色"、"武器"
+	Key   string // 原始数据中的键
+	Count int    // 出现次数
+}
+
+// Report 记录一次导入的来源、未映射条目与提示
+type Report struct {
+	Source   string
+	Unmapped []UnmappedEntry
+	Unpriced int // 已识别但不在估值规则价格表中、因而跳过的条目数
+	Warnings []string
+}
+
+// addUnmapped 记录一个未映射条目，同一条目重复出现时累加次数
+func (r *Report) addUnmapped(kind, key string) {
+	for i := range r.Unmapped {
+		if r.Unmapped[i].Kind == kind && r.Unmapped[i].Key == key {
+			r.Unmapped[i].Count++
+			return
+		}
+	}
+	r.Unmapped = append(r.Unmapped, UnmappedEntry{Kind: kind, Key: key, Count: 1})
+}
+
+func (r *Report) warnf(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// sortUnmapped 按类别与键排序未映射条目
+func (r *Report) sortUnmapped() {
+	sort.Slice(r.Unmapped, func(i, j int) bool {
+		if r.Unmapped[i].Kind != r.Unmapped[j].Kind {
+			return r.Unmapped[i].Kind < r.Unmapped[j].Kind
+		}
+		return r.Unmapped[i].Key < r.Unmapped[j].Key
+	})
+}
+
+func (r *Report) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "导入来源: %s\n", r.Source)
+	if len(r.Unmapped) == 0 {
+		sb.WriteString("全部条目均已映射\n")
+	} else {
+		fmt.Fprintf(&sb, "未映射条目 (%d):\n", len(r.Unmapped))
+		for _, e := range r.Unmapped {
+			if e.Count > 1 {
+				fmt.Fprintf(&sb, "  - [%s] %s x%d\n", e.Kind, e.Key, e.Count)
+			} else {
+				fmt.Fprintf(&sb, "  - [%s] %s\n", e.Kind, e.Key)
+			}
+		}
+	}
+	if r.Unpriced > 0 {
+		fmt.Fprintf(&sb, "跳过不参与估值的条目 %d 个\n", r.Unpriced)
+	}
+	for _, w := range r.Warnings {
+		fmt.Fprintf(&sb, "提示: %s\n", w)
+	}
+	return sb.String()
+}
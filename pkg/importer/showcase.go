@@ -0,0 +1,107 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+// showcaseProfile 是公开展柜网站使用的玩家资料 JSON 中估值用到的部分
+type showcaseProfile struct {
+	PlayerInfo *struct {
+		Nickname string `json:"nickname"`
+	} `json:"playerInfo"`
+	UID            string           `json:"uid"`
+	AvatarInfoList []showcaseAvatar `json:"avatarInfoList"`
+}
+
+type showcaseAvatar struct {
+	AvatarID     int             `json:"avatarId"`
+	TalentIDList []int           `json:"talentIdList"` // 已激活的命座，长度即命座数
+	EquipList    []showcaseEquip `json:"equipList"`
+}
+
+type showcaseEquip struct {
+	ItemID int `json:"itemId"`
+	Weapon *struct {
+		AffixMap map[string]int `json:"affixMap"` // 精炼等级 - 1
+	} `json:"weapon"`
+}
+
+// 旅行者的角色 ID
+var showcaseTravelerIDs = map[int]bool{10000005: true, 10000007: true}
+
+// ImportShowcase 将离线保存的展柜资料 JSON 转换为账号资产
+// 展柜只包含展示中的角色及其装备的武器，命座数取已激活命座的数量，
+// 精炼等级取 affixMap 中的值加一 (无 affixMap 的低星武器视为精炼1)
+func ImportShowcase(data []byte) (eval.Assets, *Report, error) {
+	var profile showcaseProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return eval.Assets{}, nil, fmt.Errorf("解析展柜数据失败: %w", err)
+	}
+	if profile.AvatarInfoList == nil {
+		return eval.Assets{}, nil, fmt.Errorf("展柜数据中没有 avatarInfoList，角色详情可能未公开")
+	}
+
+	report := &Report{Source: "展柜"}
+	if profile.PlayerInfo != nil && profile.PlayerInfo.Nickname != "" {
+		report.Source += " (" + profile.PlayerInfo.Nickname
+		if profile.UID != "" {
+			report.Source += " " + profile.UID
+		}
+		report.Source += ")"
+	}
+	account := eval.Assets{
+		Characters: make(map[string]int),
+		Weapons:    make(map[string]int),
+	}
+
+	for _, avatar := range profile.AvatarInfoList {
+		for _, equip := range avatar.EquipList {
+			if equip.Weapon != nil {
+				importShowcaseWeapon(&account, report, equip)
+			}
+		}
+		if showcaseTravelerIDs[avatar.AvatarID] {
+			continue
+		}
+		name, ok := CharacterNames[AvatarIDs[avatar.AvatarID]]
+		if !ok {
+			report.addUnmapped("角色", strconv.Itoa(avatar.AvatarID))
+			continue
+		}
+		c := len(avatar.TalentIDList)
+		if c > 6 {
+			report.warnf("角色 %s 有 %d 个命座，已按6命计算", name, c)
+			c = 6
+		}
+		account.Characters[name] = c
+	}
+
+	report.sortUnmapped()
+	return account, report, nil
+}
+
+// importShowcaseWeapon 记录角色装备的武器，同名武器取最高精炼
+func importShowcaseWeapon(account *eval.Assets, report *Report, equip showcaseEquip) {
+	name, ok := WeaponNames[WeaponIDs[equip.ItemID]]
+	if !ok {
+		report.addUnmapped("武器", strconv.Itoa(equip.ItemID))
+		return
+	}
+	refine := 1
+	for _, affix := range equip.Weapon.AffixMap {
+		refine = affix + 1
+	}
+	if refine > 5 {
+		report.warnf("武器 %s 的精炼 %d 超出 1-5，已按精炼5计算", name, refine)
+		refine = 5
+	}
+	if prev, dup := account.Weapons[name]; dup {
+		report.warnf("武器 %s 有多把，取最高精炼", name)
+		refine = max(prev, refine)
+	}
+	account.Weapons[name] = refine
+}
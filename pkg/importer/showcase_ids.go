@@ -0,0 +1,168 @@
+package importer
+
+// AvatarIDs 将展柜数据中的角色 ID 映射为 GOOD 格式的角色键，再经 CharacterNames 得到中文名
+// 旅行者 (10000005、10000007) 不参与估值，不在表中
+var AvatarIDs = map[int]string{
+	10000003: "Jean",
+	10000006: "Lisa",
+	10000014: "Barbara",
+	10000015: "Kaeya",
+	10000016: "Diluc",
+	10000020: "Razor",
+	10000021: "Amber",
+	10000022: "Venti",
+	10000023: "Xiangling",
+	10000024: "Beidou",
+	10000025: "Xingqiu",
+	10000026: "Xiao",
+	10000027: "Ningguang",
+	10000029: "Klee",
+	10000030: "Zhongli",
+	10000031: "Fischl",
+	10000032: "Bennett",
+	10000033: "Tartaglia",
+	10000034: "Noelle",
+	10000035: "Qiqi",
+	10000036: "Chongyun",
+	10000037: "Ganyu",
+	10000039: "Diona",
+	10000041: "Mona",
+	10000042: "Keqing",
+	10000043: "Sucrose",
+	10000044: "Xinyan",
+	10000045: "Rosaria",
+	10000046: "HuTao",
+	10000047: "KaedeharaKazuha",
+	10000048: "Yanfei",
+	10000049: "Yoimiya",
+	10000050: "Thoma",
+	10000051: "Eula",
+	10000052: "RaidenShogun",
+	10000053: "Sayu",
+	10000054: "SangonomiyaKokomi",
+	10000055: "Gorou",
+	10000056: "KujouSara",
+	10000057: "AratakiItto",
+	10000058: "YaeMiko",
+	10000059: "ShikanoinHeizou",
+	10000060: "Yelan",
+	10000061: "Kirara",
+	10000063: "Shenhe",
+	10000064: "YunJin",
+	10000065: "KukiShinobu",
+	10000066: "KamisatoAyato",
+	10000067: "Collei",
+	10000068: "Dori",
+	10000069: "Tighnari",
+	10000070: "Nilou",
+	10000071: "Cyno",
+	10000072: "Candace",
+	10000073: "Nahida",
+	10000074: "Layla",
+	10000075: "Wanderer",
+	10000076: "Faruzan",
+	10000077: "Yaoyao",
+	10000078: "Alhaitham",
+	10000079: "Dehya",
+	10000080: "Mika",
+	10000081: "Kaveh",
+	10000082: "Baizhu",
+	10000083: "Lynette",
+	10000084: "Lyney",
+	10000085: "Freminet",
+	10000086: "Wriothesley",
+	10000087: "Neuvillette",
+	10000088: "Charlotte",
+	10000089: "Furina",
+	10000090: "Chevreuse",
+	10000091: "Navia",
+	10000092: "Gaming",
+	10000093: "Xianyun",
+	10000094: "Chiori",
+	10000095: "Sigewinne",
+	10000096: "Arlecchino",
+	10000097: "Sethos",
+	10000098: "Clorinde",
+	10000099: "Emilie",
+	10000100: "Kachina",
+	10000101: "Kinich",
+	10000102: "Mualani",
+	10000103: "Xilonen",
+	10000104: "Chasca",
+	10000105: "Ororon",
+	10000106: "Mavuika",
+	10000107: "Citlali",
+	10000108: "LanYan",
+	10000109: "YumemizukiMizuki",
+	10000110: "Iansan",
+	10000111: "Varesa",
+	10000112: "Escoffier",
+	10000113: "Ifa",
+	10000114: "Skirk",
+	10000116: "Ineffa",
+	10000119: "Lauma",
+	10000120: "Flins",
+	10000122: "Nefer",
+	10000123: "Durin",
+	10000125: "Columbina",
+	10000126: "Zibai",
+	10000128: "Varka",
+	10000129: "Linnea",
+}
+
+// WeaponIDs 将展柜数据中的武器 ID 映射为 GOOD 格式的武器键，再经 WeaponNames 得到中文名
+// 只收录估值规则中出现的武器
+var WeaponIDs = map[int]string{
+	11503: "FreedomSworn",
+	11510: "HaranGeppakuFutsu",
+	11511: "KeyOfKhajNisut",
+	11512: "LightOfFoliarIncision",
+	11513: "SplendorOfTranquilWaters",
+	11514: "UrakuMisugiri",
+	11515: "Absolution",
+	11516: "PeakPatrolSong",
+	11517: "Azurelight",
+	11518: "AthameArtis",
+	11519: "LightbearingMoonshard",
+	12503: "SongOfBrokenPines",
+	12510: "RedhornStonethresher",
+	12512: "Verdict",
+	12513: "FangOfTheMountainKing",
+	12514: "AThousandBlazingSuns",
+	12515: "GestOfTheMightyWolf",
+	13501: "StaffOfHoma",
+	13504: "VortexVanquisher",
+	13505: "PrimordialJadeWingedSpear",
+	13507: "CalamityQueller",
+	13509: "EngulfingLightning",
+	13511: "StaffOfTheScarletSands",
+	13512: "CrimsonMoonsSemblance",
+	13513: "LumidouceElegy",
+	13514: "SymphonistOfScents",
+	13515: "FracturedHalo",
+	13516: "BloodsoakedRuins",
+	14502: "LostPrayerToTheSacredWinds",
+	14506: "EverlastingMoonglow",
+	14509: "KagurasVerity",
+	14511: "AThousandFloatingDreams",
+	14512: "TulaytullahsRemembrance",
+	14513: "CashflowSupervision",
+	14514: "TomeOfTheEternalFlow",
+	14515: "JadefallsSplendor",
+	14516: "CranesEchoingCall",
+	14517: "SurfsUp",
+	14518: "StarcallersWatch",
+	14519: "VividNotions",
+	14520: "NightweaversLookingGlass",
+	14521: "ReliquaryOfTruth",
+	14522: "NocturnesCurtainCall",
+	15502: "AmosBow",
+	15503: "ElegyForTheEnd",
+	15507: "PolarStar",
+	15508: "AquaSimulacra",
+	15509: "ThunderingPulse",
+	15512: "TheFirstGreatMagic",
+	15513: "SilvershowerHeartstrings",
+	15514: "AstralVulturesCrimsonPlumage",
+	15515: "FrostboundOathGoldenBough",
+}
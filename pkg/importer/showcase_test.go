@@ -0,0 +1,114 @@
+package importer
+
+import (
+	"testing"
+)
+
+const sampleShowcase = `{
+  "playerInfo": {"nickname": "旅行者", "level": 60, "showAvatarInfoList": [{"avatarId": 10000106, "level": 90}]},
+  "uid": "100000001",
+  "avatarInfoList": [
+    {
+      "avatarId": 10000106,
+      "talentIdList": [10601, 10602, 10603, 10604, 10605, 10606],
+      "equipList": [
+        {"itemId": 92540, "reliquary": {"level": 21}},
+        {"itemId": 14514, "weapon": {"level": 90, "affixMap": {"114514": 0}}}
+      ]
+    },
+    {
+      "avatarId": 10000089,
+      "equipList": [
+        {"itemId": 11513, "weapon": {"level": 90, "affixMap": {"111513": 2}}}
+      ]
+    },
+    {
+      "avatarId": 10000007,
+      "talentIdList": [1, 2],
+      "equipList": [{"itemId": 11301, "weapon": {"level": 1}}]
+    },
+    {
+      "avatarId": 10009999,
+      "equipList": [{"itemId": 11513, "weapon": {"level": 90, "affixMap": {"111513": 0}}}]
+    }
+  ]
+}`
+
+func TestImportShowcase(t *testing.T) {
+	account, report, err := ImportShowcase([]byte(sampleShowcase))
+	if err != nil {
+		t.Fatalf("ImportShowcase: %v", err)
+	}
+	if got := account.Characters["玛薇卡"]; got != 6 {
+		t.Errorf("Characters[玛薇卡] = %d, want 6", got)
+	}
+	if got, ok := account.Characters["芙宁娜"]; !ok || got != 0 {
+		t.Errorf("Characters[芙宁娜] = %d (%v), want 0", got, ok)
+	}
+	if len(account.Characters) != 2 {
+		t.Errorf("Characters = %v, want 2 entries", account.Characters)
+	}
+	if got := account.Weapons["万世流涌大典"]; got != 1 {
+		t.Errorf("Weapons[万世流涌大典] = %d, want 1", got)
+	}
+	if got := account.Weapons["静水流涌之辉"]; got != 3 {
+		t.Errorf("Weapons[静水流涌之辉] = %d, want 3 (取最高精炼)", got)
+	}
+
+	want := []UnmappedEntry{
+		{Kind: "武器", Key: "11301", Count: 1},
+		{Kind: "角色", Key: "10009999", Count: 1},
+	}
+	if len(report.Unmapped) != len(want) {
+		t.Fatalf("Unmapped = %v, want %v", report.Unmapped, want)
+	}
+	for i := range want {
+		if report.Unmapped[i] != want[i] {
+			t.Errorf("Unmapped[%d] = %v, want %v", i, report.Unmapped[i], want[i])
+		}
+	}
+	if report.Source != "展柜 (旅行者 100000001)" {
+		t.Errorf("Source = %q", report.Source)
+	}
+}
+
+func TestImportShowcase_Hidden(t *testing.T) {
+	if _, _, err := ImportShowcase([]byte(`{"playerInfo": {"nickname": "x"}, "uid": "1"}`)); err == nil {
+		t.Error("expected error for profile without avatarInfoList")
+	}
+}
+
+func TestShowcaseIDTables(t *testing.T) {
+	for id, key := range AvatarIDs {
+		if _, ok := CharacterNames[key]; !ok {
+			t.Errorf("AvatarIDs[%d] = %q 不在 CharacterNames 中", id, key)
+		}
+	}
+	for id, key := range WeaponIDs {
+		if _, ok := WeaponNames[key]; !ok {
+			t.Errorf("WeaponIDs[%d] = %q 不在 WeaponNames 中", id, key)
+		}
+	}
+}
+
+func TestShowcaseIDTables_CoverRules(t *testing.T) {
+	avatars := make(map[string]bool)
+	for _, key := range AvatarIDs {
+		avatars[CharacterNames[key]] = true
+	}
+	weapons := make(map[string]bool)
+	for _, key := range WeaponIDs {
+		weapons[WeaponNames[key]] = true
+	}
+	r := builtinRules(t)
+	for name := range r.Characters {
+		if !avatars[name] {
+			t.Errorf("角色 %s 无法由 AvatarIDs 得到", name)
+		}
+	}
+	for name := range r.Weapons {
+		if !weapons[name] {
+			t.Errorf("武器 %s 无法由 WeaponIDs 得到", name)
+		}
+	}
+}
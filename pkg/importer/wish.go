@@ -0,0 +1,445 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+// WishRecord 是一条祈愿记录
+type WishRecord struct {
+	GachaType string // "100" 新手、"200" 常驻、"301"/"400" 角色活动、"302" 武器活动、"500" 集录
+	Time      time.Time
+	Name      string
+	ItemType  string // "角色"、"武器"
+	Rank      int
+	ID        string
+}
+
+// 祈愿类型
+const (
+	wishBeginner   = "100"
+	wishStandard   = "200"
+	wishCharacter  = "301"
+	wishCharacter2 = "400"
+	wishWeapon     = "302"
+	wishChronicle  = "500"
+)
+
+// 祈愿记录导出中中文祈愿类型名对应的类型编号
+var wishTypeNames = map[string]string{
+	"新手祈愿":     wishBeginner,
+	"常驻祈愿":     wishStandard,
+	"角色活动祈愿":   wishCharacter,
+	"角色活动祈愿-2": wishCharacter2,
+	"武器活动祈愿":   wishWeapon,
+	"集录祈愿":     wishChronicle,
+}
+
+// StandardFiveStars 是常驻五星角色，角色活动祈愿中抽到它们即为歪了，下一个五星必为UP角色
+var StandardFiveStars = nameSet(standardFiveStarNames)
+
+// fiveStars 是全部五星角色
+var fiveStars = nameSet(limitedFiveStarNames, standardFiveStarNames)
+
+// nameSet 返回名称表中的中文名集合
+func nameSet(tables ...map[string]string) map[string]bool {
+	set := make(map[string]bool)
+	for _, t := range tables {
+		for _, name := range t {
+			set[name] = true
+		}
+	}
+	return set
+}
+
+// StandardFiveStarWeapons 是常驻五星武器，武器活动祈愿中抽到它们即为歪了
+var StandardFiveStarWeapons = map[string]bool{
+	"风鹰剑": true, "天空之刃": true, "天空之傲": true, "狼的末路": true, "天空之脊": true,
+	"和璞鸢": true, "天空之卷": true, "四风原典": true, "天空之翼": true, "阿莫斯之弓": true,
+}
+
+const wishTimeLayout = "2006-01-02 15:04:05"
+
+// uigfRecord 是 UIGF 格式中的一条记录，各字段均为字符串
+type uigfRecord struct {
+	GachaType string `json:"gacha_type"`
+	Time      string `json:"time"`
+	Name      string `json:"name"`
+	ItemType  string `json:"item_type"`
+	RankType  string `json:"rank_type"`
+	ID        string `json:"id"`
+}
+
+// uigfExport 兼容 UIGF v2/v3 (顶层 list) 与 v4 (hk4e 数组) 两种结构
+type uigfExport struct {
+	Info struct {
+		UID         string `json:"uid"`
+		UIGFVersion string `json:"uigf_version"`
+		Version     string `json:"version"`
+		ExportApp   string `json:"export_app"`
+	} `json:"info"`
+	List []uigfRecord `json:"list"`
+	HK4E []struct {
+		UID  json.RawMessage `json:"uid"`
+		List []uigfRecord    `json:"list"`
+	} `json:"hk4e"`
+}
+
+// ParseWishHistory 解析祈愿记录导出，支持 UIGF JSON 与带表头的 CSV，返回按时间排序的记录
+func ParseWishHistory(data []byte) ([]WishRecord, string, error) {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return parseWishJSON(trimmed)
+	}
+	return parseWishCSV(trimmed)
+}
+
+func parseWishJSON(data []byte) ([]WishRecord, string, error) {
+	var export uigfExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, "", fmt.Errorf("解析祈愿记录 JSON 失败: %w", err)
+	}
+	raw := export.List
+	uid := export.Info.UID
+	version := export.Info.UIGFVersion
+	if len(export.HK4E) > 0 {
+		raw = export.HK4E[0].List
+		uid = strings.Trim(string(export.HK4E[0].UID), `"`)
+		version = export.Info.Version
+	}
+	if raw == nil {
+		return nil, "", fmt.Errorf("祈愿记录 JSON 中没有记录列表")
+	}
+
+	records := make([]WishRecord, 0, len(raw))
+	for i, r := range raw {
+		rec, err := newWishRecord(r.GachaType, r.Time, r.Name, r.ItemType, r.RankType, r.ID)
+		if err != nil {
+			return nil, "", fmt.Errorf("第 %d 条记录: %w", i+1, err)
+		}
+		records = append(records, rec)
+	}
+	sortWishRecords(records)
+	source := "祈愿记录 UIGF " + version
+	if uid != "" {
+		source += " (" + uid + ")"
+	}
+	return records, source, nil
+}
+
+// wishCSVColumns 是 CSV 表头中各字段可能的列名
+var wishCSVColumns = map[string][]string{
+	"gacha_type": {"gacha_type", "祈愿类型", "卡池类型"},
+	"time":       {"time", "时间"},
+	"name":       {"name", "名称"},
+	"item_type":  {"item_type", "类别", "物品类型"},
+	"rank_type":  {"rank_type", "星级"},
+	"id":         {"id"},
+}
+
+func parseWishCSV(data []byte) ([]WishRecord, string, error) {
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, "", fmt.Errorf("解析祈愿记录 CSV 失败: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, "", fmt.Errorf("祈愿记录 CSV 为空")
+	}
+
+	index := make(map[string]int)
+	for i, h := range rows[0] {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for field, names := range wishCSVColumns {
+			for _, name := range names {
+				if h == name {
+					index[field] = i
+				}
+			}
+		}
+	}
+	for _, field := range []string{"gacha_type", "time", "name", "item_type", "rank_type"} {
+		if _, ok := index[field]; !ok {
+			return nil, "", fmt.Errorf("祈愿记录 CSV 缺少列 %s (可用列名: %s)", field, strings.Join(wishCSVColumns[field], "/"))
+		}
+	}
+	cell := func(row []string, field string) string {
+		if i, ok := index[field]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	records := make([]WishRecord, 0, len(rows)-1)
+	for n, row := range rows[1:] {
+		rec, err := newWishRecord(cell(row, "gacha_type"), cell(row, "time"), cell(row, "name"),
+			cell(row, "item_type"), cell(row, "rank_type"), cell(row, "id"))
+		if err != nil {
+			return nil, "", fmt.Errorf("第 %d 行: %w", n+2, err)
+		}
+		records = append(records, rec)
+	}
+	sortWishRecords(records)
+	return records, "祈愿记录 CSV", nil
+}
+
+// newWishRecord 校验并转换一条记录的各字段
+func newWishRecord(gachaType, t, name, itemType, rank, id string) (WishRecord, error) {
+	if code, ok := wishTypeNames[gachaType]; ok {
+		gachaType = code
+	}
+	switch gachaType {
+	case wishBeginner, wishStandard, wishCharacter, wishCharacter2, wishWeapon, wishChronicle:
+	default:
+		return WishRecord{}, fmt.Errorf("未知的祈愿类型 %q", gachaType)
+	}
+	ts, err := time.Parse(wishTimeLayout, t)
+	if err != nil {
+		return WishRecord{}, fmt.Errorf("时间 %q 格式错误", t)
+	}
+	r, err := strconv.Atoi(rank)
+	if err != nil || r < 3 || r > 5 {
+		return WishRecord{}, fmt.Errorf("星级 %q 无效", rank)
+	}
+	switch itemType {
+	case "Character":
+		itemType = "角色"
+	case "Weapon":
+		itemType = "武器"
+	}
+	return WishRecord{GachaType: gachaType, Time: ts, Name: name, ItemType: itemType, Rank: r, ID: id}, nil
+}
+
+// sortWishRecords 按时间排序，同一时间的十连按 ID 排序
+func sortWishRecords(records []WishRecord) {
+	sort.SliceStable(records, func(i, j int) bool {
+		if !records[i].Time.Equal(records[j].Time) {
+			return records[i].Time.Before(records[j].Time)
+		}
+		if len(records[i].ID) != len(records[j].ID) {
+			return len(records[i].ID) < len(records[j].ID)
+		}
+		return records[i].ID < records[j].ID
+	})
+}
+
+// goodKey 将英文名转为 GOOD 格式的键，如 "Kagura's Verity" → "KagurasVerity"
+func goodKey(name string) string {
+	name = strings.NewReplacer("'", "", "’", "").Replace(name)
+	var sb strings.Builder
+	upper := true
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// resolveWishName 将记录中的名称解析为估值规则中的名称，支持中文与英文记录
+func resolveWishName(rec WishRecord) (string, bool) {
+	table := CharacterNames
+	if rec.ItemType == "武器" {
+		table = WeaponNames
+	}
+	for _, name := range table {
+		if name == rec.Name {
+			return name, true
+		}
+	}
+	name, ok := table[goodKey(rec.Name)]
+	return name, ok
+}
+
+// ReplayWishes 重放祈愿记录，推算角色命座、武器精炼以及角色池与武器池的垫抽与保底状态
+// 只统计四星及以上角色与五星武器；命座与精炼只计入记录中的抽取，
+// 记录之外获得的角色 (如商店兑换、活动赠送) 无法推算；武器池定轨无法从记录得知，命定值不推算
+func ReplayWishes(records []WishRecord) (eval.Assets, *Report) {
+	account := eval.Assets{
+		Characters: make(map[string]int),
+		Weapons:    make(map[string]int),
+	}
+	report := &Report{}
+	charCopies := make(map[string]int)
+	weaponCopies := make(map[string]int)
+
+	for _, rec := range records {
+		name, mapped := resolveWishName(rec)
+		if !mapped {
+			name = rec.Name
+		}
+
+		isCharPool := rec.GachaType == wishCharacter || rec.GachaType == wishCharacter2
+		isWeaponPool := rec.GachaType == wishWeapon
+		if isCharPool {
+			account.CharacterPity++
+		}
+		if isWeaponPool {
+			account.WeaponPity++
+		}
+		if rec.Rank == 5 {
+			if isCharPool {
+				account.CharacterPity = 0
+				account.CharacterGuaranteed = StandardFiveStars[name]
+			}
+			if isWeaponPool {
+				account.WeaponPity = 0
+				account.WeaponGuaranteed = StandardFiveStarWeapons[name]
+			}
+		}
+
+		if rec.Rank < 4 || (rec.ItemType == "武器" && rec.Rank < 5) {
+			continue
+		}
+		if !mapped {
+			report.addUnmapped(rec.ItemType, rec.Name)
+			continue
+		}
+		if rec.ItemType == "武器" {
+			weaponCopies[name]++
+		} else {
+			charCopies[name]++
+		}
+	}
+
+	for _, name := range sortedUnion(charCopies, nil) {
+		n := charCopies[name]
+		account.Characters[name] = min(n-1, 6)
+		if n-1 > 6 {
+			report.warnf("角色 %s 共抽到 %d 个，溢出 %d 命", name, n, n-7)
+		}
+	}
+	for _, name := range sortedUnion(weaponCopies, nil) {
+		n := weaponCopies[name]
+		account.Weapons[name] = min(n, 5)
+		if n > 5 {
+			report.warnf("武器 %s 共抽到 %d 把，超出精炼5", name, n)
+		}
+	}
+	if len(records) > 0 {
+		report.warnf("记录时间范围 %s 至 %s，更早的抽取不在记录中",
+			records[0].Time.Format(time.DateOnly), records[len(records)-1].Time.Format(time.DateOnly))
+	}
+	report.sortUnmapped()
+	return account, report
+}
+
+// ImportWishHistory 解析祈愿记录导出并重放，得到推算的账号资产
+func ImportWishHistory(data []byte) (eval.Assets, *Report, error) {
+	records, source, err := ParseWishHistory(data)
+	if err != nil {
+		return eval.Assets{}, nil, err
+	}
+	account, report := ReplayWishes(records)
+	report.Source = source
+	return account, report, nil
+}
+
+// WishDiscrepancy 是卖家声明与祈愿记录推算结果之间的一处差异
+type WishDiscrepancy struct {
+	Subject  string // 角色名、武器名或卡池状态
+	Claimed  string
+	Recorded string
+	Detail   string
+}
+
+func (d WishDiscrepancy) String() string {
+	return fmt.Sprintf("%s: 声明 %s，记录 %s (%s)", d.Subject, d.Claimed, d.Recorded, d.Detail)
+}
+
+// CompareWishClaims 比较卖家声明的账号与祈愿记录推算的账号
+// 声明高于记录可能来自记录范围之外的抽取或赠送，低于记录则声明有误；
+// 四星角色可从商店等途径获得，只比较五星角色
+func CompareWishClaims(recorded, claimed eval.Assets) []WishDiscrepancy {
+	var diffs []WishDiscrepancy
+	describeChar := func(m map[string]int, name string) string {
+		if c, ok := m[name]; ok {
+			return fmt.Sprintf("%d命", c)
+		}
+		return "未拥有"
+	}
+	describeWeapon := func(m map[string]int, name string) string {
+		if r, ok := m[name]; ok {
+			return fmt.Sprintf("精%d", r)
+		}
+		return "未拥有"
+	}
+
+	for _, name := range sortedUnion(recorded.Characters, claimed.Characters) {
+		if !fiveStars[name] {
+			continue
+		}
+		rc, rok := recorded.Characters[name]
+		cc, cok := claimed.Characters[name]
+		if rok == cok && rc == cc {
+			continue
+		}
+		detail := "声明低于记录"
+		if !rok || (cok && cc > rc) {
+			detail = "声明高于记录，可能来自记录范围之外"
+		}
+		diffs = append(diffs, WishDiscrepancy{Subject: name, Claimed: describeChar(claimed.Characters, name),
+			Recorded: describeChar(recorded.Characters, name), Detail: detail})
+	}
+	for _, name := range sortedUnion(recorded.Weapons, claimed.Weapons) {
+		rr, rok := recorded.Weapons[name]
+		cr, cok := claimed.Weapons[name]
+		if rok == cok && rr == cr {
+			continue
+		}
+		detail := "声明低于记录"
+		if !rok || (cok && cr > rr) {
+			detail = "声明高于记录，可能来自记录范围之外"
+		}
+		diffs = append(diffs, WishDiscrepancy{Subject: name, Claimed: describeWeapon(claimed.Weapons, name),
+			Recorded: describeWeapon(recorded.Weapons, name), Detail: detail})
+	}
+
+	if claimed.CharacterPity != recorded.CharacterPity {
+		diffs = append(diffs, WishDiscrepancy{Subject: "角色池垫抽", Claimed: strconv.Itoa(claimed.CharacterPity),
+			Recorded: strconv.Itoa(recorded.CharacterPity), Detail: "垫抽数不一致"})
+	}
+	if claimed.CharacterGuaranteed != recorded.CharacterGuaranteed {
+		diffs = append(diffs, WishDiscrepancy{Subject: "角色池大保底", Claimed: strconv.FormatBool(claimed.CharacterGuaranteed),
+			Recorded: strconv.FormatBool(recorded.CharacterGuaranteed), Detail: "保底状态不一致"})
+	}
+	if claimed.WeaponPity != recorded.WeaponPity {
+		diffs = append(diffs, WishDiscrepancy{Subject: "武器池垫抽", Claimed: strconv.Itoa(claimed.WeaponPity),
+			Recorded: strconv.Itoa(recorded.WeaponPity), Detail: "垫抽数不一致"})
+	}
+	if claimed.WeaponGuaranteed != recorded.WeaponGuaranteed {
+		diffs = append(diffs, WishDiscrepancy{Subject: "武器池保底", Claimed: strconv.FormatBool(claimed.WeaponGuaranteed),
+			Recorded: strconv.FormatBool(recorded.WeaponGuaranteed), Detail: "保底状态不一致"})
+	}
+	return diffs
+}
+
+// sortedUnion 返回两个 map 键的并集，按名称排序
+func sortedUnion(a, b map[string]int) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	var names []string
+	for _, m := range []map[string]int{a, b} {
+		for name := range m {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
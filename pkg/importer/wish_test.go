@@ -0,0 +1,152 @@
+package importer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+)
+
+// wishLog 按顺序生成祈愿记录，每条间隔一秒
+type wishLog struct {
+	t       time.Time
+	records []WishRecord
+}
+
+func (l *wishLog) pull(gachaType string, n int, name, itemType string, rank int) {
+	for i := 0; i < n; i++ {
+		l.t = l.t.Add(time.Second)
+		l.records = append(l.records, WishRecord{GachaType: gachaType, Time: l.t, Name: name, ItemType: itemType, Rank: rank})
+	}
+}
+
+func TestReplayWishes(t *testing.T) {
+	l := &wishLog{t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+	l.pull("301", 10, "黎明神剑", "武器", 3)
+	l.pull("301", 1, "刻晴", "角色", 5)
+	l.pull("301", 3, "班尼特", "角色", 4)
+	l.pull("301", 1, "玛薇卡", "角色", 5)
+	l.pull("302", 1, "焚曜千阳", "武器", 5)
+	l.pull("302", 1, "天空之刃", "武器", 5)
+	l.pull("302", 4, "黎明神剑", "武器", 3)
+	l.pull("400", 2, "黎明神剑", "武器", 3)
+	l.pull("400", 1, "玛薇卡", "角色", 5)
+	l.pull("301", 2, "黎明神剑", "武器", 3)
+	l.pull("200", 5, "黎明神剑", "武器", 3)
+
+	account, report := ReplayWishes(l.records)
+	want := eval.Assets{
+		Characters:          map[string]int{"刻晴": 0, "班尼特": 2, "玛薇卡": 1},
+		Weapons:             map[string]int{"焚曜千阳": 1},
+		CharacterPity:       2,
+		CharacterGuaranteed: false,
+		WeaponPity:          4,
+		WeaponGuaranteed:    true,
+	}
+	if !reflect.DeepEqual(account, want) {
+		t.Errorf("ReplayWishes =\n%+v\nwant\n%+v", account, want)
+	}
+	if len(report.Unmapped) != 1 || report.Unmapped[0].Key != "天空之刃" {
+		t.Errorf("Unmapped = %v, want [天空之刃]", report.Unmapped)
+	}
+
+	// 最后一个五星歪了常驻，进入大保底
+	l.pull("301", 1, "迪卢克", "角色", 5)
+	if account, _ := ReplayWishes(l.records); !account.CharacterGuaranteed || account.CharacterPity != 0 {
+		t.Errorf("after 迪卢克: pity %d guaranteed %v, want 0 true", account.CharacterPity, account.CharacterGuaranteed)
+	}
+}
+
+func TestImportWishHistory_UIGF(t *testing.T) {
+	data := `{
+  "info": {"uid": "100000001", "lang": "zh-cn", "uigf_version": "v2.3", "export_app": "test"},
+  "list": [
+    {"gacha_type": "301", "time": "2025-01-01 00:00:02", "name": "玛薇卡", "item_type": "角色", "rank_type": "5", "id": "1002"},
+    {"gacha_type": "301", "time": "2025-01-01 00:00:01", "name": "黎明神剑", "item_type": "武器", "rank_type": "3", "id": "1001"},
+    {"gacha_type": "301", "time": "2025-01-01 00:00:03", "name": "黎明神剑", "item_type": "武器", "rank_type": "3", "id": "1003"},
+    {"gacha_type": "400", "time": "2025-01-01 00:00:04", "name": "新角色", "item_type": "角色", "rank_type": "5", "id": "1004"}
+  ]
+}`
+	account, report, err := ImportWishHistory([]byte(data))
+	if err != nil {
+		t.Fatalf("ImportWishHistory: %v", err)
+	}
+	if account.Characters["玛薇卡"] != 0 || account.CharacterPity != 0 {
+		t.Errorf("account = %+v", account)
+	}
+	if report.Source != "祈愿记录 UIGF v2.3 (100000001)" {
+		t.Errorf("Source = %q", report.Source)
+	}
+	if len(report.Unmapped) != 1 || report.Unmapped[0].Key != "新角色" {
+		t.Errorf("Unmapped = %v", report.Unmapped)
+	}
+}
+
+func TestImportWishHistory_UIGFv4English(t *testing.T) {
+	data := `{
+  "info": {"export_timestamp": 1735689600, "export_app": "test", "version": "v4.0"},
+  "hk4e": [{"uid": 100000001, "lang": "en-us", "list": [
+    {"gacha_type": "302", "time": "2025-01-01 00:00:01", "name": "A Thousand Blazing Suns", "item_type": "Weapon", "rank_type": "5", "id": "1"},
+    {"gacha_type": "302", "time": "2025-01-01 00:00:02", "name": "A Thousand Blazing Suns", "item_type": "Weapon", "rank_type": "5", "id": "2"},
+    {"gacha_type": "301", "time": "2025-01-01 00:00:03", "name": "Hu Tao", "item_type": "Character", "rank_type": "5", "id": "3"}
+  ]}]
+}`
+	account, _, err := ImportWishHistory([]byte(data))
+	if err != nil {
+		t.Fatalf("ImportWishHistory: %v", err)
+	}
+	if account.Weapons["焚曜千阳"] != 2 || account.Characters["胡桃"] != 0 || len(account.Characters) != 1 {
+		t.Errorf("account = %+v", account)
+	}
+}
+
+func TestImportWishHistory_CSV(t *testing.T) {
+	data := "时间,名称,类别,星级,祈愿类型\n" +
+		"2025-01-01 00:00:01,玛薇卡,角色,5,角色活动祈愿\n" +
+		"2025-01-01 00:00:02,黎明神剑,武器,3,角色活动祈愿-2\n" +
+		"2025-01-01 00:00:03,玛薇卡,角色,5,角色活动祈愿\n"
+	account, report, err := ImportWishHistory([]byte(data))
+	if err != nil {
+		t.Fatalf("ImportWishHistory: %v", err)
+	}
+	if account.Characters["玛薇卡"] != 1 || report.Source != "祈愿记录 CSV" {
+		t.Errorf("account = %+v, source %q", account, report.Source)
+	}
+
+	_, _, err = ImportWishHistory([]byte("时间,名称\n2025-01-01 00:00:01,玛薇卡\n"))
+	if err == nil || !strings.Contains(err.Error(), "缺少列") {
+		t.Errorf("err = %v, want missing column error", err)
+	}
+	_, _, err = ImportWishHistory([]byte("时间,名称,类别,星级,祈愿类型\n2025-01-01,玛薇卡,角色,5,角色活动祈愿\n"))
+	if err == nil || !strings.Contains(err.Error(), "第 2 行") {
+		t.Errorf("err = %v, want row error", err)
+	}
+}
+
+func TestCompareWishClaims(t *testing.T) {
+	recorded := eval.Assets{
+		Characters:    map[string]int{"玛薇卡": 1, "班尼特": 2, "刻晴": 0},
+		Weapons:       map[string]int{"焚曜千阳": 1},
+		CharacterPity: 10,
+	}
+	claimed := eval.Assets{
+		Characters:    map[string]int{"玛薇卡": 2, "班尼特": 6, "茜特菈莉": 0},
+		Weapons:       map[string]int{"焚曜千阳": 1},
+		CharacterPity: 10,
+	}
+	var got []string
+	for _, d := range CompareWishClaims(recorded, claimed) {
+		got = append(got, d.String())
+	}
+	// 四星角色班尼特不比较
+	want := []string{
+		"刻晴: 声明 未拥有，记录 0命 (声明低于记录)",
+		"玛薇卡: 声明 2命，记录 1命 (声明高于记录，可能来自记录范围之外)",
+		"茜特菈莉: 声明 0命，记录 未拥有 (声明高于记录，可能来自记录范围之外)",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CompareWishClaims =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
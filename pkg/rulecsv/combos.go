@@ -0,0 +1,320 @@
+package rulecsv
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval/newrule"
+)
+
+// 组合表的列，列表类单元格中的多项以空格分隔:
+//   - 角色要求: 沿用组合名称中的写法，如 "6玛薇卡 2-6茜特菈莉 希诺宁" (不写命座表示0-6命)
+//   - 替代要求组: "需满足数量:选项/选项"，多组以 ";" 分隔，如 "1:6丝柯克/6爱可菲"
+//   - 属性要求: "数量=3 命座=6 地区=纳塔"，可用键为 数量、命座、地区、元素、武器类型、版本，多条以 ";" 分隔
+//   - 武器要求: 如 "精5焚曜千阳 精1-5苍耀"
+//   - 武器乘数: 如 "焚曜千阳=2"
+var comboHeader = withProvenance("名称", "分组", "价值", "角色要求", "替代要求组", "属性要求", "武器要求", "武器乘数", "条件", "可共享角色", "共享折扣")
+
+var (
+	charReqPattern   = regexp.MustCompile(`^(?:(\d)(?:-(\d))?)?(\D.*)$`)
+	weaponReqPattern = regexp.MustCompile(`^精(\d)(?:-(\d))?(.+)$`)
+)
+
+// formatChars 以空格连接角色要求
+func formatChars(reqs []newrule.RequiredChar) string {
+	parts := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		parts = append(parts, req.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatGroups(groups []newrule.RequirementGroup) string {
+	parts := make([]string, 0, len(groups))
+	for _, g := range groups {
+		opts := make([]string, 0, len(g.Options))
+		for _, opt := range g.Options {
+			opts = append(opts, opt.String())
+		}
+		parts = append(parts, fmt.Sprintf("%d:%s", g.MinMatch, strings.Join(opts, "/")))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func formatConstRange(lo, hi int) string {
+	if lo == hi {
+		return strconv.Itoa(lo)
+	}
+	return fmt.Sprintf("%d-%d", lo, hi)
+}
+
+func formatAttributes(attrs []newrule.AttributeRequirement) string {
+	parts := make([]string, 0, len(attrs))
+	for _, a := range attrs {
+		fields := []string{"数量=" + strconv.Itoa(a.Count), "命座=" + formatConstRange(a.MinConst, a.MaxConst)}
+		for _, kv := range [][2]string{{"地区", a.Region}, {"元素", a.Element}, {"武器类型", a.WeaponType}, {"版本", a.Version}} {
+			if kv[1] != "" {
+				fields = append(fields, kv[0]+"="+kv[1])
+			}
+		}
+		parts = append(parts, strings.Join(fields, " "))
+	}
+	return strings.Join(parts, "; ")
+}
+
+func formatWeapons(reqs []newrule.RequiredWeapon) string {
+	parts := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		parts = append(parts, req.String())
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatMultipliers(m map[string]float64) string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, name+"="+formatFloat(m[name]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// ExportCombos 导出组合表，保持规则中的顺序
+func ExportCombos(w io.Writer, combos []newrule.ComboRule) error {
+	records := make([][]string, 0, len(combos))
+	for _, c := range combos {
+		discount := ""
+		if c.SharingDiscount != 0 {
+			discount = formatFloat(c.SharingDiscount)
+		}
+		rec := []string{
+			c.Name, c.Group, formatFloat(c.Value),
+			formatChars(c.RequiredChars), formatGroups(c.RequiredGroups), formatAttributes(c.RequiredAttributes),
+			formatWeapons(c.RequiredWeapons), formatMultipliers(c.WeaponMultipliers),
+			c.Condition, strings.Join(c.ShareableChars, " "), discount,
+		}
+		records = append(records, append(rec, provenanceCells(c.Provenance)...))
+	}
+	return writeTable(w, comboHeader, records)
+}
+
+// parseConstRange 解析 "6" 或 "2-5" 形式的命座范围
+func parseConstRange(s string) (int, int, error) {
+	loText, hiText, isRange := strings.Cut(s, "-")
+	lo, err := strconv.Atoi(loText)
+	if err != nil {
+		return 0, 0, fmt.Errorf("命座 %q 无效", s)
+	}
+	hi := lo
+	if isRange {
+		if hi, err = strconv.Atoi(hiText); err != nil {
+			return 0, 0, fmt.Errorf("命座 %q 无效", s)
+		}
+	}
+	if lo < 0 || lo > hi || hi > 6 {
+		return 0, 0, fmt.Errorf("命座范围 %q 无效", s)
+	}
+	return lo, hi, nil
+}
+
+// parseCharReq 解析 "6玛薇卡"、"2-5丝柯克"、"希诺宁" 形式的角色要求
+func parseCharReq(s string) (newrule.RequiredChar, error) {
+	m := charReqPattern.FindStringSubmatch(s)
+	if m == nil {
+		return newrule.RequiredChar{}, fmt.Errorf("角色要求 %q 无效", s)
+	}
+	req := newrule.RequiredChar{Name: m[3], MinConst: 0, MaxConst: 6}
+	if m[1] != "" {
+		req.MinConst, _ = strconv.Atoi(m[1])
+		req.MaxConst = req.MinConst
+		if m[2] != "" {
+			req.MaxConst, _ = strconv.Atoi(m[2])
+		}
+	}
+	if req.MinConst > req.MaxConst || req.MaxConst > 6 {
+		return req, fmt.Errorf("角色要求 %q 的命座范围无效", s)
+	}
+	return req, nil
+}
+
+func parseChars(s string) ([]newrule.RequiredChar, error) {
+	var reqs []newrule.RequiredChar
+	for _, f := range strings.Fields(s) {
+		req, err := parseCharReq(f)
+		if err != nil {
+			return nil, err
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
+func parseGroups(s string) ([]newrule.RequirementGroup, error) {
+	var groups []newrule.RequirementGroup
+	for _, part := range splitList(s) {
+		n, opts, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("替代要求组 %q 缺少 \"数量:\" 前缀", part)
+		}
+		g := newrule.RequirementGroup{}
+		var err error
+		if g.MinMatch, err = strconv.Atoi(strings.TrimSpace(n)); err != nil || g.MinMatch < 1 {
+			return nil, fmt.Errorf("替代要求组 %q 的数量无效", part)
+		}
+		for _, opt := range strings.Split(opts, "/") {
+			req, err := parseCharReq(strings.TrimSpace(opt))
+			if err != nil {
+				return nil, err
+			}
+			g.Options = append(g.Options, req)
+		}
+		if g.MinMatch > len(g.Options) {
+			return nil, fmt.Errorf("替代要求组 %q 需满足 %d 项，但只有 %d 个选项", part, g.MinMatch, len(g.Options))
+		}
+		groups = append(groups, g)
+	}
+	return groups, nil
+}
+
+func parseAttributes(s string) ([]newrule.AttributeRequirement, error) {
+	var attrs []newrule.AttributeRequirement
+	for _, part := range splitList(s) {
+		a := newrule.AttributeRequirement{MinConst: 0, MaxConst: 6, Count: 1}
+		for _, f := range strings.Fields(part) {
+			key, value, ok := strings.Cut(f, "=")
+			if !ok {
+				return nil, fmt.Errorf("属性要求 %q 应为 键=值", f)
+			}
+			var err error
+			switch key {
+			case "数量":
+				a.Count, err = strconv.Atoi(value)
+				if err == nil && a.Count < 1 {
+					err = fmt.Errorf("数量必须为正")
+				}
+			case "命座":
+				a.MinConst, a.MaxConst, err = parseConstRange(value)
+			case "地区":
+				a.Region = value
+			case "元素":
+				a.Element = value
+			case "武器类型":
+				a.WeaponType = value
+			case "版本":
+				a.Version = value
+			default:
+				err = fmt.Errorf("未知的属性 %q", key)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("属性要求 %q: %v", f, err)
+			}
+		}
+		attrs = append(attrs, a)
+	}
+	return attrs, nil
+}
+
+func parseWeapons(s string) ([]newrule.RequiredWeapon, error) {
+	var reqs []newrule.RequiredWeapon
+	for _, f := range strings.Fields(s) {
+		m := weaponReqPattern.FindStringSubmatch(f)
+		if m == nil {
+			return nil, fmt.Errorf("武器要求 %q 应为 \"精5武器名\" 或 \"精1-5武器名\"", f)
+		}
+		req := newrule.RequiredWeapon{Name: m[3]}
+		req.MinRefine, _ = strconv.Atoi(m[1])
+		req.MaxRefine = req.MinRefine
+		if m[2] != "" {
+			req.MaxRefine, _ = strconv.Atoi(m[2])
+		}
+		if req.MinRefine < 1 || req.MinRefine > req.MaxRefine || req.MaxRefine > 5 {
+			return nil, fmt.Errorf("武器要求 %q 的精炼范围无效", f)
+		}
+		reqs = append(reqs, req)
+	}
+	return reqs, nil
+}
+
+func parseMultipliers(s string) (map[string]float64, error) {
+	var m map[string]float64
+	for _, f := range strings.Fields(s) {
+		name, value, ok := strings.Cut(f, "=")
+		v, err := strconv.ParseFloat(value, 64)
+		if !ok || err != nil || name == "" {
+			return nil, fmt.Errorf("武器乘数 %q 应为 \"武器名=乘数\"", f)
+		}
+		if m == nil {
+			m = make(map[string]float64)
+		}
+		m[name] = v
+	}
+	return m, nil
+}
+
+// splitList 按 ";" 分隔单元格中的多项，忽略空项
+func splitList(s string) []string {
+	var parts []string
+	for _, p := range strings.Split(s, ";") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return parts
+}
+
+// ImportCombos 导入组合表，每个组合都经过 newrule.ValidateRules 校验，错误报告在组合所在的行
+// 组合名称允许重复 (同名组合在最优方案中至多选用一个)，可用 newrule.LintCombos 检查
+func ImportCombos(r io.Reader) ([]newrule.ComboRule, error) {
+	t, rows := readTable(CombosFile, r, comboHeader[:4])
+	var combos []newrule.ComboRule
+	for _, row := range rows {
+		before := len(t.errs)
+		c := newrule.ComboRule{
+			Name:            row.str("名称"),
+			Group:           row.str("分组"),
+			Value:           row.float("价值"),
+			Condition:       row.str("条件"),
+			ShareableChars:  strings.Fields(row.str("可共享角色")),
+			SharingDiscount: row.float("共享折扣"),
+			Provenance:      row.provenance(),
+		}
+		var err error
+		if c.RequiredChars, err = parseChars(row.str("角色要求")); err != nil {
+			row.fail("角色要求", "%v", err)
+		}
+		if c.RequiredGroups, err = parseGroups(row.str("替代要求组")); err != nil {
+			row.fail("替代要求组", "%v", err)
+		}
+		if c.RequiredAttributes, err = parseAttributes(row.str("属性要求")); err != nil {
+			row.fail("属性要求", "%v", err)
+		}
+		if c.RequiredWeapons, err = parseWeapons(row.str("武器要求")); err != nil {
+			row.fail("武器要求", "%v", err)
+		}
+		if c.WeaponMultipliers, err = parseMultipliers(row.str("武器乘数")); err != nil {
+			row.fail("武器乘数", "%v", err)
+		}
+		if c.Name == "" {
+			row.fail("名称", "组合名称不能为空")
+		}
+		if len(c.RequiredChars)+len(c.RequiredGroups)+len(c.RequiredAttributes) == 0 && len(t.errs) == before {
+			row.fail("角色要求", "组合没有任何角色要求")
+		}
+		if len(t.errs) > before {
+			continue
+		}
+		for _, err := range newrule.ValidateRules(newrule.ValuationRules{Combos: []newrule.ComboRule{c}}) {
+			row.fail("", "%v", err)
+		}
+		combos = append(combos, c)
+	}
+	return combos, errorOrNil(t.errs)
+}
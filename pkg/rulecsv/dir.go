@@ -0,0 +1,105 @@
+package rulecsv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval/newrule"
+)
+
+// ExportDir 将规则中的各张表导出到目录
+func ExportDir(dir string, rules newrule.ValuationRules) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tables := []struct {
+		file  string
+		write func(w io.Writer) error
+	}{
+		{CharactersFile, func(w io.Writer) error { return ExportCharacters(w, rules.Characters) }},
+		{WeaponsFile, func(w io.Writer) error { return ExportWeapons(w, rules.Weapons) }},
+		{CombosFile, func(w io.Writer) error { return ExportCombos(w, rules.Combos) }},
+		{CharCountTiersFile, func(w io.Writer) error { return ExportCharCountTiers(w, rules.CharCountMultiplierTiers) }},
+		{ResourceTiersFile, func(w io.Writer) error { return ExportResourceTiers(w, rules.ResourceValueTiers) }},
+	}
+	for _, t := range tables {
+		f, err := os.Create(filepath.Join(dir, t.file))
+		if err != nil {
+			return err
+		}
+		err = t.write(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportDir 从目录导入各张表，替换 base 中对应的部分；目录中不存在的表沿用 base
+// 全部表的行错误合并为一个 *ImportError 返回；导入后的规则须通过 newrule.ValidateRules 校验
+func ImportDir(dir string, base newrule.ValuationRules) (newrule.ValuationRules, error) {
+	rules := base
+	var rowErrs []RowError
+	var otherErr error
+
+	importTable := func(file string, load func(r io.Reader) error) {
+		f, err := os.Open(filepath.Join(dir, file))
+		if errors.Is(err, fs.ErrNotExist) {
+			return
+		}
+		if err != nil {
+			otherErr = errors.Join(otherErr, err)
+			return
+		}
+		defer f.Close()
+		if err := load(f); err != nil {
+			var ie *ImportError
+			if errors.As(err, &ie) {
+				rowErrs = append(rowErrs, ie.Rows...)
+			} else {
+				otherErr = errors.Join(otherErr, err)
+			}
+		}
+	}
+
+	importTable(CharactersFile, func(r io.Reader) (err error) {
+		rules.Characters, err = ImportCharacters(r)
+		return err
+	})
+	importTable(WeaponsFile, func(r io.Reader) (err error) {
+		rules.Weapons, err = ImportWeapons(r)
+		return err
+	})
+	importTable(CombosFile, func(r io.Reader) (err error) {
+		if rules.Combos, err = ImportCombos(r); err == nil {
+			newrule.SortCombos(rules.Combos)
+		}
+		return err
+	})
+	importTable(CharCountTiersFile, func(r io.Reader) (err error) {
+		rules.CharCountMultiplierTiers, err = ImportCharCountTiers(r)
+		return err
+	})
+	importTable(ResourceTiersFile, func(r io.Reader) (err error) {
+		rules.ResourceValueTiers, err = ImportResourceTiers(r)
+		return err
+	})
+
+	if otherErr != nil {
+		return base, otherErr
+	}
+	if len(rowErrs) > 0 {
+		return base, &ImportError{Rows: rowErrs}
+	}
+	if errs := newrule.ValidateRules(rules); len(errs) > 0 {
+		return base, fmt.Errorf("导入的规则校验失败: %w", errors.Join(errs...))
+	}
+	return rules, nil
+}
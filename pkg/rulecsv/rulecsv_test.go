@@ -0,0 +1,194 @@
+package rulecsv
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval/newrule"
+)
+
+func TestDirRoundTrip(t *testing.T) {
+	rules := newrule.New().Rules()
+	dir := t.TempDir()
+	if err := ExportDir(dir, rules); err != nil {
+		t.Fatalf("ExportDir: %v", err)
+	}
+	got, err := ImportDir(dir, newrule.ValuationRules{})
+	if err != nil {
+		t.Fatalf("ImportDir: %v", err)
+	}
+	if !reflect.DeepEqual(got.Characters, rules.Characters) {
+		t.Error("characters differ after round trip")
+	}
+	if !reflect.DeepEqual(got.Weapons, rules.Weapons) {
+		t.Error("weapons differ after round trip")
+	}
+	if !reflect.DeepEqual(got.CharCountMultiplierTiers, rules.CharCountMultiplierTiers) ||
+		!reflect.DeepEqual(got.ResourceValueTiers, rules.ResourceValueTiers) {
+		t.Error("tiers differ after round trip")
+	}
+	// 空列表导入后为 nil，按导出的 CSV 比较组合
+	var want, again bytes.Buffer
+	if err := ExportCombos(&want, rules.Combos); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExportCombos(&again, got.Combos); err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Combos) != len(rules.Combos) || !bytes.Equal(want.Bytes(), again.Bytes()) {
+		t.Errorf("combos differ after round trip (%d vs %d)", len(got.Combos), len(rules.Combos))
+	}
+}
+
+func TestImportCombos_Fields(t *testing.T) {
+	data := "名称,分组,价值,角色要求,替代要求组,属性要求,武器要求,武器乘数,条件,可共享角色,共享折扣\n" +
+		"测试组合,测试分组,1500,6玛薇卡 2-6茜特菈莉 希诺宁,1:6丝柯克/6爱可菲; 2:恰斯卡/基尼奇/玛拉妮,数量=2 命座=6 地区=纳塔,精5焚曜千阳 精1-5苍耀,焚曜千阳=2.5,original_resin > 0,希诺宁,0.3\n"
+	combos, err := ImportCombos(strings.NewReader(data))
+	if err == nil {
+		t.Fatal("expected error for unknown condition identifier")
+	}
+
+	data = strings.Replace(data, "original_resin > 0", "", 1)
+	combos, err = ImportCombos(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("ImportCombos: %v", err)
+	}
+	want := newrule.ComboRule{
+		Name:  "测试组合",
+		Group: "测试分组",
+		Value: 1500,
+		RequiredChars: []newrule.RequiredChar{
+			{Name: "玛薇卡", MinConst: 6, MaxConst: 6},
+			{Name: "茜特菈莉", MinConst: 2, MaxConst: 6},
+			{Name: "希诺宁", MinConst: 0, MaxConst: 6},
+		},
+		RequiredGroups: []newrule.RequirementGroup{
+			{MinMatch: 1, Options: []newrule.RequiredChar{{Name: "丝柯克", MinConst: 6, MaxConst: 6}, {Name: "爱可菲", MinConst: 6, MaxConst: 6}}},
+			{MinMatch: 2, Options: []newrule.RequiredChar{{Name: "恰斯卡", MaxConst: 6}, {Name: "基尼奇", MaxConst: 6}, {Name: "玛拉妮", MaxConst: 6}}},
+		},
+		RequiredAttributes: []newrule.AttributeRequirement{{Count: 2, MinConst: 6, MaxConst: 6, Region: "纳塔"}},
+		RequiredWeapons:    []newrule.RequiredWeapon{{Name: "焚曜千阳", MinRefine: 5, MaxRefine: 5}, {Name: "苍耀", MinRefine: 1, MaxRefine: 5}},
+		WeaponMultipliers:  map[string]float64{"焚曜千阳": 2.5},
+		ShareableChars:     []string{"希诺宁"},
+		SharingDiscount:    0.3,
+	}
+	if len(combos) != 1 || !reflect.DeepEqual(combos[0], want) {
+		t.Errorf("ImportCombos =\n%+v\nwant\n%+v", combos, want)
+	}
+}
+
+func TestImport_RowErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		load   func(string) error
+		data   string
+		errors []string
+	}{
+		{
+			name: "角色",
+			load: func(s string) error { _, err := ImportCharacters(strings.NewReader(s)); return err },
+			data: "角色,0命,1命,2命,3命,4命,5命,6命,专武\n" +
+				"玛薇卡,5,10,x,90,100,200,500,焚曜千阳\n" +
+				"\n" +
+				"玛薇卡,5,10,80,90,100,200,500,焚曜千阳\n" +
+				"玛薇卡,5,10,80,90,100,200,,焚曜千阳\n" +
+				",1,1,1,1,1,1,-1\n",
+			errors: []string{
+				`characters.csv 第2行 [2命]: "x" 不是数字`,
+				`characters.csv 第5行 [6命]: 价格不能为空`,
+				`characters.csv 第5行 [角色]: 角色 玛薇卡 重复`,
+				`characters.csv 第6行 [6命]: 价格不能为负`,
+				`characters.csv 第6行 [角色]: 角色名不能为空`,
+			},
+		},
+		{
+			name:   "缺少列",
+			load:   func(s string) error { _, err := ImportWeapons(strings.NewReader(s)); return err },
+			data:   "武器,精1,精2,精3,精4\n焚曜千阳,1,2,3,4\n",
+			errors: []string{`weapons.csv 第1行 [精5]: 缺少该列`},
+		},
+		{
+			name:   "出处",
+			load:   func(s string) error { _, err := ImportWeapons(strings.NewReader(s)); return err },
+			data:   "武器,精1,精2,精3,精4,精5,生效日期\n焚曜千阳,1,2,3,4,5,2025/1/1\n",
+			errors: []string{`weapons.csv 第2行 [生效日期]: "2025/1/1" 不是 YYYY-MM-DD 格式的日期`},
+		},
+		{
+			name: "组合",
+			load: func(s string) error { _, err := ImportCombos(strings.NewReader(s)); return err },
+			data: "名称,分组,价值,角色要求,替代要求组,可共享角色,共享折扣\n" +
+				"A,g,100,7玛薇卡,,,\n" +
+				"B,g,100,6玛薇卡,玛薇卡/希诺宁,,\n" +
+				"C,g,100,,,,\n" +
+				"D,g,100,6玛薇卡,,希诺宁,1.5\n",
+			errors: []string{
+				`combos.csv 第2行 [角色要求]: 角色要求 "7玛薇卡" 的命座范围无效`,
+				`combos.csv 第3行 [替代要求组]: 替代要求组 "玛薇卡/希诺宁" 缺少 "数量:" 前缀`,
+				`combos.csv 第4行 [角色要求]: 组合没有任何角色要求`,
+				`combos.csv 第5行: 组合 "D" 的共享折扣 1.50 超出 0-1 范围`,
+				`combos.csv 第5行: 组合 "D" 声明可共享的角色 "希诺宁" 不在组合要求中`,
+			},
+		},
+		{
+			name:   "档位",
+			load:   func(s string) error { _, err := ImportCharCountTiers(strings.NewReader(s)); return err },
+			data:   "最少角色数,最多角色数,乘数\n10,5,1.1\n1,4,abc\n",
+			errors: []string{`char_count_tiers.csv 第2行 [最多角色数]: 5 小于最少角色数 10`, `char_count_tiers.csv 第3行 [乘数]: "abc" 不是数字`, `char_count_tiers.csv 第3行 [乘数]: 乘数必须为正`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.load(tt.data)
+			var ie *ImportError
+			if !errors.As(err, &ie) {
+				t.Fatalf("err = %v, want *ImportError", err)
+			}
+			var got []string
+			for _, r := range ie.Rows {
+				got = append(got, r.Error())
+			}
+			if !reflect.DeepEqual(got, tt.errors) {
+				t.Errorf("errors =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.errors, "\n"))
+			}
+		})
+	}
+}
+
+func TestImportDir_KeepsMissingTables(t *testing.T) {
+	rules := newrule.New().Rules()
+	got, err := ImportDir(t.TempDir(), rules)
+	if err != nil {
+		t.Fatalf("ImportDir: %v", err)
+	}
+	if len(got.Combos) != len(rules.Combos) || len(got.Characters) != len(rules.Characters) {
+		t.Error("tables missing from the directory should keep the base rules")
+	}
+}
+
+func TestImportDir_Validates(t *testing.T) {
+	base := newrule.New().Rules()
+	base.SpecialRules = append(base.SpecialRules, newrule.SpecialRuleConfig{
+		Type: "expr", Name: "专武加价", Expr: `has("玛薇卡") => weapon("焚曜千阳") x2`})
+	dir := t.TempDir()
+	// 导入的武器表中没有特殊规则引用的武器，合并后的规则无法通过校验
+	if err := ExportDir(dir, newrule.ValuationRules{Weapons: map[string]newrule.WeaponInfo{"苍耀": base.Weapons["苍耀"]}}); err != nil {
+		t.Fatalf("ExportDir: %v", err)
+	}
+	for _, file := range []string{CharactersFile, CombosFile, CharCountTiersFile, ResourceTiersFile} {
+		if err := os.Remove(filepath.Join(dir, file)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := ImportDir(dir, base)
+	if err == nil || !strings.Contains(err.Error(), "焚曜千阳") {
+		t.Fatalf("ImportDir should reject rules that fail validation, got %v", err)
+	}
+	if len(got.Weapons) != len(base.Weapons) {
+		t.Error("ImportDir should return the base rules on error")
+	}
+}
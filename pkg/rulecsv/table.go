@@ -0,0 +1,178 @@
+// Package rulecsv 以便于表格软件编辑的 CSV 导入导出估值规则中的价格表、组合与分档表
+//
+// 导出的文件带 UTF-8 BOM，可直接用 Excel 打开；导入时按表头名称识别列，列的顺序不限，
+// 每一行的错误都会带上文件名、行号与列名一并报告
+package rulecsv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// RowError 是导入时某一行的错误
+type RowError struct {
+	Table  string // 文件名，如 "characters.csv"
+	Line   int
+	Column string // 为空表示整行
+	Err    string
+}
+
+func (e RowError) Error() string {
+	if e.Column == "" {
+		return fmt.Sprintf("%s 第%d行: %s", e.Table, e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s 第%d行 [%s]: %s", e.Table, e.Line, e.Column, e.Err)
+}
+
+// ImportError 汇总一次导入中的全部行错误
+type ImportError struct {
+	Rows []RowError
+}
+
+func (e *ImportError) Error() string {
+	lines := make([]string, 0, len(e.Rows)+1)
+	lines = append(lines, fmt.Sprintf("导入失败，共 %d 处错误:", len(e.Rows)))
+	for _, r := range e.Rows {
+		lines = append(lines, "  "+r.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// errorOrNil 在没有行错误时返回 nil
+func errorOrNil(rows []RowError) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return &ImportError{Rows: rows}
+}
+
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// table 是读取中的一张表
+type table struct {
+	name    string
+	columns map[string]int
+	errs    []RowError
+}
+
+// row 是表中的一行
+type row struct {
+	t     *table
+	line  int
+	cells []string
+}
+
+// readTable 读取表头与全部数据行，跳过空行；缺少必需列时只返回表头错误
+func readTable(name string, r io.Reader, required []string) (*table, []row) {
+	t := &table{name: name, columns: make(map[string]int)}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.errs = append(t.errs, RowError{Table: name, Err: err.Error()})
+		return t, nil
+	}
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		t.errs = append(t.errs, RowError{Table: name, Line: 1, Err: "无法读取表头: " + err.Error()})
+		return t, nil
+	}
+	for i, h := range header {
+		t.columns[strings.TrimSpace(h)] = i
+	}
+	for _, col := range required {
+		if _, ok := t.columns[col]; !ok {
+			t.errs = append(t.errs, RowError{Table: name, Line: 1, Column: col, Err: "缺少该列"})
+		}
+	}
+	if len(t.errs) > 0 {
+		return t, nil
+	}
+
+	var rows []row
+	for {
+		cells, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var perr *csv.ParseError
+			line := 0
+			if errors.As(err, &perr) {
+				line = perr.Line
+			}
+			t.errs = append(t.errs, RowError{Table: name, Line: line, Err: err.Error()})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if strings.TrimSpace(strings.Join(cells, "")) == "" {
+			continue
+		}
+		rows = append(rows, row{t: t, line: line, cells: cells})
+	}
+	return t, rows
+}
+
+func (r row) fail(col, format string, args ...any) {
+	r.t.errs = append(r.t.errs, RowError{Table: r.t.name, Line: r.line, Column: col, Err: fmt.Sprintf(format, args...)})
+}
+
+// str 返回单元格内容，列不存在或该行较短时为空
+func (r row) str(col string) string {
+	i, ok := r.t.columns[col]
+	if !ok || i >= len(r.cells) {
+		return ""
+	}
+	return strings.TrimSpace(r.cells[i])
+}
+
+// float 解析数值单元格，空单元格为 0
+func (r row) float(col string) float64 {
+	s := r.str(col)
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		r.fail(col, "%q 不是数字", s)
+	}
+	return v
+}
+
+// int 解析整数单元格，空单元格为 0
+func (r row) int(col string) int {
+	s := r.str(col)
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		r.fail(col, "%q 不是整数", s)
+	}
+	return v
+}
+
+// writeTable 写出带 BOM 的 CSV
+func writeTable(w io.Writer, header []string, records [][]string) error {
+	if _, err := w.Write(utf8BOM); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(records); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,204 @@
+package rulecsv
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval/newrule"
+)
+
+// 表文件名
+const (
+	CharactersFile     = "characters.csv"
+	WeaponsFile        = "weapons.csv"
+	CombosFile         = "combos.csv"
+	CharCountTiersFile = "char_count_tiers.csv"
+	ResourceTiersFile  = "resource_tiers.csv"
+)
+
+// 各表末尾是可省略的出处列
+var (
+	characterHeader = withProvenance("角色", "0命", "1命", "2命", "3命", "4命", "5命", "6命", "专武", "地区", "元素", "武器类型", "版本")
+	weaponHeader    = withProvenance("武器", "精1", "精2", "精3", "精4", "精5")
+	charTierHeader  = withProvenance("最少角色数", "最多角色数", "乘数")
+	resTierHeader   = withProvenance("最少纠缠数", "单价")
+)
+
+var provenanceHeader = []string{"来源", "章节", "生效日期", "调整前"}
+
+func withProvenance(cols ...string) []string {
+	return append(cols, provenanceHeader...)
+}
+
+func provenanceCells(p newrule.Provenance) []string {
+	return []string{p.Source, p.Section, p.EffectiveDate, p.Previous}
+}
+
+// provenance 读取出处列，生效日期须为 YYYY-MM-DD
+func (r row) provenance() newrule.Provenance {
+	p := newrule.Provenance{Source: r.str("来源"), Section: r.str("章节"), EffectiveDate: r.str("生效日期"), Previous: r.str("调整前")}
+	if p.EffectiveDate != "" {
+		if _, err := time.Parse(time.DateOnly, p.EffectiveDate); err != nil {
+			r.fail("生效日期", "%q 不是 YYYY-MM-DD 格式的日期", p.EffectiveDate)
+		}
+	}
+	return p
+}
+
+// ExportCharacters 导出角色价格表，按角色名排序
+func ExportCharacters(w io.Writer, chars map[string]newrule.CharacterInfo) error {
+	names := make([]string, 0, len(chars))
+	for name := range chars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	records := make([][]string, 0, len(names))
+	for _, name := range names {
+		info := chars[name]
+		rec := []string{name}
+		for _, p := range info.Prices {
+			rec = append(rec, formatFloat(p))
+		}
+		rec = append(rec, info.SpecializedWeapon, info.Region, info.Element, info.WeaponType, info.Version)
+		records = append(records, append(rec, provenanceCells(info.Provenance)...))
+	}
+	return writeTable(w, characterHeader, records)
+}
+
+// ImportCharacters 导入角色价格表
+func ImportCharacters(r io.Reader) (map[string]newrule.CharacterInfo, error) {
+	t, rows := readTable(CharactersFile, r, characterHeader[:8])
+	chars := make(map[string]newrule.CharacterInfo, len(rows))
+	for _, row := range rows {
+		before := len(t.errs)
+		info := newrule.CharacterInfo{
+			Name:              row.str("角色"),
+			SpecializedWeapon: row.str("专武"),
+			Region:            row.str("地区"),
+			Element:           row.str("元素"),
+			WeaponType:        row.str("武器类型"),
+			Version:           row.str("版本"),
+			Provenance:        row.provenance(),
+		}
+		for c := range info.Prices {
+			col := characterHeader[1+c]
+			if row.str(col) == "" {
+				row.fail(col, "价格不能为空")
+			}
+			if info.Prices[c] = row.float(col); info.Prices[c] < 0 {
+				row.fail(col, "价格不能为负")
+			}
+		}
+		if info.Name == "" {
+			row.fail("角色", "角色名不能为空")
+		} else if _, dup := chars[info.Name]; dup {
+			row.fail("角色", "角色 %s 重复", info.Name)
+		}
+		if len(t.errs) == before {
+			chars[info.Name] = info
+		}
+	}
+	return chars, errorOrNil(t.errs)
+}
+
+// ExportWeapons 导出武器价格表，按武器名排序
+func ExportWeapons(w io.Writer, weapons map[string]newrule.WeaponInfo) error {
+	names := make([]string, 0, len(weapons))
+	for name := range weapons {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	records := make([][]string, 0, len(names))
+	for _, name := range names {
+		rec := []string{name}
+		for _, p := range weapons[name].Prices {
+			rec = append(rec, formatFloat(p))
+		}
+		records = append(records, append(rec, provenanceCells(weapons[name].Provenance)...))
+	}
+	return writeTable(w, weaponHeader, records)
+}
+
+// ImportWeapons 导入武器价格表
+func ImportWeapons(r io.Reader) (map[string]newrule.WeaponInfo, error) {
+	t, rows := readTable(WeaponsFile, r, weaponHeader[:6])
+	weapons := make(map[string]newrule.WeaponInfo, len(rows))
+	for _, row := range rows {
+		before := len(t.errs)
+		info := newrule.WeaponInfo{Name: row.str("武器"), Provenance: row.provenance()}
+		for i := range info.Prices {
+			col := weaponHeader[1+i]
+			if row.str(col) == "" {
+				row.fail(col, "价格不能为空")
+			}
+			if info.Prices[i] = row.float(col); info.Prices[i] < 0 {
+				row.fail(col, "价格不能为负")
+			}
+		}
+		if info.Name == "" {
+			row.fail("武器", "武器名不能为空")
+		} else if _, dup := weapons[info.Name]; dup {
+			row.fail("武器", "武器 %s 重复", info.Name)
+		}
+		if len(t.errs) == before {
+			weapons[info.Name] = info
+		}
+	}
+	return weapons, errorOrNil(t.errs)
+}
+
+// ExportCharCountTiers 导出角色数量乘数档位
+func ExportCharCountTiers(w io.Writer, tiers []newrule.CharCountTier) error {
+	records := make([][]string, 0, len(tiers))
+	for _, tier := range tiers {
+		rec := []string{strconv.Itoa(tier.MinCount), strconv.Itoa(tier.MaxCount), formatFloat(tier.Factor)}
+		records = append(records, append(rec, provenanceCells(tier.Provenance)...))
+	}
+	return writeTable(w, charTierHeader, records)
+}
+
+// ImportCharCountTiers 导入角色数量乘数档位
+func ImportCharCountTiers(r io.Reader) ([]newrule.CharCountTier, error) {
+	t, rows := readTable(CharCountTiersFile, r, charTierHeader[:3])
+	var tiers []newrule.CharCountTier
+	for _, row := range rows {
+		tier := newrule.CharCountTier{MinCount: row.int("最少角色数"), MaxCount: row.int("最多角色数"), Factor: row.float("乘数"), Provenance: row.provenance()}
+		if tier.MaxCount < tier.MinCount {
+			row.fail("最多角色数", "%d 小于最少角色数 %d", tier.MaxCount, tier.MinCount)
+		}
+		if tier.Factor <= 0 {
+			row.fail("乘数", "乘数必须为正")
+		}
+		tiers = append(tiers, tier)
+	}
+	return tiers, errorOrNil(t.errs)
+}
+
+// ExportResourceTiers 导出资源单价档位
+func ExportResourceTiers(w io.Writer, tiers []newrule.ResourceTier) error {
+	records := make([][]string, 0, len(tiers))
+	for _, tier := range tiers {
+		rec := []string{strconv.Itoa(tier.MinFates), formatFloat(tier.Price)}
+		records = append(records, append(rec, provenanceCells(tier.Provenance)...))
+	}
+	return writeTable(w, resTierHeader, records)
+}
+
+// ImportResourceTiers 导入资源单价档位
+func ImportResourceTiers(r io.Reader) ([]newrule.ResourceTier, error) {
+	t, rows := readTable(ResourceTiersFile, r, resTierHeader[:2])
+	var tiers []newrule.ResourceTier
+	for _, row := range rows {
+		tier := newrule.ResourceTier{MinFates: row.int("最少纠缠数"), Price: row.float("单价"), Provenance: row.provenance()}
+		if tier.MinFates < 0 {
+			row.fail("最少纠缠数", "不能为负")
+		}
+		if tier.Price < 0 {
+			row.fail("单价", "单价不能为负")
+		}
+		tiers = append(tiers, tier)
+	}
+	return tiers, errorOrNil(t.errs)
+}
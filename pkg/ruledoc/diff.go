@@ -0,0 +1,112 @@
+package ruledoc
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval/newrule"
+)
+
+// Change 是导入的规则与当前规则之间的一处差异
+type Change struct {
+	Table  string // "角色"、"武器" 或 "组合"
+	Kind   string // "新增": 仅文档中存在; "删除": 仅当前规则中存在; "修改": 内容不同
+	Name   string
+	Detail string
+}
+
+func (c Change) String() string {
+	if c.Detail == "" {
+		return fmt.Sprintf("[%s][%s] %s", c.Table, c.Kind, c.Name)
+	}
+	return fmt.Sprintf("[%s][%s] %s: %s", c.Table, c.Kind, c.Name, c.Detail)
+}
+
+func formatPrice(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatPrices(prices []float64) string {
+	parts := make([]string, len(prices))
+	for i, v := range prices {
+		parts[i] = formatPrice(v)
+	}
+	return strings.Join(parts, "/")
+}
+
+// priceChanges 描述两组价格的差异，如 "6命 550 → 500"
+func priceChanges(old, new []float64, label func(i int) string) []string {
+	var changes []string
+	for i := range old {
+		if old[i] != new[i] {
+			changes = append(changes, fmt.Sprintf("%s %s → %s", label(i), formatPrice(old[i]), formatPrice(new[i])))
+		}
+	}
+	return changes
+}
+
+// sortedKeys 返回两个表中全部名称，按名称排序
+func sortedKeys[A, B any](a map[string]A, b map[string]B) []string {
+	seen := make(map[string]bool)
+	for k := range a {
+		seen[k] = true
+	}
+	for k := range b {
+		seen[k] = true
+	}
+	keys := make([]string, 0, len(seen))
+	for k := range seen {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Diff 比较导入的规则与当前规则中的价格表与组合，按角色、武器、组合的顺序返回差异
+func Diff(current, imported newrule.ValuationRules) []Change {
+	var changes []Change
+
+	constLabel := func(i int) string { return fmt.Sprintf("%d命", i) }
+	for _, name := range sortedKeys(current.Characters, imported.Characters) {
+		old, inOld := current.Characters[name]
+		now, inNew := imported.Characters[name]
+		switch {
+		case !inOld:
+			changes = append(changes, Change{Table: "角色", Kind: "新增", Name: name, Detail: formatPrices(now.Prices[:])})
+		case !inNew:
+			changes = append(changes, Change{Table: "角色", Kind: "删除", Name: name, Detail: formatPrices(old.Prices[:])})
+		default:
+			details := priceChanges(old.Prices[:], now.Prices[:], constLabel)
+			if old.SpecializedWeapon != now.SpecializedWeapon {
+				details = append(details, fmt.Sprintf("专武 %s → %s", old.SpecializedWeapon, now.SpecializedWeapon))
+			}
+			if len(details) > 0 {
+				changes = append(changes, Change{Table: "角色", Kind: "修改", Name: name, Detail: strings.Join(details, "; ")})
+			}
+		}
+	}
+
+	refineLabel := func(i int) string { return fmt.Sprintf("精%d", i+1) }
+	for _, name := range sortedKeys(current.Weapons, imported.Weapons) {
+		old, inOld := current.Weapons[name]
+		now, inNew := imported.Weapons[name]
+		switch {
+		case !inOld:
+			changes = append(changes, Change{Table: "武器", Kind: "新增", Name: name, Detail: formatPrices(now.Prices[:])})
+		case !inNew:
+			changes = append(changes, Change{Table: "武器", Kind: "删除", Name: name, Detail: formatPrices(old.Prices[:])})
+		default:
+			if details := priceChanges(old.Prices[:], now.Prices[:], refineLabel); len(details) > 0 {
+				changes = append(changes, Change{Table: "武器", Kind: "修改", Name: name, Detail: strings.Join(details, "; ")})
+			}
+		}
+	}
+
+	kinds := map[string]string{"多余": "新增", "缺失": "删除", "不一致": "修改"}
+	for _, d := range newrule.DiffCombos(imported.Combos, current.Combos, "文档", "当前") {
+		changes = append(changes, Change{Table: "组合", Kind: kinds[d.Kind], Name: d.Name, Detail: d.Detail})
+	}
+	return changes
+}
@@ -0,0 +1,288 @@
+// Package ruledoc 从估值规则所依据的 Word 价格文档 (.docx) 中提取价格表与组合列表，
+// 转换为规则集并与当前加载的规则比较，免去手工誊录
+package ruledoc
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Block 是文档正文中的一个段落或表格
+type Block struct {
+	Heading int        // 标题级别 1-9，普通段落与表格为 0
+	Text    string     // 段落文字
+	Table   [][]string // 表格各行的单元格文字，段落为 nil
+}
+
+// Document 是按正文顺序排列的段落与表格
+type Document struct {
+	Name   string // 文件名，作为提取出的规则条目的来源
+	Blocks []Block
+}
+
+// OpenDocx 读取并解析 .docx 文件
+func OpenDocx(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := ParseDocx(data)
+	if err != nil {
+		return nil, err
+	}
+	doc.Name = filepath.Base(path)
+	return doc, nil
+}
+
+// ParseDocx 解析 .docx 文件内容 (zip 包中的 word/document.xml 与 word/styles.xml)
+func ParseDocx(data []byte) (*Document, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("不是有效的 .docx 文件: %w", err)
+	}
+	var body, styles []byte
+	for _, f := range zr.File {
+		switch f.Name {
+		case "word/document.xml":
+			body, err = readZipFile(f)
+		case "word/styles.xml":
+			styles, err = readZipFile(f)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("读取 %s 失败: %w", f.Name, err)
+		}
+	}
+	if body == nil {
+		return nil, errors.New("不是有效的 .docx 文件: 缺少 word/document.xml")
+	}
+
+	p := &docxParser{headingStyles: map[string]int{}}
+	if styles != nil {
+		if err := p.parseStyles(styles); err != nil {
+			return nil, fmt.Errorf("解析 word/styles.xml 失败: %w", err)
+		}
+	}
+	doc, err := p.parseBody(body)
+	if err != nil {
+		return nil, fmt.Errorf("解析 word/document.xml 失败: %w", err)
+	}
+	return doc, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+type docxParser struct {
+	d             *xml.Decoder
+	headingStyles map[string]int // 样式 ID -> 标题级别
+}
+
+// attr 返回元素的属性值，忽略命名空间
+func attr(se xml.StartElement, local string) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// headingLevel 由样式名 ("heading 2"、"标题 2") 或大纲级别 (从0开始) 推断标题级别
+func headingLevel(styleName, outlineLvl string) int {
+	if n, err := strconv.Atoi(outlineLvl); err == nil && n >= 0 && n < 9 {
+		return n + 1
+	}
+	name := strings.ToLower(strings.TrimSpace(styleName))
+	for _, prefix := range []string{"heading", "标题"} {
+		if rest, ok := strings.CutPrefix(name, prefix); ok {
+			if n, err := strconv.Atoi(strings.TrimSpace(rest)); err == nil && n >= 1 && n <= 9 {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// parseStyles 记录标题样式，中文版 Word 的标题样式 ID 通常是 "1"、"2" 等，需要按样式名判断
+func (p *docxParser) parseStyles(data []byte) error {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var id, name, outline string
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "style":
+				id, name, outline = attr(t, "styleId"), "", ""
+			case "name":
+				name = attr(t, "val")
+			case "outlineLvl":
+				outline = attr(t, "val")
+			}
+		case xml.EndElement:
+			if t.Name.Local == "style" {
+				if level := headingLevel(name, outline); level > 0 && id != "" {
+					p.headingStyles[id] = level
+				}
+			}
+		}
+	}
+}
+
+// parseBody 按顺序读取正文中的段落与表格，内容控件等容器中的段落同样读取
+func (p *docxParser) parseBody(data []byte) (*Document, error) {
+	p.d = xml.NewDecoder(bytes.NewReader(data))
+	doc := &Document{}
+	for {
+		tok, err := p.d.Token()
+		if err == io.EOF {
+			return doc, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch se.Name.Local {
+		case "p":
+			text, heading, err := p.paragraph()
+			if err != nil {
+				return nil, err
+			}
+			doc.Blocks = append(doc.Blocks, Block{Heading: heading, Text: text})
+		case "tbl":
+			rows, err := p.table()
+			if err != nil {
+				return nil, err
+			}
+			doc.Blocks = append(doc.Blocks, Block{Table: rows})
+		}
+	}
+}
+
+// paragraph 读取到段落结束，返回段落文字与标题级别
+func (p *docxParser) paragraph() (string, int, error) {
+	var sb strings.Builder
+	var style, outline string
+	inText := false
+	for depth := 1; depth > 0; {
+		tok, err := p.d.Token()
+		if err != nil {
+			return "", 0, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab":
+				sb.WriteByte('\t')
+			case "br", "cr":
+				sb.WriteByte('\n')
+			case "pStyle":
+				style = attr(t, "val")
+			case "outlineLvl":
+				outline = attr(t, "val")
+			}
+		case xml.EndElement:
+			depth--
+			if t.Name.Local == "t" {
+				inText = false
+			}
+		case xml.CharData:
+			if inText {
+				sb.Write(t)
+			}
+		}
+	}
+	heading := p.headingStyles[style]
+	if level := headingLevel("", outline); level > 0 {
+		heading = level
+	}
+	return strings.TrimSpace(sb.String()), heading, nil
+}
+
+// table 读取到表格结束，返回各行单元格文字
+// 横向合并的单元格补空白单元格以保持列对齐，纵向合并的后续单元格沿用上一行同列的文字
+func (p *docxParser) table() ([][]string, error) {
+	var rows [][]string
+	var row []string
+	var cell []string
+	span, merged := 1, false
+	for {
+		tok, err := p.d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "tr":
+				row = nil
+			case "tc":
+				cell, span, merged = nil, 1, false
+			case "gridSpan":
+				if n, err := strconv.Atoi(attr(t, "val")); err == nil && n > 1 {
+					span = n
+				}
+			case "vMerge":
+				merged = attr(t, "val") != "restart"
+			case "p":
+				text, _, err := p.paragraph()
+				if err != nil {
+					return nil, err
+				}
+				if text != "" {
+					cell = append(cell, text)
+				}
+			case "tbl":
+				nested, err := p.table()
+				if err != nil {
+					return nil, err
+				}
+				for _, r := range nested {
+					cell = append(cell, strings.Join(r, " "))
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "tc":
+				text := strings.Join(cell, "\n")
+				if merged && len(rows) > 0 && len(row) < len(rows[len(rows)-1]) {
+					text = rows[len(rows)-1][len(row)]
+				}
+				row = append(row, text)
+				for i := 1; i < span; i++ {
+					row = append(row, "")
+				}
+			case "tr":
+				rows = append(rows, row)
+			case "tbl":
+				return rows, nil
+			}
+		}
+	}
+}
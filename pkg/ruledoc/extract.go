@@ -0,0 +1,447 @@
+package ruledoc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval/newrule"
+)
+
+// Report 记录从文档中提取的条目数以及无法识别或有误的内容
+type Report struct {
+	Characters int
+	Weapons    int
+	Combos     int
+	Warnings   []string
+}
+
+func (r *Report) warnf(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (r *Report) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "从文档中提取: 角色 %d，武器 %d，组合 %d\n", r.Characters, r.Weapons, r.Combos)
+	for _, w := range r.Warnings {
+		fmt.Fprintf(&sb, "  警告: %s\n", w)
+	}
+	return sb.String()
+}
+
+// 文档中的表格按表头识别:
+//   - 角色价格表: "角色" 列与 "0命".."6命" 列 (也可写作 "C0".."C6")，可选 "专武" 列
+//   - 武器价格表: "武器" 列与 "精1".."精5" 列 (也可写作 "R1".."R5")
+//   - 组合表: "组合" 或 "名称" 列与 "价值" 或 "溢价" 列，可选 "分组" 列
+//
+// 标题含 "组合" 的章节中，形如 "6玛薇卡+6茜特菈莉：300" 的段落也作为组合读取，分组取所在章节的标题
+var (
+	comboLinePattern = regexp.MustCompile(`^(?:\d+[.、．)）]\s*)?(.+?)\s*[:：]\s*[+＋]?\s*(\d+(?:\.\d+)?)\s*元?$`)
+	comboPartPattern = regexp.MustCompile(`^(?:大于(\d)命?|(\d)(?:-(\d))?命?)?(\D.*)$`)
+	comboNoteSuffix  = regexp.MustCompile(`\s*[(（][^()（）]*[)）]$`)
+)
+
+// columnIndex 返回表头中第一个满足条件的列，没有时返回 -1
+func columnIndex(header []string, match func(string) bool) int {
+	for i, h := range header {
+		if match(strings.ReplaceAll(strings.TrimSpace(h), " ", "")) {
+			return i
+		}
+	}
+	return -1
+}
+
+func equalsAny(names ...string) func(string) bool {
+	return func(h string) bool {
+		for _, n := range names {
+			if strings.EqualFold(h, n) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func containsAny(names ...string) func(string) bool {
+	return func(h string) bool {
+		for _, n := range names {
+			if strings.Contains(h, n) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// levelColumns 查找 "0命".."6命" 或 "精1".."精5" 这类按级别排列的列，缺少任一列时返回 nil
+func levelColumns(header []string, from, to int, formats ...string) []int {
+	cols := make([]int, 0, to-from+1)
+	for level := from; level <= to; level++ {
+		names := make([]string, 0, len(formats))
+		for _, f := range formats {
+			names = append(names, fmt.Sprintf(f, level))
+		}
+		i := columnIndex(header, equalsAny(names...))
+		if i < 0 {
+			return nil
+		}
+		cols = append(cols, i)
+	}
+	return cols
+}
+
+// parsePrice 读取价格单元格，"550->500" 这类调价记录取调整后的价格，并返回调整前的写法
+func parsePrice(s string) (float64, string, error) {
+	s = strings.NewReplacer(" ", "", "元", "", "→", "->").Replace(strings.TrimSpace(s))
+	previous := ""
+	if i := strings.LastIndex(s, "->"); i >= 0 {
+		previous, s = s[:i], s[i+2:]
+	}
+	if s == "" {
+		return 0, "", fmt.Errorf("价格为空")
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("%q 不是价格", s)
+	}
+	if v < 0 {
+		return 0, "", fmt.Errorf("价格 %s 为负", s)
+	}
+	return v, previous, nil
+}
+
+// cell 返回行中的单元格，行较短时返回空
+func cell(row []string, i int) string {
+	if i < 0 || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// parseComboName 按组合名称的写法解析角色要求，如 "6丝柯克+大于2命玛薇卡+6那维莱特/6阿蕾奇诺+爱可菲"
+// 不写命座表示0-6命，"/" 分隔的选项组成满足其一即可的替代要求组，末尾括号内的备注忽略
+func parseComboName(name string, known func(string) bool) ([]newrule.RequiredChar, []newrule.RequirementGroup, error) {
+	var chars []newrule.RequiredChar
+	var groups []newrule.RequirementGroup
+	base := comboNoteSuffix.ReplaceAllString(name, "")
+	for _, part := range strings.Split(strings.ReplaceAll(base, "＋", "+"), "+") {
+		var options []newrule.RequiredChar
+		for _, opt := range strings.Split(part, "/") {
+			req, err := parseComboPart(strings.TrimSpace(opt), known)
+			if err != nil {
+				return nil, nil, err
+			}
+			options = append(options, req)
+		}
+		if len(options) == 1 {
+			chars = append(chars, options[0])
+		} else {
+			groups = append(groups, newrule.RequirementGroup{Options: options, MinMatch: 1})
+		}
+	}
+	return chars, groups, nil
+}
+
+func parseComboPart(s string, known func(string) bool) (newrule.RequiredChar, error) {
+	m := comboPartPattern.FindStringSubmatch(s)
+	if m == nil || m[4] == "" {
+		return newrule.RequiredChar{}, fmt.Errorf("无法解析组合要求 %q", s)
+	}
+	req := newrule.RequiredChar{Name: m[4], MinConst: 0, MaxConst: 6}
+	switch {
+	case m[1] != "":
+		req.MinConst, _ = strconv.Atoi(m[1])
+	case m[3] != "":
+		req.MinConst, _ = strconv.Atoi(m[2])
+		req.MaxConst, _ = strconv.Atoi(m[3])
+	case m[2] != "":
+		req.MinConst, _ = strconv.Atoi(m[2])
+		req.MaxConst = req.MinConst
+	}
+	if req.MinConst > req.MaxConst || req.MaxConst > 6 {
+		return req, fmt.Errorf("组合要求 %q 的命座范围无效", s)
+	}
+	if !known(req.Name) {
+		return req, fmt.Errorf("组合要求 %q 中的角色 %s 不在价格表中", s, req.Name)
+	}
+	return req, nil
+}
+
+// extractor 逐块读取文档，收集价格表与组合
+type extractor struct {
+	source     string
+	base       newrule.ValuationRules
+	report     *Report
+	characters map[string]newrule.CharacterInfo
+	weapons    map[string]newrule.WeaponInfo
+	combos     []newrule.ComboRule
+	headings   []string // 当前所在章节的各级标题
+}
+
+// Extract 从文档中提取角色价格表、武器价格表与组合，替换 base 中对应的部分
+// 提取出的条目以文档名与所在章节为出处，价格单元格中的调价记录 (如 "550->500") 记为调整前的取值
+// 文档中没有的表沿用 base；文档无法表达的字段 (角色属性、组合的武器要求、条件、共享设置等) 沿用 base 中的同名条目
+func Extract(doc *Document, base newrule.ValuationRules) (newrule.ValuationRules, *Report) {
+	e := &extractor{source: doc.Name, base: base, report: &Report{}}
+
+	// 组合要求中的角色需要对照价格表校验，先读取价格表，再按章节读取组合
+	tables := 0
+	for _, b := range doc.Blocks {
+		switch {
+		case b.Table != nil:
+			tables++
+			e.readPriceTable(tables, b.Table)
+		case b.Heading > 0:
+			e.enterHeading(b.Heading, b.Text)
+		}
+	}
+	tables, e.headings = 0, nil
+	for _, b := range doc.Blocks {
+		switch {
+		case b.Table != nil:
+			tables++
+			e.readComboTable(tables, b.Table)
+		case b.Heading > 0:
+			e.enterHeading(b.Heading, b.Text)
+		default:
+			for _, line := range strings.Split(b.Text, "\n") {
+				e.readComboLine(line)
+			}
+		}
+	}
+
+	rules := base
+	if e.characters != nil {
+		rules.Characters = e.characters
+	}
+	if e.weapons != nil {
+		rules.Weapons = e.weapons
+	}
+	if e.combos != nil {
+		rules.Combos = e.combos
+		newrule.SortCombos(rules.Combos)
+	}
+	e.report.Characters, e.report.Weapons, e.report.Combos = len(e.characters), len(e.weapons), len(e.combos)
+	for _, err := range newrule.ValidateRules(rules) {
+		e.report.warnf("%v", err)
+	}
+	return rules, e.report
+}
+
+// enterHeading 更新当前章节
+func (e *extractor) enterHeading(level int, text string) {
+	if level > len(e.headings)+1 {
+		level = len(e.headings) + 1
+	}
+	e.headings = append(e.headings[:level-1], text)
+}
+
+// inComboSection 判断当前章节是否为组合章节
+func (e *extractor) inComboSection() bool {
+	for _, h := range e.headings {
+		if strings.Contains(h, "组合") {
+			return true
+		}
+	}
+	return false
+}
+
+// section 返回当前章节的标题，用作组合分组
+func (e *extractor) section() string {
+	if len(e.headings) == 0 {
+		return ""
+	}
+	return e.headings[len(e.headings)-1]
+}
+
+// provenance 返回当前章节中条目的出处
+func (e *extractor) provenance(previous []string) newrule.Provenance {
+	return newrule.Provenance{Source: e.source, Section: e.section(), Previous: strings.Join(previous, "; ")}
+}
+
+// knownCharacter 判断角色是否在价格表中，文档没有角色价格表时对照 base
+func (e *extractor) knownCharacter(name string) bool {
+	if e.characters != nil {
+		_, ok := e.characters[name]
+		return ok
+	}
+	_, ok := e.base.Characters[name]
+	return ok
+}
+
+// readPriceTable 读取角色或武器价格表，其他表格忽略
+func (e *extractor) readPriceTable(no int, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+	header := rows[0]
+	if name := columnIndex(header, containsAny("角色")); name >= 0 {
+		if prices := levelColumns(header, 0, 6, "%d命", "C%d"); prices != nil {
+			e.readCharacters(no, rows, name, prices, columnIndex(header, containsAny("专武")))
+			return
+		}
+	}
+	if name := columnIndex(header, containsAny("武器")); name >= 0 {
+		if prices := levelColumns(header, 1, 5, "精%d", "R%d"); prices != nil {
+			e.readWeapons(no, rows, name, prices)
+		}
+	}
+}
+
+func (e *extractor) readCharacters(no int, rows [][]string, nameCol int, priceCols []int, weaponCol int) {
+	if e.characters == nil {
+		e.characters = make(map[string]newrule.CharacterInfo)
+	}
+	for i, row := range rows[1:] {
+		name := cell(row, nameCol)
+		if name == "" {
+			continue
+		}
+		info, ok := e.base.Characters[name]
+		if !ok {
+			info = newrule.CharacterInfo{Name: name}
+		}
+		valid := true
+		var previous []string
+		for c, col := range priceCols {
+			v, prev, err := parsePrice(cell(row, col))
+			if err != nil {
+				e.report.warnf("表格 %d 第 %d 行 %s %d命: %v", no, i+2, name, c, err)
+				valid = false
+				continue
+			}
+			info.Prices[c] = v
+			if prev != "" {
+				previous = append(previous, fmt.Sprintf("%d命 %s", c, prev))
+			}
+		}
+		if weaponCol >= 0 {
+			info.SpecializedWeapon = cell(row, weaponCol)
+		}
+		info.Provenance = e.provenance(previous)
+		if !valid {
+			continue
+		}
+		if _, dup := e.characters[name]; dup {
+			e.report.warnf("表格 %d 第 %d 行: 角色 %s 重复，以此行为准", no, i+2, name)
+		}
+		e.characters[name] = info
+	}
+}
+
+func (e *extractor) readWeapons(no int, rows [][]string, nameCol int, priceCols []int) {
+	if e.weapons == nil {
+		e.weapons = make(map[string]newrule.WeaponInfo)
+	}
+	for i, row := range rows[1:] {
+		name := cell(row, nameCol)
+		if name == "" {
+			continue
+		}
+		info := newrule.WeaponInfo{Name: name}
+		valid := true
+		var previous []string
+		for r, col := range priceCols {
+			v, prev, err := parsePrice(cell(row, col))
+			if err != nil {
+				e.report.warnf("表格 %d 第 %d 行 %s 精%d: %v", no, i+2, name, r+1, err)
+				valid = false
+				continue
+			}
+			info.Prices[r] = v
+			if prev != "" {
+				previous = append(previous, fmt.Sprintf("精%d %s", r+1, prev))
+			}
+		}
+		info.Provenance = e.provenance(previous)
+		if !valid {
+			continue
+		}
+		if _, dup := e.weapons[name]; dup {
+			e.report.warnf("表格 %d 第 %d 行: 武器 %s 重复，以此行为准", no, i+2, name)
+		}
+		e.weapons[name] = info
+	}
+}
+
+// readComboTable 读取组合表，价格表以外无法识别的表格给出警告
+func (e *extractor) readComboTable(no int, rows [][]string) {
+	if len(rows) == 0 {
+		return
+	}
+	header := rows[0]
+	nameCol := columnIndex(header, containsAny("组合", "名称"))
+	valueCol := columnIndex(header, containsAny("价值", "溢价"))
+	if nameCol < 0 || valueCol < 0 {
+		if e.isPriceTable(header) {
+			return
+		}
+		e.report.warnf("表格 %d: 无法识别的表头 [%s]，已忽略", no, strings.Join(header, ", "))
+		return
+	}
+	groupCol := columnIndex(header, containsAny("分组"))
+	for i, row := range rows[1:] {
+		name := cell(row, nameCol)
+		if name == "" {
+			continue
+		}
+		group := cell(row, groupCol)
+		if groupCol < 0 {
+			group = e.section()
+		}
+		if err := e.addCombo(name, cell(row, valueCol), group); err != nil {
+			e.report.warnf("表格 %d 第 %d 行: %v", no, i+2, err)
+		}
+	}
+}
+
+func (e *extractor) isPriceTable(header []string) bool {
+	return (columnIndex(header, containsAny("角色")) >= 0 && levelColumns(header, 0, 6, "%d命", "C%d") != nil) ||
+		(columnIndex(header, containsAny("武器")) >= 0 && levelColumns(header, 1, 5, "精%d", "R%d") != nil)
+}
+
+// readComboLine 读取组合章节中 "组合名称：价值" 形式的段落
+func (e *extractor) readComboLine(line string) {
+	line = strings.TrimSpace(line)
+	if line == "" || !e.inComboSection() {
+		return
+	}
+	m := comboLinePattern.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+	if err := e.addCombo(m[1], m[2], e.section()); err != nil {
+		e.report.warnf("%s 章节 %q: %v", e.section(), line, err)
+	}
+}
+
+// addCombo 由组合名称与价值生成组合，沿用 base 中同名组合里名称无法表达的字段
+func (e *extractor) addCombo(name, value, group string) error {
+	v, prev, err := parsePrice(value)
+	if err != nil {
+		return fmt.Errorf("组合 %s: %v", name, err)
+	}
+	chars, groups, err := parseComboName(name, e.knownCharacter)
+	if err != nil {
+		return err
+	}
+	var previous []string
+	if prev != "" {
+		previous = append(previous, prev)
+	}
+	combo := newrule.ComboRule{Name: name, Group: group, Value: v, RequiredChars: chars, RequiredGroups: groups, Provenance: e.provenance(previous)}
+	for _, old := range e.base.Combos {
+		if old.Name == name {
+			combo.Condition = old.Condition
+			combo.RequiredAttributes = old.RequiredAttributes
+			combo.RequiredWeapons = old.RequiredWeapons
+			combo.WeaponMultipliers = old.WeaponMultipliers
+			combo.ShareableChars = old.ShareableChars
+			combo.SharingDiscount = old.SharingDiscount
+			break
+		}
+	}
+	e.combos = append(e.combos, combo)
+	return nil
+}
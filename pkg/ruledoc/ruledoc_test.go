@@ -0,0 +1,192 @@
+package ruledoc
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval/newrule"
+)
+
+const wordNS = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"`
+
+// buildDocx 生成只含正文与样式的最小 .docx
+func buildDocx(t *testing.T, body string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := map[string]string{
+		"word/document.xml": `<?xml version="1.0" encoding="UTF-8"?><w:document ` + wordNS + `><w:body>` + body + `</w:body></w:document>`,
+		"word/styles.xml": `<?xml version="1.0" encoding="UTF-8"?><w:styles ` + wordNS + `>` +
+			`<w:style w:type="paragraph" w:styleId="1"><w:name w:val="heading 1"/></w:style>` +
+			`<w:style w:type="paragraph" w:styleId="a3"><w:name w:val="Normal"/></w:style></w:styles>`,
+	}
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseDocx(t *testing.T) {
+	body := `<w:p><w:pPr><w:pStyle w:val="1"/></w:pPr><w:r><w:t>纳塔满命</w:t></w:r><w:r><w:t>溢价组合</w:t></w:r></w:p>` +
+		`<w:p><w:pPr><w:outlineLvl w:val="1"/></w:pPr><w:r><w:t>子标题</w:t></w:r></w:p>` +
+		`<w:p><w:r><w:t>6玛薇卡+6茜特菈莉：300</w:t><w:br/><w:t xml:space="preserve">6玛薇卡+6恰斯卡 </w:t><w:t>：500</w:t></w:r></w:p>` +
+		`<w:tbl>` +
+		`<w:tr><w:tc><w:p><w:r><w:t>分组</w:t></w:r></w:p></w:tc><w:tc><w:tcPr><w:gridSpan w:val="2"/></w:tcPr><w:p><w:r><w:t>组合</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>价值</w:t></w:r></w:p></w:tc></w:tr>` +
+		`<w:tr><w:tc><w:tcPr><w:vMerge w:val="restart"/></w:tcPr><w:p><w:r><w:t>A</w:t></w:r></w:p></w:tc><w:tc><w:p/></w:tc><w:tc><w:p><w:r><w:t>x</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>1</w:t></w:r></w:p></w:tc></w:tr>` +
+		`<w:tr><w:tc><w:tcPr><w:vMerge/></w:tcPr><w:p/></w:tc><w:tc><w:p/></w:tc><w:tc><w:p><w:r><w:t>y</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>2</w:t></w:r></w:p></w:tc></w:tr>` +
+		`</w:tbl>`
+	doc, err := ParseDocx(buildDocx(t, body))
+	if err != nil {
+		t.Fatalf("ParseDocx: %v", err)
+	}
+	want := []Block{
+		{Heading: 1, Text: "纳塔满命溢价组合"},
+		{Heading: 2, Text: "子标题"},
+		{Text: "6玛薇卡+6茜特菈莉：300\n6玛薇卡+6恰斯卡 ：500"},
+		{Table: [][]string{{"分组", "组合", "", "价值"}, {"A", "", "x", "1"}, {"A", "", "y", "2"}}},
+	}
+	if !reflect.DeepEqual(doc.Blocks, want) {
+		t.Errorf("Blocks =\n%q\nwant\n%q", doc.Blocks, want)
+	}
+
+	if _, err := ParseDocx([]byte("not a zip")); err == nil || !strings.Contains(err.Error(), "不是有效的 .docx 文件") {
+		t.Errorf("err = %v, want invalid docx error", err)
+	}
+}
+
+// documentFor 按文档的版式生成与规则一致的文档: 角色与武器价格表，以及按分组列出组合的章节
+func documentFor(rules newrule.ValuationRules) *Document {
+	doc := &Document{}
+	chars := [][]string{{"角色", "0命", "1命", "2命", "3命", "4命", "5命", "6命", "专武"}}
+	for _, name := range sortedKeys(rules.Characters, rules.Characters) {
+		info := rules.Characters[name]
+		row := []string{name}
+		for _, v := range info.Prices {
+			row = append(row, formatPrice(v))
+		}
+		chars = append(chars, append(row, info.SpecializedWeapon))
+	}
+	weapons := [][]string{{"武器", "精1", "精2", "精3", "精4", "精5"}}
+	for _, name := range sortedKeys(rules.Weapons, rules.Weapons) {
+		row := []string{name}
+		for _, v := range rules.Weapons[name].Prices {
+			row = append(row, formatPrice(v))
+		}
+		weapons = append(weapons, row)
+	}
+	doc.Blocks = append(doc.Blocks, Block{Heading: 1, Text: "角色价格表"}, Block{Table: chars},
+		Block{Heading: 1, Text: "武器价格表"}, Block{Table: weapons})
+
+	groups := make(map[string][]string)
+	var order []string
+	for _, combo := range rules.Combos {
+		if _, ok := groups[combo.Group]; !ok {
+			order = append(order, combo.Group)
+		}
+		groups[combo.Group] = append(groups[combo.Group], fmt.Sprintf("%s：%s", combo.Name, formatPrice(combo.Value)))
+	}
+	sort.Strings(order)
+	for _, group := range order {
+		doc.Blocks = append(doc.Blocks, Block{Heading: 1, Text: group}, Block{Text: strings.Join(groups[group], "\n")})
+	}
+	return doc
+}
+
+func TestExtract_RoundTrip(t *testing.T) {
+	rules := newrule.New().Rules()
+	imported, report := Extract(documentFor(rules), rules)
+	if len(report.Warnings) > 0 {
+		t.Errorf("warnings:\n%s", strings.Join(report.Warnings, "\n"))
+	}
+	if report.Characters != len(rules.Characters) || report.Weapons != len(rules.Weapons) || report.Combos != len(rules.Combos) {
+		t.Errorf("report = %+v", report)
+	}
+	// 组合名称与组合要求一致，文档中的内容应能完整还原当前规则
+	if changes := Diff(rules, imported); len(changes) > 0 {
+		for _, c := range changes {
+			t.Error(c)
+		}
+	}
+}
+
+func TestExtract_Changes(t *testing.T) {
+	rules := newrule.New().Rules()
+	doc := documentFor(rules)
+	chars := doc.Blocks[1].Table
+	for _, row := range chars {
+		if row[0] == "玛薇卡" {
+			row[7] = "500->550"
+		}
+	}
+	doc.Blocks[1].Table = append(chars,
+		[]string{"新角色", "5", "10", "80", "90", "100", "200", "500", ""},
+		[]string{"错误行", "5", "x", "80", "90", "100", "200", "500", ""})
+	doc.Blocks = append(doc.Blocks,
+		Block{Heading: 1, Text: "新增溢价组合"},
+		Block{Text: "说明：以下组合自下个版本生效\n1. 6新角色+大于2命玛薇卡：300\n6未知角色+6玛薇卡：100"},
+		Block{Table: [][]string{{"项目", "备注"}, {"a", "b"}}})
+
+	imported, report := Extract(doc, rules)
+	wantWarnings := []string{
+		`表格 1 第 55 行 错误行 1命: "x" 不是价格`,
+		`新增溢价组合 章节 "6未知角色+6玛薇卡：100": 组合要求 "6未知角色" 中的角色 未知角色 不在价格表中`,
+		`表格 3: 无法识别的表头 [项目, 备注]，已忽略`,
+	}
+	if !reflect.DeepEqual(report.Warnings, wantWarnings) {
+		t.Errorf("warnings =\n%s\nwant\n%s", strings.Join(report.Warnings, "\n"), strings.Join(wantWarnings, "\n"))
+	}
+
+	var got []string
+	for _, c := range Diff(rules, imported) {
+		got = append(got, c.String())
+	}
+	wantDiff := []string{
+		"[角色][新增] 新角色: 5/10/80/90/100/200/500",
+		"[角色][修改] 玛薇卡: 6命 500 → 550",
+		"[组合][新增] 6新角色+大于2命玛薇卡: 分组=新增溢价组合 价值=300.00 角色=[新角色:6-6,玛薇卡:2-6]",
+	}
+	if !reflect.DeepEqual(got, wantDiff) {
+		t.Errorf("Diff =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(wantDiff, "\n"))
+	}
+	if imported.Characters["玛薇卡"].Region != "纳塔" {
+		t.Error("character attributes missing from the document should be kept")
+	}
+	want := newrule.Provenance{Section: "角色价格表", Previous: "6命 500"}
+	if got := imported.Characters["玛薇卡"].Provenance; got != want {
+		t.Errorf("Provenance = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseComboName(t *testing.T) {
+	known := func(string) bool { return true }
+	chars, groups, err := parseComboName("6丝柯克+大于2命玛薇卡+0-1兹白+6那维莱特/6阿蕾奇诺+爱可菲(独立)", known)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantChars := []newrule.RequiredChar{
+		{Name: "丝柯克", MinConst: 6, MaxConst: 6},
+		{Name: "玛薇卡", MinConst: 2, MaxConst: 6},
+		{Name: "兹白", MinConst: 0, MaxConst: 1},
+		{Name: "爱可菲", MinConst: 0, MaxConst: 6},
+	}
+	wantGroups := []newrule.RequirementGroup{{MinMatch: 1, Options: []newrule.RequiredChar{
+		{Name: "那维莱特", MinConst: 6, MaxConst: 6}, {Name: "阿蕾奇诺", MinConst: 6, MaxConst: 6}}}}
+	if !reflect.DeepEqual(chars, wantChars) || !reflect.DeepEqual(groups, wantGroups) {
+		t.Errorf("parseComboName = %+v %+v", chars, groups)
+	}
+	if _, _, err := parseComboName("6玛薇卡+5-2茜特菈莉", known); err == nil {
+		t.Error("expected error for invalid constellation range")
+	}
+}
@@ -0,0 +1,130 @@
+package schema
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// Generate 由文档的 Go 类型生成 JSON Schema
+// 字段名取自 json 标签，未标 omitempty 或 omitzero 的字段为必填；切片与 map 为 nil 时编码为 null，因此允许 null；
+// 实现了 encoding.TextMarshaler 的整数类型视为枚举，枚举值由 MarshalText 逐个列出
+func Generate(d Document) ([]byte, error) {
+	g := &generator{defs: make(map[string]any)}
+	root := g.structSchema(d.Type)
+	root["$schema"] = jsonSchemaDialect
+	root["title"] = d.Title
+	root["properties"].(map[string]any)["schema"] = map[string]any{"const": d.Version}
+	if len(g.defs) > 0 {
+		root["$defs"] = g.defs
+	}
+	data, err := json.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+type generator struct {
+	defs map[string]any
+}
+
+func (g *generator) schemaFor(t reflect.Type) map[string]any {
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	if t.Implements(textMarshalerType) {
+		s := map[string]any{"type": "string"}
+		if enum := enumValues(t); len(enum) > 0 {
+			s["enum"] = enum
+		}
+		return s
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Array:
+		return map[string]any{"type": "array", "items": g.schemaFor(t.Elem()), "minItems": t.Len(), "maxItems": t.Len()}
+	case reflect.Slice:
+		return map[string]any{"type": []string{"array", "null"}, "items": g.schemaFor(t.Elem())}
+	case reflect.Map:
+		s := map[string]any{"type": []string{"object", "null"}, "additionalProperties": g.schemaFor(t.Elem())}
+		switch t.Key().Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			s["propertyNames"] = map[string]any{"pattern": "^-?[0-9]+$"}
+		}
+		return s
+	case reflect.Pointer:
+		return g.schemaFor(t.Elem())
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		if _, ok := g.defs[t.Name()]; !ok {
+			g.defs[t.Name()] = nil // 占位，防止递归类型无限展开
+			g.defs[t.Name()] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/$defs/" + t.Name()}
+	default:
+		return map[string]any{}
+	}
+}
+
+func (g *generator) structSchema(t reflect.Type) map[string]any {
+	properties := make(map[string]any)
+	required := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		properties[name] = g.schemaFor(f.Type)
+		if !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero") {
+			required = append(required, name)
+		}
+	}
+	return map[string]any{"type": "object", "properties": properties, "required": required}
+}
+
+// enumValues 依次对 0, 1, 2... 调用 MarshalText，直到出错为止
+func enumValues(t reflect.Type) []string {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	default:
+		return nil
+	}
+	var values []string
+	v := reflect.New(t).Elem()
+	for i := int64(0); i < 64; i++ {
+		v.SetInt(i)
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			break
+		}
+		values = append(values, string(text))
+	}
+	return values
+}
@@ -0,0 +1,175 @@
+// Package schema 定义账号、规则集与估值结果的版本化 JSON 文档
+//
+// 每个文档都带有 schema 字段标明版本，读取时版本不符即报错；
+// 对应的 JSON Schema 由 Go 类型生成，见 docs/schema
+package schema
+
+//go:generate go run ../../cmd/schema-gen -out ../../docs/schema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+	"github.com/sdojjy/genshin-value-rule/pkg/eval/newrule"
+)
+
+// 各文档的 schema 版本
+const (
+	AccountV1   = "genshin-value-rule/account/v1"
+	RuleSetV1   = "genshin-value-rule/ruleset/v1"
+	ValuationV1 = "genshin-value-rule/valuation/v1"
+)
+
+// AccountDocument 是账号文档
+type AccountDocument struct {
+	Schema  string      `json:"schema"`
+	Account eval.Assets `json:"account"`
+}
+
+// RuleSetDocument 是规则集文档，带版本号时可登记为按时间生效的一版规则
+type RuleSetDocument struct {
+	Schema        string                 `json:"schema"`
+	Version       string                 `json:"version,omitempty"`
+	GameVersion   string                 `json:"gameVersion,omitempty"`
+	EffectiveFrom time.Time              `json:"effectiveFrom,omitzero"`
+	Rules         newrule.ValuationRules `json:"rules"`
+}
+
+// ValuationDocument 是估值结果文档，附带被估值的账号
+type ValuationDocument struct {
+	Schema  string                  `json:"schema"`
+	Account eval.Assets             `json:"account"`
+	Result  newrule.ValuationReport `json:"result"`
+}
+
+// Document 描述一种文档及其 JSON Schema 文件
+type Document struct {
+	File    string // docs/schema 下的文件名
+	Version string
+	Title   string
+	Type    reflect.Type
+}
+
+// Documents 是全部文档类型
+var Documents = []Document{
+	{File: "account.v1.json", Version: AccountV1, Title: "账号", Type: reflect.TypeOf(AccountDocument{})},
+	{File: "ruleset.v1.json", Version: RuleSetV1, Title: "估值规则集", Type: reflect.TypeOf(RuleSetDocument{})},
+	{File: "valuation.v1.json", Version: ValuationV1, Title: "估值结果", Type: reflect.TypeOf(ValuationDocument{})},
+}
+
+// encode 以缩进格式输出文档
+func encode(doc any) ([]byte, error) {
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// decode 解析文档并校验 schema 版本
+func decode(data []byte, version string, doc any) error {
+	var header struct {
+		Schema string `json:"schema"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return fmt.Errorf("解析文档失败: %w", err)
+	}
+	if header.Schema == "" {
+		return fmt.Errorf("文档缺少 schema 字段，应为 %q", version)
+	}
+	if header.Schema != version {
+		return fmt.Errorf("不支持的 schema 版本 %q，应为 %q", header.Schema, version)
+	}
+	if err := json.Unmarshal(data, doc); err != nil {
+		return fmt.Errorf("解析 %s 文档失败: %w", version, err)
+	}
+	return nil
+}
+
+// EncodeAccount 将账号编码为账号文档
+func EncodeAccount(account eval.Assets) ([]byte, error) {
+	return encode(AccountDocument{Schema: AccountV1, Account: account})
+}
+
+// DecodeAccount 解析账号文档
+func DecodeAccount(data []byte) (eval.Assets, error) {
+	var doc AccountDocument
+	if err := decode(data, AccountV1, &doc); err != nil {
+		return eval.Assets{}, err
+	}
+	return doc.Account, nil
+}
+
+// EncodeRuleSet 将估值规则编码为规则集文档
+func EncodeRuleSet(rules newrule.ValuationRules) ([]byte, error) {
+	return encode(RuleSetDocument{Schema: RuleSetV1, Rules: rules})
+}
+
+// DecodeRuleSet 解析规则集文档，规则须通过 newrule.ValidateRules 校验
+func DecodeRuleSet(data []byte) (newrule.ValuationRules, error) {
+	var doc RuleSetDocument
+	if err := decode(data, RuleSetV1, &doc); err != nil {
+		return newrule.ValuationRules{}, err
+	}
+	if errs := newrule.ValidateRules(doc.Rules); len(errs) > 0 {
+		return newrule.ValuationRules{}, fmt.Errorf("规则集校验失败: %w", errors.Join(errs...))
+	}
+	return doc.Rules, nil
+}
+
+// EncodeRuleSetVersion 将一版规则编码为带版本信息的规则集文档
+func EncodeRuleSetVersion(v newrule.RuleSetVersion) ([]byte, error) {
+	return encode(RuleSetDocument{Schema: RuleSetV1, Version: v.Version, GameVersion: v.GameVersion,
+		EffectiveFrom: v.EffectiveFrom, Rules: v.Rules})
+}
+
+// DecodeRuleSetVersion 解析带版本信息的规则集文档，文档须有 version 字段
+func DecodeRuleSetVersion(data []byte) (newrule.RuleSetVersion, error) {
+	var doc RuleSetDocument
+	if err := decode(data, RuleSetV1, &doc); err != nil {
+		return newrule.RuleSetVersion{}, err
+	}
+	if doc.Version == "" {
+		return newrule.RuleSetVersion{}, fmt.Errorf("规则集文档缺少 version 字段")
+	}
+	return newrule.RuleSetVersion{Version: doc.Version, GameVersion: doc.GameVersion,
+		EffectiveFrom: doc.EffectiveFrom, Rules: doc.Rules}, nil
+}
+
+// LoadRuleVersions 读取目录下全部 .json 规则集文档并登记到 registry
+func LoadRuleVersions(dir string, registry *newrule.RuleRegistry) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		v, err := DecodeRuleSetVersion(data)
+		if err == nil {
+			err = registry.Register(v)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+	}
+	return nil
+}
+
+// EncodeValuation 将估值结果编码为估值结果文档
+func EncodeValuation(account eval.Assets, result newrule.ValuationReport) ([]byte, error) {
+	return encode(ValuationDocument{Schema: ValuationV1, Account: account, Result: result})
+}
+
+// DecodeValuation 解析估值结果文档
+func DecodeValuation(data []byte) (eval.Assets, newrule.ValuationReport, error) {
+	var doc ValuationDocument
+	if err := decode(data, ValuationV1, &doc); err != nil {
+		return eval.Assets{}, newrule.ValuationReport{}, err
+	}
+	return doc.Account, doc.Result, nil
+}
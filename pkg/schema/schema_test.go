@@ -0,0 +1,217 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sdojjy/genshin-value-rule/pkg/eval"
+	"github.com/sdojjy/genshin-value-rule/pkg/eval/newrule"
+)
+
+var sampleAccount = eval.Assets{
+	Characters:          map[string]int{"玛薇卡": 6, "茜特菈莉": 2, "希诺宁": 0, "丝柯克": 6},
+	Weapons:             map[string]int{"焚曜千阳": 5, "苍耀": 1},
+	YuanShi:             32000,
+	JiuChanZhiYuan:      120,
+	CharacterPity:       40,
+	CharacterGuaranteed: true,
+	WeaponPity:          10,
+	FatePoints:          1,
+}
+
+func TestAccountRoundTrip(t *testing.T) {
+	data, err := EncodeAccount(sampleAccount)
+	if err != nil {
+		t.Fatalf("EncodeAccount: %v", err)
+	}
+	got, err := DecodeAccount(data)
+	if err != nil {
+		t.Fatalf("DecodeAccount: %v", err)
+	}
+	if !reflect.DeepEqual(got, sampleAccount) {
+		t.Errorf("round trip = %+v, want %+v", got, sampleAccount)
+	}
+	if !bytes.Contains(data, []byte(`"schema": "`+AccountV1+`"`)) || !bytes.Contains(data, []byte(`"yuanShi": 32000`)) {
+		t.Errorf("encoded account:\n%s", data)
+	}
+}
+
+// roundTripJSON 检查解码再编码后得到相同的 JSON
+func roundTripJSON[T any](t *testing.T, data []byte, decode func([]byte) (T, error), encode func(T) ([]byte, error)) {
+	t.Helper()
+	v, err := decode(data)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	again, err := encode(v)
+	if err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if !bytes.Equal(data, again) {
+		t.Errorf("round trip changed the document")
+	}
+}
+
+func TestRuleSetRoundTrip(t *testing.T) {
+	rules := newrule.New().Rules()
+	data, err := EncodeRuleSet(rules)
+	if err != nil {
+		t.Fatalf("EncodeRuleSet: %v", err)
+	}
+	roundTripJSON(t, data, DecodeRuleSet, EncodeRuleSet)
+
+	decoded, err := DecodeRuleSet(data)
+	if err != nil {
+		t.Fatalf("DecodeRuleSet: %v", err)
+	}
+	if len(decoded.Combos) != len(rules.Combos) || len(decoded.Characters) != len(rules.Characters) {
+		t.Errorf("decoded %d combos / %d characters, want %d / %d",
+			len(decoded.Combos), len(decoded.Characters), len(rules.Combos), len(rules.Characters))
+	}
+	if !reflect.DeepEqual(decoded.Characters, rules.Characters) || !reflect.DeepEqual(decoded.Pipeline, rules.Pipeline) ||
+		decoded.ResourceValueMode != rules.ResourceValueMode || decoded.MonteCarlo != rules.MonteCarlo {
+		t.Error("decoded rule set differs from the loaded rules")
+	}
+	if !bytes.Contains(data, []byte(`"resourceValueMode": "`)) {
+		t.Error("enums should be encoded by name")
+	}
+}
+
+func TestDecodeRuleSet_Invalid(t *testing.T) {
+	rules := newrule.New().Rules()
+	rules.SpecialRules = append(rules.SpecialRules, newrule.SpecialRuleConfig{Type: "expr", Name: "坏规则", Expr: `has(6) => +1`})
+	data, err := EncodeRuleSet(rules)
+	if err != nil {
+		t.Fatalf("EncodeRuleSet: %v", err)
+	}
+	if _, err := DecodeRuleSet(data); err == nil || !strings.Contains(err.Error(), "坏规则") {
+		t.Errorf("DecodeRuleSet should reject invalid rules, got %v", err)
+	}
+}
+
+func TestRuleSetVersion(t *testing.T) {
+	v := newrule.RuleSetVersion{Version: "2025-10", GameVersion: "6.1",
+		EffectiveFrom: time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC), Rules: newrule.New().Rules()}
+	data, err := EncodeRuleSetVersion(v)
+	if err != nil {
+		t.Fatalf("EncodeRuleSetVersion: %v", err)
+	}
+	roundTripJSON(t, data, DecodeRuleSetVersion, EncodeRuleSetVersion)
+	if !bytes.Contains(data, []byte(`"effectiveFrom": "2025-10-01T00:00:00Z"`)) {
+		t.Errorf("encoded version:\n%.200s", data)
+	}
+	// 不带版本信息的规则集文档可作为普通规则集读取，但不能登记为一版规则
+	plain, _ := EncodeRuleSet(v.Rules)
+	if _, err := DecodeRuleSetVersion(plain); err == nil || !strings.Contains(err.Error(), "缺少 version") {
+		t.Errorf("err = %v, want missing version", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "2025-10.json"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	registry := newrule.NewRuleRegistry()
+	if err := LoadRuleVersions(dir, registry); err != nil {
+		t.Fatalf("LoadRuleVersions: %v", err)
+	}
+	if got, err := registry.At(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)); err != nil || got.GameVersion != "6.1" {
+		t.Errorf("At = %+v, %v", got.Version, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "plain.json"), plain, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadRuleVersions(dir, newrule.NewRuleRegistry()); err == nil || !strings.Contains(err.Error(), "plain.json") {
+		t.Errorf("err = %v, want error naming plain.json", err)
+	}
+}
+
+func TestValuationRoundTrip(t *testing.T) {
+	report := newrule.New().Evaluate(sampleAccount)
+	data, err := EncodeValuation(sampleAccount, report)
+	if err != nil {
+		t.Fatalf("EncodeValuation: %v", err)
+	}
+	account, got, err := DecodeValuation(data)
+	if err != nil {
+		t.Fatalf("DecodeValuation: %v", err)
+	}
+	if !reflect.DeepEqual(account, sampleAccount) || !reflect.DeepEqual(got, report) {
+		t.Errorf("round trip changed the valuation:\n%+v\nwant\n%+v", got, report)
+	}
+	if len(report.Combos) == 0 || report.FinalTotal != newrule.New().CalculateValuation(sampleAccount).FinalTotal {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestDecode_Version(t *testing.T) {
+	tests := []struct {
+		name, data, want string
+	}{
+		{"缺少 schema", `{"account": {"characters": {}}}`, "缺少 schema"},
+		{"版本不符", `{"schema": "genshin-value-rule/account/v0", "account": {}}`, "不支持的 schema 版本"},
+		{"文档类型不符", `{"schema": "` + RuleSetV1 + `", "rules": {}}`, "不支持的 schema 版本"},
+		{"非 JSON", `account`, "解析文档失败"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeAccount([]byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+
+	bad := `{"schema": "` + RuleSetV1 + `", "rules": {"resourceValueMode": "unknown"}}`
+	if _, err := DecodeRuleSet([]byte(bad)); err == nil {
+		t.Error("expected error for unknown enum name")
+	}
+}
+
+// TestSchemaDocsUpToDate 确保 docs/schema 与类型一致，类型变更后需运行 go generate ./pkg/schema
+func TestSchemaDocsUpToDate(t *testing.T) {
+	for _, d := range Documents {
+		want, err := Generate(d)
+		if err != nil {
+			t.Fatalf("Generate(%s): %v", d.File, err)
+		}
+		got, err := os.ReadFile(filepath.Join("..", "..", "docs", "schema", d.File))
+		if err != nil {
+			t.Fatalf("read %s: %v", d.File, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("docs/schema/%s 已过期，请运行 go generate ./pkg/schema", d.File)
+		}
+	}
+}
+
+func TestGenerate_Required(t *testing.T) {
+	data, err := Generate(Documents[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Required   []string                   `json:"required"`
+		Properties map[string]json.RawMessage `json:"properties"`
+		Defs       map[string]struct {
+			Required []string `json:"required"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(doc.Required, []string{"schema", "account"}) {
+		t.Errorf("required = %v", doc.Required)
+	}
+	if string(doc.Properties["schema"]) == "" || !strings.Contains(string(doc.Properties["schema"]), AccountV1) {
+		t.Errorf("schema property = %s", doc.Properties["schema"])
+	}
+	if !reflect.DeepEqual(doc.Defs["Assets"].Required, []string{"characters", "weapons"}) {
+		t.Errorf("Assets required = %v", doc.Defs["Assets"].Required)
+	}
+}